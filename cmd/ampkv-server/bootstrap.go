@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/Unfield/AmpKV/internal/auth"
+	"go.uber.org/zap"
+)
+
+// bootstrapTokenEnv provides the first admin token without writing it to a
+// file. It is deliberately not a flag so the token does not show up in the
+// process list.
+const bootstrapTokenEnv = "AMPKV_AUTH_BOOTSTRAP_TOKEN"
+
+// bootstrapAdminKey creates the first admin key on a fresh server. The token
+// comes from the environment, from tokenFile if it exists, or is generated;
+// generated tokens are written to tokenFile or, failing that, printed once.
+func bootstrapAdminKey(manager *auth.ApiKeyManager, tokenFile string, appLogger *zap.Logger) error {
+	token := os.Getenv(bootstrapTokenEnv)
+	tokenFromFile := false
+	if token == "" && tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to read bootstrap token: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+		tokenFromFile = err == nil
+	}
+
+	apiKey, err := manager.BootstrapAdminKey(token)
+	if err != nil {
+		return err
+	}
+	if apiKey == nil {
+		return nil
+	}
+
+	appLogger.Info("Created bootstrap admin api key", zap.String("id", apiKey.ID))
+	if token != "" {
+		return nil
+	}
+
+	if tokenFile != "" && !tokenFromFile {
+		err := writeBootstrapToken(tokenFile, apiKey.Key)
+		if err == nil {
+			appLogger.Info("Bootstrap admin token written", zap.String("file", tokenFile))
+			return nil
+		}
+		appLogger.Error("Failed to write bootstrap admin token, printing it instead", zap.Error(err))
+	}
+
+	fmt.Fprintf(os.Stdout, "\nBootstrap admin api key, it will not be shown again:\n\n    %s\n\n", apiKey.Key)
+	return nil
+}
+
+func writeBootstrapToken(path, token string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(file, token); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
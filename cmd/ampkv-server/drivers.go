@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	_ "github.com/Unfield/AmpKV/drivers/cache/ristretto"
+	_ "github.com/Unfield/AmpKV/drivers/store/badger"
+	_ "github.com/Unfield/AmpKV/drivers/store/sqlite"
+	"github.com/Unfield/AmpKV/internal/config"
+	"github.com/Unfield/AmpKV/internal/storage"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+)
+
+// openDrivers creates the cache and store required by mode. Drivers that the
+// mode does not use are left nil.
+func openDrivers(mode embedded.AmpKVStorageMode, cfg config.StorageConfig) (storage.ICache, storage.KVStore, error) {
+	var (
+		cache storage.ICache
+		store storage.KVStore
+		err   error
+	)
+
+	if mode != embedded.AmpKVStorageModeStoreOnly {
+		cache, err = storage.OpenCache(cfg.CacheDriver, cfg.Drivers[cfg.CacheDriver])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to initialize %s cache: %w", cfg.CacheDriver, err)
+		}
+	}
+
+	if mode != embedded.AmpKVStorageModeCacheOnly {
+		store, err = storage.OpenStore(cfg.StoreDriver, cfg.Drivers[cfg.StoreDriver])
+		if err != nil {
+			if cache != nil {
+				cache.Close()
+			}
+			return nil, nil, fmt.Errorf("failed to initialize %s store: %w", cfg.StoreDriver, err)
+		}
+	}
+
+	return cache, store, nil
+}
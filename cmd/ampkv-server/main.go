@@ -1,59 +1,79 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
-	"github.com/Unfield/AmpKV/drivers/cache/ristretto"
-	"github.com/Unfield/AmpKV/drivers/store/badger"
+	"github.com/Unfield/AmpKV/internal/audit"
 	"github.com/Unfield/AmpKV/internal/auth"
+	"github.com/Unfield/AmpKV/internal/config"
 	"github.com/Unfield/AmpKV/internal/logger"
 	"github.com/Unfield/AmpKV/internal/server"
 	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
 	"github.com/Unfield/AmpKV/pkg/embedded"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 )
 
 func main() {
 	var (
-		grpcPort = flag.Int("grpc-port", 50051, "The gRPC server port")
-		dbPath   = flag.String("db-path", "ampkv_server.db", "Path to the AmpKV Embedded DB file")
-		httpMode = flag.String("http-mode", "http", "Http/Https mode for the Http Server")
+		configPath  = flag.String("config", os.Getenv("AMPKV_CONFIG"), "Path to a YAML config file")
+		printConfig = flag.Bool("print-config", false, "Print the resolved configuration and exit")
 	)
+	configFlags := config.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
-	logger.InitLogger()
+	cfg, err := config.Resolve(*configPath, os.Environ(), configFlags)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *printConfig {
+		out, err := cfg.YAML()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(out)
+		return
+	}
+
+	if err := logger.Configure(cfg.Log.Level, cfg.Log.Format); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 	defer logger.GetLogger().Sync()
 
 	appLogger := logger.GetLogger()
 
 	appLogger.Info("Starting AmpKV...")
 
-	ampkvCache, err := ristretto.NewRistrettoCache(1e7, 1<<30, 64)
-	if err != nil {
-		appLogger.Fatal("failed to initialize ristretto cache")
-	}
-
-	if dbPath == nil {
-		appLogger.Fatal("db-path must not be empty")
-	}
+	// Validated by config.Resolve.
+	mode, _ := embedded.ParseAmpKVStorageMode(cfg.Storage.Mode)
 
-	ampkvStore, err := badger.NewBadgerStore(*dbPath)
+	ampkvCache, ampkvStore, err := openDrivers(mode, cfg.Storage)
 	if err != nil {
-		appLogger.Fatal("failed to initialize badger store")
+		appLogger.Fatal("failed to initialize storage drivers", zap.Error(err))
 	}
 
-	ampkvEmbedded, err := embedded.NewAmpKV(ampkvCache, ampkvStore, embedded.AmpKVOptions{DefaultTTL: 10 * time.Minute})
+	ampkvEmbedded, err := embedded.NewAmpKV(ampkvCache, ampkvStore, embedded.AmpKVOptions{DefaultTTL: cfg.Storage.DefaultTTL, Mode: mode})
 	if err != nil {
-		appLogger.Fatal("failed to initialize AmpKV embedded")
+		appLogger.Fatal("failed to initialize AmpKV embedded", zap.Error(err))
 	}
+	appLogger.Info("Storage initialized",
+		zap.String("mode", mode.ToString()),
+		zap.String("cache-driver", cfg.Storage.CacheDriver),
+		zap.String("store-driver", cfg.Storage.StoreDriver))
 	defer func() {
 		err := ampkvEmbedded.Close()
 		if err != nil {
@@ -65,18 +85,86 @@ func main() {
 
 	grpcServerImpl := server.NewAmpKVGrpcServer(ampkvEmbedded)
 
-	lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", *grpcPort))
+	lis, err := net.Listen("tcp", net.JoinHostPort(cfg.GRPC.Address, strconv.Itoa(cfg.GRPC.Port)))
 	if err != nil {
 		appLogger.Fatal("Failed to listen", zap.Error(err))
 	}
 
-	apiKeyManager, err := auth.NewApiKeyManager(ampkvEmbedded)
-	if err != nil {
-		appLogger.Fatal("Failed to initialize api key manager", zap.Error(err))
+	var (
+		apiKeyManager *auth.ApiKeyManager
+		auditLog      *audit.Log
+		grpcOptions   []grpc.ServerOption
+	)
+	if cfg.Auth.Enabled {
+		secret, err := auth.LoadOrCreateSecret(cfg.Auth.SecretFile)
+		if err != nil {
+			appLogger.Fatal("Failed to load auth secret", zap.Error(err))
+		}
+		apiKeyManager, err = auth.NewApiKeyManager(ampkvEmbedded, secret)
+		if err != nil {
+			appLogger.Fatal("Failed to initialize api key manager", zap.Error(err))
+		}
+		migrated, err := apiKeyManager.MigrateLegacyKeys()
+		if err != nil {
+			appLogger.Fatal("Failed to migrate plaintext api keys", zap.Error(err), zap.Int("migrated", migrated))
+		}
+		if migrated > 0 {
+			appLogger.Info("Migrated plaintext api keys to hashed records", zap.Int("count", migrated))
+		}
+		if err := bootstrapAdminKey(apiKeyManager, cfg.Auth.BootstrapTokenFile, appLogger); err != nil {
+			appLogger.Fatal("Failed to bootstrap admin api key", zap.Error(err))
+		}
+		if cfg.Auth.JWT.Enabled() {
+			keySet, err := loadJWKS(cfg.Auth.JWT, appLogger)
+			if err != nil {
+				appLogger.Fatal("Failed to load jwks", zap.Error(err))
+			}
+			apiKeyManager.AddAuthenticator(auth.NewJWTAuthenticator(keySet, auth.JWTOptions{
+				Issuer:           cfg.Auth.JWT.Issuer,
+				Audience:         cfg.Auth.JWT.Audience,
+				PermissionsClaim: cfg.Auth.JWT.PermissionsClaim,
+				ScopesClaim:      cfg.Auth.JWT.ScopesClaim,
+				Leeway:           cfg.Auth.JWT.Leeway,
+			}))
+			appLogger.Info("JWT authentication enabled", zap.String("issuer", cfg.Auth.JWT.Issuer))
+		}
+		if cfg.Audit.Enabled {
+			auditLog = audit.NewLog(ampkvEmbedded, audit.Options{
+				Retention: cfg.Audit.Retention,
+				Reads:     cfg.Audit.Reads,
+				OnError: func(err error) {
+					appLogger.Error("Failed to write audit log", zap.Error(err))
+				},
+			})
+			appLogger.Info("Audit log enabled", zap.Duration("retention", cfg.Audit.Retention))
+		}
+		grpcOptions = append(grpcOptions,
+			grpc.UnaryInterceptor(server.AuthUnaryServerInterceptor(apiKeyManager, auditLog)),
+			grpc.StreamInterceptor(server.AuthStreamServerInterceptor(apiKeyManager, auditLog, cfg.Auth.StreamRevalidateInterval)))
+	} else {
+		appLogger.Warn("Authentication is disabled, every client has full access")
+	}
+
+	var certReloader *server.CertReloader
+	if cfg.TLS.Enabled() {
+		certReloader, err = server.NewCertReloader(server.TLSOptions{
+			CertFile:          cfg.TLS.CertFile,
+			KeyFile:           cfg.TLS.KeyFile,
+			ClientCAFile:      cfg.TLS.ClientCAFile,
+			RequireClientCert: cfg.TLS.ClientAuth == "require",
+		})
+		if err != nil {
+			appLogger.Fatal("Failed to load TLS certificates", zap.Error(err))
+		}
+		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(certReloader.ServerConfig())))
+		appLogger.Info("TLS enabled", zap.String("cert", cfg.TLS.CertFile), zap.Bool("mtls", cfg.TLS.ClientCAFile != ""))
 	}
 
-	s := grpc.NewServer(grpc.UnaryInterceptor(server.AuthUnaryServerInterceptor(apiKeyManager)))
+	s := grpc.NewServer(grpcOptions...)
 	pb.RegisterAmpKVServiceServer(s, grpcServerImpl)
+	if apiKeyManager != nil {
+		pb.RegisterAdminServiceServer(s, server.NewAmpKVAdminGrpcServer(apiKeyManager, auditLog))
+	}
 
 	reflection.Register(s)
 
@@ -87,20 +175,85 @@ func main() {
 		}
 	}()
 
-	httpServerImpl := server.NewAmpKVHttpServer(ampkvEmbedded, apiKeyManager)
+	httpServerImpl := server.NewAmpKVHttpServer(ampkvEmbedded, apiKeyManager, auditLog)
+
+	switch {
+	case cfg.HTTP.Mode == "https" && certReloader != nil:
+		httpServerImpl.ListenTLS(cfg.HTTP.Address, uint16(cfg.HTTP.Port), certReloader.ServerConfig("h2", "http/1.1"))
+	case cfg.HTTP.Mode == "https":
+		httpServerImpl.ListenAutoTLS(cfg.HTTP.Address, uint16(cfg.HTTP.Port), cfg.HTTP.AutocertCacheDir)
+	default:
+		httpServerImpl.Listen(cfg.HTTP.Address, uint16(cfg.HTTP.Port))
+	}
+
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	if certReloader != nil && cfg.TLS.ReloadInterval > 0 {
+		go certReloader.Watch(watchCtx, cfg.TLS.ReloadInterval, func(err error) {
+			logCertReload(appLogger, "file change", err)
+		})
+	}
 
-	if *httpMode == "https" {
-		httpServerImpl.ListenAutoTLS("0.0.0.0", 4443)
+	// The usage flusher outlives the servers so that it persists the usage
+	// of the last requests.
+	usageCtx, stopUsage := context.WithCancel(context.Background())
+	defer stopUsage()
+	usageFlushed := make(chan struct{})
+	if apiKeyManager != nil {
+		go func() {
+			defer close(usageFlushed)
+			apiKeyManager.RunUsageFlusher(usageCtx, cfg.Auth.UsageFlushInterval, func(err error) {
+				appLogger.Error("Failed to persist api key usage", zap.Error(err))
+			})
+		}()
 	} else {
-		httpServerImpl.Listen("0.0.0.0", 8080)
+		close(usageFlushed)
 	}
 
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
 	sig := <-sigChan
+	for sig == syscall.SIGHUP {
+		if certReloader != nil {
+			logCertReload(appLogger, "SIGHUP", certReloader.Reload())
+		}
+		sig = <-sigChan
+	}
 	appLogger.Info("Shutting down", zap.String("signal", sig.String()))
+	stopWatch()
 	s.GracefulStop()
 	appLogger.Info("gRPC server stopped")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServerImpl.Shutdown(shutdownCtx); err != nil {
+		appLogger.Error("failed to stop Http server", zap.Error(err))
+	}
+	appLogger.Info("Http server stopped")
+	stopUsage()
+	<-usageFlushed
+	auditLog.Close()
 	appLogger.Info("AmpKV server exited")
 }
+
+func logCertReload(appLogger *zap.Logger, trigger string, err error) {
+	if err != nil {
+		appLogger.Error("Failed to reload TLS certificates, keeping the previous ones", zap.String("trigger", trigger), zap.Error(err))
+		return
+	}
+	appLogger.Info("TLS certificates reloaded", zap.String("trigger", trigger))
+}
+
+// loadJWKS opens the configured key set. A JWKS URL that can not be fetched
+// yet is only logged; it is fetched again on the next request.
+func loadJWKS(cfg config.JWTConfig, appLogger *zap.Logger) (*auth.JWKS, error) {
+	if cfg.JWKSFile != "" {
+		return auth.NewJWKSFile(cfg.JWKSFile, cfg.JWKSRefreshInterval)
+	}
+	keySet := auth.NewJWKSURL(cfg.JWKSURL, cfg.JWKSRefreshInterval)
+	if err := keySet.Reload(); err != nil {
+		appLogger.Warn("Failed to fetch jwks, retrying on the next request", zap.String("url", cfg.JWKSURL), zap.Error(err))
+	}
+	return keySet, nil
+}
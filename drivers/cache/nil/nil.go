@@ -2,6 +2,8 @@ package nil
 
 import (
 	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
 )
 
 type NilCache struct {
@@ -11,8 +13,8 @@ func NewNilCache() (*NilCache, error) {
 	return &NilCache{}, nil
 }
 
-func (r *NilCache) Get(key string) ([]byte, bool) {
-	return nil, false
+func (r *NilCache) Get(key string) ([]byte, error) {
+	return nil, storage.ErrNotFound
 }
 
 func (r *NilCache) Set(key string, value []byte, cost int64) error {
@@ -23,7 +25,12 @@ func (r *NilCache) SetWithTTL(key string, value []byte, cost int64, ttl time.Dur
 	return nil
 }
 
-func (r *NilCache) Delete(key string) {
+func (r *NilCache) SetMany(entries []storage.Entry) error {
+	return nil
+}
+
+func (r *NilCache) Delete(key string) error {
+	return nil
 }
 
 func (r *NilCache) Close() error {
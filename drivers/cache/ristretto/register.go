@@ -0,0 +1,29 @@
+package ristretto
+
+import "github.com/Unfield/AmpKV/internal/storage"
+
+func init() {
+	storage.RegisterCache(storage.CacheDriver{
+		Name: "ristretto",
+		Options: []storage.DriverOption{
+			{Name: "num-counters", Default: "10000000", Usage: "Number of keys to track access frequency for"},
+			{Name: "max-cost", Default: "1073741824", Usage: "Maximum total cost of the cached entries"},
+			{Name: "buffer-items", Default: "64", Usage: "Number of keys per Get buffer"},
+		},
+		New: func(options storage.DriverOptions) (storage.ICache, error) {
+			numCounters, err := options.Int64("num-counters")
+			if err != nil {
+				return nil, err
+			}
+			maxCost, err := options.Int64("max-cost")
+			if err != nil {
+				return nil, err
+			}
+			bufferItems, err := options.Int64("buffer-items")
+			if err != nil {
+				return nil, err
+			}
+			return NewRistrettoCache(numCounters, maxCost, bufferItems)
+		},
+	})
+}
@@ -2,13 +2,16 @@ package ristretto
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/Unfield/AmpKV/internal/storage"
 	"github.com/dgraph-io/ristretto/v2"
 )
 
 type RistrettoCache struct {
-	cache *ristretto.Cache[string, []byte]
+	cache  *ristretto.Cache[string, []byte]
+	closed atomic.Bool
 }
 
 func NewRistrettoCache(NumCounters, MaxCost, BufferItems int64) (*RistrettoCache, error) {
@@ -22,15 +25,25 @@ func NewRistrettoCache(NumCounters, MaxCost, BufferItems int64) (*RistrettoCache
 	}
 
 	return &RistrettoCache{
-		cache,
+		cache: cache,
 	}, nil
 }
 
-func (r *RistrettoCache) Get(key string) ([]byte, bool) {
-	return r.cache.Get(key)
+func (r *RistrettoCache) Get(key string) ([]byte, error) {
+	if r.closed.Load() {
+		return nil, storage.ErrClosed
+	}
+	value, found := r.cache.Get(key)
+	if !found {
+		return nil, storage.ErrNotFound
+	}
+	return value, nil
 }
 
 func (r *RistrettoCache) Set(key string, value []byte, cost int64) error {
+	if r.closed.Load() {
+		return storage.ErrClosed
+	}
 	result := r.cache.Set(key, value, cost)
 	r.cache.Wait()
 	if !result {
@@ -40,6 +53,9 @@ func (r *RistrettoCache) Set(key string, value []byte, cost int64) error {
 }
 
 func (r *RistrettoCache) SetWithTTL(key string, value []byte, cost int64, ttl time.Duration) error {
+	if r.closed.Load() {
+		return storage.ErrClosed
+	}
 	result := r.cache.SetWithTTL(key, value, cost, ttl)
 	r.cache.Wait()
 	if !result {
@@ -48,11 +64,37 @@ func (r *RistrettoCache) SetWithTTL(key string, value []byte, cost int64, ttl ti
 	return nil
 }
 
-func (r *RistrettoCache) Delete(key string) {
+func (r *RistrettoCache) SetMany(entries []storage.Entry) error {
+	if r.closed.Load() {
+		return storage.ErrClosed
+	}
+
+	rejected := 0
+	for _, entry := range entries {
+		if !r.cache.SetWithTTL(entry.Key, entry.Value, entry.Cost, entry.TTL) {
+			rejected++
+		}
+	}
+	r.cache.Wait()
+
+	if rejected > 0 {
+		return fmt.Errorf("Failed to set %d of %d keys", rejected, len(entries))
+	}
+	return nil
+}
+
+func (r *RistrettoCache) Delete(key string) error {
+	if r.closed.Load() {
+		return storage.ErrClosed
+	}
 	r.cache.Del(key)
+	return nil
 }
 
 func (r *RistrettoCache) Close() error {
+	if r.closed.Swap(true) {
+		return nil
+	}
 	r.cache.Close()
 	return nil
 }
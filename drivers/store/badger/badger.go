@@ -1,9 +1,11 @@
 package badger
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/Unfield/AmpKV/internal/storage"
 	"github.com/dgraph-io/badger/v4"
 )
 
@@ -21,59 +23,94 @@ func NewBadgerStore(path string) (*BadgerStore, error) {
 	}, nil
 }
 
-func (s *BadgerStore) Get(key string) ([]byte, bool) {
+func (s *BadgerStore) Get(key string) ([]byte, error) {
+	if s.badger.IsClosed() {
+		return nil, storage.ErrClosed
+	}
+
 	var value []byte
 	err := s.badger.View(
 		func(tx *badger.Txn) error {
 			item, err := tx.Get([]byte(key))
 			if err != nil {
-				if err == badger.ErrKeyNotFound {
-					return badger.ErrKeyNotFound
-				}
-				return fmt.Errorf("Failed to get value from Badger: %w", err)
+				return err
 			}
 			value, err = item.ValueCopy(nil)
+			return err
+		})
+	if err != nil {
+		return nil, wrapBadgerError("Failed to get value from Badger", err)
+	}
+	return value, nil
+}
+
+// ExpiresAt returns when the entry of key expires, with the second precision
+// Badger keeps, or the zero time if it has no TTL.
+func (s *BadgerStore) ExpiresAt(key string) (time.Time, error) {
+	if s.badger.IsClosed() {
+		return time.Time{}, storage.ErrClosed
+	}
+
+	var expiresAt uint64
+	err := s.badger.View(
+		func(tx *badger.Txn) error {
+			item, err := tx.Get([]byte(key))
 			if err != nil {
-				return fmt.Errorf("Failed to copy value from Badger: %w", err)
+				return err
 			}
+			expiresAt = item.ExpiresAt()
 			return nil
 		})
-
-	if err == badger.ErrKeyNotFound {
-		return nil, false
-	} else if err != nil {
-		fmt.Printf("BadgerStore.Get unexpected error for key %s: %v\n", key, err)
-		return nil, false
-	} else {
-		return value, true
+	if err != nil {
+		return time.Time{}, wrapBadgerError("Failed to get expiry from Badger", err)
 	}
+	if expiresAt == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(int64(expiresAt), 0), nil
 }
 
 func (s *BadgerStore) Set(key string, value []byte, cost int64) error {
+	if s.badger.IsClosed() {
+		return storage.ErrClosed
+	}
+
 	err := s.badger.Update(func(txn *badger.Txn) error {
 		return txn.Set([]byte(key), value)
 	})
 	if err != nil {
-		return fmt.Errorf("Failed to set key/value pair to Badger: %w", err)
+		return wrapBadgerError("Failed to set key/value pair to Badger", err)
 	}
 	return nil
 }
 
 func (s *BadgerStore) SetWithTTL(key string, value []byte, cost int64, ttl time.Duration) error {
+	if s.badger.IsClosed() {
+		return storage.ErrClosed
+	}
+
 	e := badger.NewEntry([]byte(key), value).WithTTL(ttl)
 	err := s.badger.Update(func(txn *badger.Txn) error {
 		return txn.SetEntry(e)
 	})
 	if err != nil {
-		return fmt.Errorf("Failed to set key/value pair to Badger: %w", err)
+		return wrapBadgerError("Failed to set key/value pair to Badger", err)
 	}
 	return nil
 }
 
-func (s *BadgerStore) Delete(key string) {
-	s.badger.Update(func(txn *badger.Txn) error {
+func (s *BadgerStore) Delete(key string) error {
+	if s.badger.IsClosed() {
+		return storage.ErrClosed
+	}
+
+	err := s.badger.Update(func(txn *badger.Txn) error {
 		return txn.Delete([]byte(key))
 	})
+	if err != nil {
+		return wrapBadgerError("Failed to delete key from Badger", err)
+	}
+	return nil
 }
 
 func (s *BadgerStore) Close() error {
@@ -87,3 +124,16 @@ func (s *BadgerStore) Close() error {
 func (r *BadgerStore) IsNil() bool {
 	return false
 }
+
+func wrapBadgerError(msg string, err error) error {
+	switch {
+	case errors.Is(err, badger.ErrKeyNotFound):
+		return storage.ErrNotFound
+	case errors.Is(err, badger.ErrConflict):
+		return storage.NewStorageErrorWithCause(storage.Conflict, msg, err)
+	case errors.Is(err, badger.ErrDBClosed), errors.Is(err, badger.ErrBlockedWrites):
+		return storage.NewStorageErrorWithCause(storage.Closed, msg, err)
+	default:
+		return storage.NewStorageErrorWithCause(storage.BackendFailure, msg, err)
+	}
+}
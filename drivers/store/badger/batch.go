@@ -0,0 +1,81 @@
+package badger
+
+import (
+	"errors"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	"github.com/dgraph-io/badger/v4"
+)
+
+func (s *BadgerStore) GetMany(keys []string) (map[string][]byte, error) {
+	if s.badger.IsClosed() {
+		return nil, storage.ErrClosed
+	}
+
+	values := make(map[string][]byte, len(keys))
+	err := s.badger.View(func(txn *badger.Txn) error {
+		for _, key := range keys {
+			item, err := txn.Get([]byte(key))
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			values[key] = value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, wrapBadgerError("Failed to get values from Badger", err)
+	}
+	return values, nil
+}
+
+func (s *BadgerStore) SetMany(entries []storage.Entry) error {
+	if s.badger.IsClosed() {
+		return storage.ErrClosed
+	}
+
+	wb := s.badger.NewWriteBatch()
+	defer wb.Cancel()
+
+	for _, entry := range entries {
+		e := badger.NewEntry([]byte(entry.Key), entry.Value)
+		if entry.TTL > 0 {
+			e = e.WithTTL(entry.TTL)
+		}
+		if err := wb.SetEntry(e); err != nil {
+			return wrapBadgerError("Failed to add entry to Badger write batch", err)
+		}
+	}
+
+	if err := wb.Flush(); err != nil {
+		return wrapBadgerError("Failed to flush Badger write batch", err)
+	}
+	return nil
+}
+
+func (s *BadgerStore) DeleteMany(keys []string) error {
+	if s.badger.IsClosed() {
+		return storage.ErrClosed
+	}
+
+	wb := s.badger.NewWriteBatch()
+	defer wb.Cancel()
+
+	for _, key := range keys {
+		if err := wb.Delete([]byte(key)); err != nil {
+			return wrapBadgerError("Failed to add delete to Badger write batch", err)
+		}
+	}
+
+	if err := wb.Flush(); err != nil {
+		return wrapBadgerError("Failed to flush Badger write batch", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package badger
+
+import (
+	"github.com/Unfield/AmpKV/internal/storage"
+	"github.com/dgraph-io/badger/v4"
+)
+
+type BadgerIterator struct {
+	txn     *badger.Txn
+	it      *badger.Iterator
+	prefix  []byte
+	seek    []byte
+	started bool
+	key     string
+	value   []byte
+	err     error
+}
+
+func (s *BadgerStore) Iterate(options storage.IteratorOptions) (storage.Iterator, error) {
+	if s.badger.IsClosed() {
+		return nil, storage.ErrClosed
+	}
+
+	prefix := []byte(options.Prefix)
+	seek := prefix
+	if options.Start > options.Prefix {
+		seek = []byte(options.Start)
+	}
+
+	txn := s.badger.NewTransaction(false)
+	iteratorOptions := badger.DefaultIteratorOptions
+	iteratorOptions.Prefix = prefix
+
+	return &BadgerIterator{
+		txn:    txn,
+		it:     txn.NewIterator(iteratorOptions),
+		prefix: prefix,
+		seek:   seek,
+	}, nil
+}
+
+func (i *BadgerIterator) Next() bool {
+	if i.err != nil {
+		return false
+	}
+
+	if !i.started {
+		i.it.Seek(i.seek)
+		i.started = true
+	} else {
+		i.it.Next()
+	}
+
+	if !i.it.ValidForPrefix(i.prefix) {
+		return false
+	}
+
+	item := i.it.Item()
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		i.err = wrapBadgerError("Failed to copy value from Badger", err)
+		return false
+	}
+
+	i.key = string(item.KeyCopy(nil))
+	i.value = value
+	return true
+}
+
+func (i *BadgerIterator) Key() string {
+	return i.key
+}
+
+func (i *BadgerIterator) Value() []byte {
+	return i.value
+}
+
+func (i *BadgerIterator) Err() error {
+	return i.err
+}
+
+func (i *BadgerIterator) Close() error {
+	i.it.Close()
+	i.txn.Discard()
+	return nil
+}
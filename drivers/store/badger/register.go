@@ -0,0 +1,15 @@
+package badger
+
+import "github.com/Unfield/AmpKV/internal/storage"
+
+func init() {
+	storage.RegisterStore(storage.StoreDriver{
+		Name: "badger",
+		Options: []storage.DriverOption{
+			{Name: "path", Default: "ampkv_server.db", Usage: "Directory of the Badger database"},
+		},
+		New: func(options storage.DriverOptions) (storage.KVStore, error) {
+			return NewBadgerStore(options.String("path"))
+		},
+	})
+}
@@ -0,0 +1,75 @@
+package badger
+
+import (
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	"github.com/dgraph-io/badger/v4"
+)
+
+type BadgerTxn struct {
+	txn *badger.Txn
+}
+
+func (s *BadgerStore) Update(fn func(txn storage.Txn) error) error {
+	if s.badger.IsClosed() {
+		return storage.ErrClosed
+	}
+
+	txn := s.badger.NewTransaction(true)
+	defer txn.Discard()
+
+	if err := fn(&BadgerTxn{txn: txn}); err != nil {
+		return err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return wrapBadgerError("Failed to commit Badger transaction", err)
+	}
+	return nil
+}
+
+func (s *BadgerStore) View(fn func(txn storage.Txn) error) error {
+	if s.badger.IsClosed() {
+		return storage.ErrClosed
+	}
+
+	txn := s.badger.NewTransaction(false)
+	defer txn.Discard()
+
+	return fn(&BadgerTxn{txn: txn})
+}
+
+func (t *BadgerTxn) Get(key string) ([]byte, error) {
+	item, err := t.txn.Get([]byte(key))
+	if err != nil {
+		return nil, wrapBadgerError("Failed to get value from Badger", err)
+	}
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, wrapBadgerError("Failed to copy value from Badger", err)
+	}
+	return value, nil
+}
+
+func (t *BadgerTxn) Set(key string, value []byte) error {
+	if err := t.txn.Set([]byte(key), value); err != nil {
+		return wrapBadgerError("Failed to set key/value pair in Badger transaction", err)
+	}
+	return nil
+}
+
+func (t *BadgerTxn) SetWithTTL(key string, value []byte, ttl time.Duration) error {
+	e := badger.NewEntry([]byte(key), value).WithTTL(ttl)
+	if err := t.txn.SetEntry(e); err != nil {
+		return wrapBadgerError("Failed to set key/value pair in Badger transaction", err)
+	}
+	return nil
+}
+
+func (t *BadgerTxn) Delete(key string) error {
+	if err := t.txn.Delete([]byte(key)); err != nil {
+		return wrapBadgerError("Failed to delete key in Badger transaction", err)
+	}
+	return nil
+}
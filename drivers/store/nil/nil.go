@@ -2,6 +2,8 @@ package nil
 
 import (
 	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
 )
 
 type NilStore struct {
@@ -11,8 +13,12 @@ func NewNilStore() (*NilStore, error) {
 	return &NilStore{}, nil
 }
 
-func (s *NilStore) Get(key string) ([]byte, bool) {
-	return nil, false
+func (s *NilStore) Get(key string) ([]byte, error) {
+	return nil, storage.ErrNotFound
+}
+
+func (s *NilStore) GetMany(keys []string) (map[string][]byte, error) {
+	return map[string][]byte{}, nil
 }
 
 func (s *NilStore) Set(key string, value []byte, cost int64) error {
@@ -23,7 +29,28 @@ func (s *NilStore) SetWithTTL(key string, value []byte, cost int64, ttl time.Dur
 	return nil
 }
 
-func (s *NilStore) Delete(key string) {
+func (s *NilStore) SetMany(entries []storage.Entry) error {
+	return nil
+}
+
+func (s *NilStore) Delete(key string) error {
+	return nil
+}
+
+func (s *NilStore) DeleteMany(keys []string) error {
+	return nil
+}
+
+func (s *NilStore) Iterate(options storage.IteratorOptions) (storage.Iterator, error) {
+	return &NilIterator{}, nil
+}
+
+func (s *NilStore) Update(fn func(txn storage.Txn) error) error {
+	return fn(&NilTxn{})
+}
+
+func (s *NilStore) View(fn func(txn storage.Txn) error) error {
+	return fn(&NilTxn{})
 }
 
 func (s *NilStore) Close() error {
@@ -33,3 +60,45 @@ func (s *NilStore) Close() error {
 func (r *NilStore) IsNil() bool {
 	return true
 }
+
+type NilTxn struct {
+}
+
+func (t *NilTxn) Get(key string) ([]byte, error) {
+	return nil, storage.ErrNotFound
+}
+
+func (t *NilTxn) Set(key string, value []byte) error {
+	return nil
+}
+
+func (t *NilTxn) SetWithTTL(key string, value []byte, ttl time.Duration) error {
+	return nil
+}
+
+func (t *NilTxn) Delete(key string) error {
+	return nil
+}
+
+type NilIterator struct {
+}
+
+func (i *NilIterator) Next() bool {
+	return false
+}
+
+func (i *NilIterator) Key() string {
+	return ""
+}
+
+func (i *NilIterator) Value() []byte {
+	return nil
+}
+
+func (i *NilIterator) Err() error {
+	return nil
+}
+
+func (i *NilIterator) Close() error {
+	return nil
+}
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+)
+
+// Stay well below SQLite's limit on bound parameters per statement.
+const getManyChunkSize = 500
+
+func (s *SQLiteStore) GetMany(keys []string) (map[string][]byte, error) {
+	if s.closed.Load() {
+		return nil, storage.ErrClosed
+	}
+
+	values := make(map[string][]byte, len(keys))
+	now := nowMillis()
+	for start := 0; start < len(keys); start += getManyChunkSize {
+		chunk := keys[start:min(start+getManyChunkSize, len(keys))]
+
+		args := make([]any, 0, len(chunk)+1)
+		for _, key := range chunk {
+			args = append(args, key)
+		}
+		args = append(args, now)
+
+		query := `SELECT key, value FROM kv WHERE key IN (?` + strings.Repeat(", ?", len(chunk)-1) + `) AND ` + liveCondition
+		rows, err := s.db.Query(query, args...)
+		if err != nil {
+			return nil, wrapSQLiteError("Failed to get values from SQLite", err)
+		}
+		for rows.Next() {
+			var (
+				key   string
+				value []byte
+			)
+			if err := rows.Scan(&key, &value); err != nil {
+				rows.Close()
+				return nil, wrapSQLiteError("Failed to get values from SQLite", err)
+			}
+			values[key] = value
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, wrapSQLiteError("Failed to get values from SQLite", err)
+		}
+	}
+	return values, nil
+}
+
+func (s *SQLiteStore) SetMany(entries []storage.Entry) error {
+	if s.closed.Load() {
+		return storage.ErrClosed
+	}
+
+	tx, err := s.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return wrapSQLiteError("Failed to begin SQLite batch", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(upsertQuery)
+	if err != nil {
+		return wrapSQLiteError("Failed to prepare SQLite batch", err)
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		if _, err := stmt.Exec(entry.Key, entry.Value, expiresAt(entry.TTL)); err != nil {
+			return wrapSQLiteError("Failed to add entry to SQLite batch", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return wrapSQLiteError("Failed to commit SQLite batch", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DeleteMany(keys []string) error {
+	if s.closed.Load() {
+		return storage.ErrClosed
+	}
+
+	tx, err := s.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return wrapSQLiteError("Failed to begin SQLite batch", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(deleteQuery)
+	if err != nil {
+		return wrapSQLiteError("Failed to prepare SQLite batch", err)
+	}
+	defer stmt.Close()
+
+	for _, key := range keys {
+		if _, err := stmt.Exec(key); err != nil {
+			return wrapSQLiteError("Failed to add delete to SQLite batch", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return wrapSQLiteError("Failed to commit SQLite batch", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"github.com/Unfield/AmpKV/internal/storage"
+)
+
+const iteratorPageSize = 256
+
+// SQLiteIterator reads the range in pages so that no connection or read lock
+// is held between calls to Next. Unlike a Badger iterator it does not see a
+// single snapshot: writes made while iterating may or may not be visited.
+type SQLiteIterator struct {
+	store   *SQLiteStore
+	upper   string
+	after   string
+	started bool
+	page    []iteratorRow
+	pos     int
+	last    bool
+	key     string
+	value   []byte
+	err     error
+}
+
+type iteratorRow struct {
+	key   string
+	value []byte
+}
+
+func (s *SQLiteStore) Iterate(options storage.IteratorOptions) (storage.Iterator, error) {
+	if s.closed.Load() {
+		return nil, storage.ErrClosed
+	}
+
+	seek := options.Prefix
+	if options.Start > options.Prefix {
+		seek = options.Start
+	}
+
+	return &SQLiteIterator{
+		store: s,
+		upper: prefixUpperBound(options.Prefix),
+		after: seek,
+	}, nil
+}
+
+func (i *SQLiteIterator) Next() bool {
+	if i.err != nil {
+		return false
+	}
+
+	if i.pos >= len(i.page) {
+		if i.last || !i.fetch() {
+			return false
+		}
+	}
+
+	row := i.page[i.pos]
+	i.pos++
+	i.key = row.key
+	i.value = row.value
+	return true
+}
+
+// fetch loads the page following the last visited key. The first page starts
+// at the seek key itself.
+func (i *SQLiteIterator) fetch() bool {
+	if i.store.closed.Load() {
+		i.err = storage.ErrClosed
+		return false
+	}
+
+	op := ">"
+	if !i.started {
+		op = ">="
+		i.started = true
+	}
+	query := `SELECT key, value FROM kv WHERE key ` + op + ` ? AND ` + liveCondition
+	args := []any{i.after, nowMillis()}
+	if i.upper != "" {
+		query += ` AND key < ?`
+		args = append(args, i.upper)
+	}
+	query += ` ORDER BY key LIMIT ?`
+	args = append(args, iteratorPageSize)
+
+	rows, err := i.store.db.Query(query, args...)
+	if err != nil {
+		i.err = wrapSQLiteError("Failed to iterate over SQLite", err)
+		return false
+	}
+	defer rows.Close()
+
+	i.page = i.page[:0]
+	i.pos = 0
+	for rows.Next() {
+		var row iteratorRow
+		if err := rows.Scan(&row.key, &row.value); err != nil {
+			i.err = wrapSQLiteError("Failed to read row from SQLite", err)
+			return false
+		}
+		i.page = append(i.page, row)
+	}
+	if err := rows.Err(); err != nil {
+		i.err = wrapSQLiteError("Failed to iterate over SQLite", err)
+		return false
+	}
+
+	i.last = len(i.page) < iteratorPageSize
+	if len(i.page) == 0 {
+		return false
+	}
+	i.after = i.page[len(i.page)-1].key
+	return true
+}
+
+func (i *SQLiteIterator) Key() string {
+	return i.key
+}
+
+func (i *SQLiteIterator) Value() []byte {
+	return i.value
+}
+
+func (i *SQLiteIterator) Err() error {
+	return i.err
+}
+
+func (i *SQLiteIterator) Close() error {
+	i.page = nil
+	return nil
+}
+
+// prefixUpperBound returns the smallest key greater than every key starting
+// with prefix, or "" if there is none.
+func prefixUpperBound(prefix string) string {
+	upper := []byte(prefix)
+	for n := len(upper) - 1; n >= 0; n-- {
+		if upper[n] < 0xff {
+			upper[n]++
+			return string(upper[:n+1])
+		}
+	}
+	return ""
+}
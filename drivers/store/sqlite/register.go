@@ -0,0 +1,20 @@
+package sqlite
+
+import "github.com/Unfield/AmpKV/internal/storage"
+
+func init() {
+	storage.RegisterStore(storage.StoreDriver{
+		Name: "sqlite",
+		Options: []storage.DriverOption{
+			{Name: "path", Default: "ampkv_server.sqlite", Usage: "Path of the SQLite database file"},
+			{Name: "reap-interval", Default: DefaultReapInterval.String(), Usage: "How often expired entries are removed"},
+		},
+		New: func(options storage.DriverOptions) (storage.KVStore, error) {
+			reapInterval, err := options.Duration("reap-interval")
+			if err != nil {
+				return nil, err
+			}
+			return NewSQLiteStore(options.String("path"), reapInterval)
+		},
+	})
+}
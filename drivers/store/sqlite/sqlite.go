@@ -0,0 +1,207 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	sqlitedriver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	DefaultReapInterval = time.Minute
+
+	busyTimeout = 5 * time.Second
+
+	// Keys are TEXT compared with the default BINARY collation, so rows are
+	// ordered byte-wise like in every other store. Expiry is stored as unix
+	// milliseconds; NULL means the entry does not expire.
+	schema = `
+CREATE TABLE IF NOT EXISTS kv (
+	key        TEXT PRIMARY KEY,
+	value      BLOB NOT NULL,
+	expires_at INTEGER
+) WITHOUT ROWID;
+CREATE INDEX IF NOT EXISTS kv_expires_at ON kv (expires_at) WHERE expires_at IS NOT NULL;`
+
+	liveCondition = `(expires_at IS NULL OR expires_at > ?)`
+
+	getQuery    = `SELECT value FROM kv WHERE key = ? AND ` + liveCondition
+	expiryQuery = `SELECT expires_at FROM kv WHERE key = ? AND ` + liveCondition
+	upsertQuery = `INSERT INTO kv (key, value, expires_at) VALUES (?, ?, ?)
+ON CONFLICT (key) DO UPDATE SET value = excluded.value, expires_at = excluded.expires_at`
+	deleteQuery = `DELETE FROM kv WHERE key = ?`
+	reapQuery   = `DELETE FROM kv WHERE expires_at IS NOT NULL AND expires_at <= ?`
+)
+
+type SQLiteStore struct {
+	db     *sql.DB
+	closed atomic.Bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewSQLiteStore opens or creates the database file at path. Expired entries
+// are hidden from reads immediately and removed every reapInterval; zero uses
+// DefaultReapInterval.
+func NewSQLiteStore(path string, reapInterval time.Duration) (*SQLiteStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("Failed to open SQLite: path must not be empty")
+	}
+	if reapInterval <= 0 {
+		reapInterval = DefaultReapInterval
+	}
+
+	// Write transactions take the write lock up front, so concurrent writers
+	// wait for each other instead of failing halfway through.
+	dsn := fmt.Sprintf("%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_txlock=immediate",
+		path, busyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open SQLite: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Failed to create SQLite schema: %w", err)
+	}
+
+	s := &SQLiteStore{
+		db:   db,
+		done: make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.runReaper(reapInterval)
+
+	return s, nil
+}
+
+func (s *SQLiteStore) Get(key string) ([]byte, error) {
+	if s.closed.Load() {
+		return nil, storage.ErrClosed
+	}
+
+	var value []byte
+	err := s.db.QueryRow(getQuery, key, nowMillis()).Scan(&value)
+	if err != nil {
+		return nil, wrapSQLiteError("Failed to get value from SQLite", err)
+	}
+	return value, nil
+}
+
+// ExpiresAt returns when the entry of key expires, or the zero time if it
+// has no TTL.
+func (s *SQLiteStore) ExpiresAt(key string) (time.Time, error) {
+	if s.closed.Load() {
+		return time.Time{}, storage.ErrClosed
+	}
+
+	var expiresAt sql.NullInt64
+	err := s.db.QueryRow(expiryQuery, key, nowMillis()).Scan(&expiresAt)
+	if err != nil {
+		return time.Time{}, wrapSQLiteError("Failed to get expiry from SQLite", err)
+	}
+	if !expiresAt.Valid {
+		return time.Time{}, nil
+	}
+	return time.UnixMilli(expiresAt.Int64), nil
+}
+
+func (s *SQLiteStore) Set(key string, value []byte, cost int64) error {
+	return s.SetWithTTL(key, value, cost, 0)
+}
+
+func (s *SQLiteStore) SetWithTTL(key string, value []byte, cost int64, ttl time.Duration) error {
+	if s.closed.Load() {
+		return storage.ErrClosed
+	}
+
+	if _, err := s.db.Exec(upsertQuery, key, value, expiresAt(ttl)); err != nil {
+		return wrapSQLiteError("Failed to set key/value pair to SQLite", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Delete(key string) error {
+	if s.closed.Load() {
+		return storage.ErrClosed
+	}
+
+	if _, err := s.db.Exec(deleteQuery, key); err != nil {
+		return wrapSQLiteError("Failed to delete key from SQLite", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Close() error {
+	if s.closed.Swap(true) {
+		return nil
+	}
+	close(s.done)
+	s.wg.Wait()
+
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("Failed to close SQLite: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) IsNil() bool {
+	return false
+}
+
+func (s *SQLiteStore) runReaper(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			// A failed sweep is retried on the next tick; expired rows are
+			// invisible to reads either way.
+			s.db.Exec(reapQuery, nowMillis())
+		}
+	}
+}
+
+func nowMillis() int64 {
+	return time.Now().UnixMilli()
+}
+
+func expiresAt(ttl time.Duration) any {
+	if ttl <= 0 {
+		return nil
+	}
+	return time.Now().Add(ttl).UnixMilli()
+}
+
+func wrapSQLiteError(msg string, err error) error {
+	var sqliteErr *sqlitedriver.Error
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return storage.ErrNotFound
+	case errors.Is(err, sql.ErrConnDone):
+		return storage.NewStorageErrorWithCause(storage.Closed, msg, err)
+	case errors.As(err, &sqliteErr) && isBusy(sqliteErr.Code()):
+		return storage.NewStorageErrorWithCause(storage.Conflict, msg, err)
+	default:
+		return storage.NewStorageErrorWithCause(storage.BackendFailure, msg, err)
+	}
+}
+
+func isBusy(code int) bool {
+	switch code & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,175 @@
+package sqlite_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Unfield/AmpKV/drivers/store/sqlite"
+	"github.com/Unfield/AmpKV/internal/storage"
+)
+
+func setupTestStore(t *testing.T) *sqlite.SQLiteStore {
+	store, err := sqlite.NewSQLiteStore(filepath.Join(t.TempDir(), "ampkv.sqlite"), 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to open SQLite store: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("Failed to close SQLite store: %v", err)
+		}
+	})
+	return store
+}
+
+func TestSQLiteStoreOperations(t *testing.T) {
+	store := setupTestStore(t)
+
+	if _, err := store.Get("missing"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	if err := store.Set("key", []byte("value"), 1); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := store.Set("key", []byte("updated"), 1); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	value, err := store.Get("key")
+	if err != nil || string(value) != "updated" {
+		t.Errorf("Expected 'updated', got '%s' (%v)", value, err)
+	}
+
+	if err := store.Delete("key"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Get("key"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+
+	if err := store.SetWithTTL("ttl", []byte("short lived"), 1, 100*time.Millisecond); err != nil {
+		t.Fatalf("SetWithTTL failed: %v", err)
+	}
+	if _, err := store.Get("ttl"); err != nil {
+		t.Errorf("Expected key before expiry, got %v", err)
+	}
+	if expiresAt, err := store.ExpiresAt("ttl"); err != nil || expiresAt.IsZero() || time.Until(expiresAt) > 100*time.Millisecond {
+		t.Errorf("Expected the key to expire within 100ms, got %v (%v)", expiresAt, err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if _, err := store.Get("ttl"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after expiry, got %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := store.Get("key"); !errors.Is(err, storage.ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestSQLiteStoreIterate(t *testing.T) {
+	store := setupTestStore(t)
+
+	var entries []storage.Entry
+	for i := range 600 {
+		entries = append(entries, storage.Entry{Key: fmt.Sprintf("user:%04d", i), Value: []byte("u")})
+	}
+	entries = append(entries,
+		storage.Entry{Key: "user;", Value: []byte("outside")},
+		storage.Entry{Key: "user:expired", Value: []byte("gone"), TTL: time.Millisecond},
+	)
+	if err := store.SetMany(entries); err != nil {
+		t.Fatalf("SetMany failed: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	it, err := store.Iterate(storage.IteratorOptions{Prefix: "user:", Start: "user:0100"})
+	if err != nil {
+		t.Fatalf("Iterate failed: %v", err)
+	}
+	defer it.Close()
+
+	count := 0
+	previous := ""
+	for it.Next() {
+		if it.Key() <= previous {
+			t.Fatalf("Keys out of order: '%s' after '%s'", it.Key(), previous)
+		}
+		previous = it.Key()
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Iterator failed: %v", err)
+	}
+	if count != 500 {
+		t.Errorf("Expected 500 keys from user:0100, got %d", count)
+	}
+
+	values, err := store.GetMany([]string{"user:0001", "user;", "user:expired", "missing"})
+	if err != nil {
+		t.Fatalf("GetMany failed: %v", err)
+	}
+	if len(values) != 2 {
+		t.Errorf("Expected 2 live keys, got %d", len(values))
+	}
+
+	if err := store.DeleteMany([]string{"user:0001", "user;"}); err != nil {
+		t.Fatalf("DeleteMany failed: %v", err)
+	}
+	if _, err := store.Get("user;"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after DeleteMany, got %v", err)
+	}
+}
+
+func TestSQLiteStoreTransactions(t *testing.T) {
+	store := setupTestStore(t)
+
+	if err := store.Set("counter", []byte("1"), 1); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	err := store.Update(func(txn storage.Txn) error {
+		if _, err := txn.Get("counter"); err != nil {
+			return err
+		}
+		if err := txn.Set("counter", []byte("2")); err != nil {
+			return err
+		}
+		value, err := txn.Get("counter")
+		if err != nil || string(value) != "2" {
+			t.Errorf("Expected transaction to read its own write, got '%s' (%v)", value, err)
+		}
+		return txn.Delete("other")
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	err = store.Update(func(txn storage.Txn) error {
+		if _, err := txn.Get("counter"); err != nil {
+			return err
+		}
+		if err := store.Set("counter", []byte("concurrent"), 1); err != nil {
+			return err
+		}
+		return txn.Set("counter", []byte("3"))
+	})
+	if !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+	value, _ := store.Get("counter")
+	if string(value) != "concurrent" {
+		t.Errorf("Expected conflicting transaction to be discarded, got '%s'", value)
+	}
+
+	err = store.View(func(txn storage.Txn) error {
+		return txn.Set("counter", []byte("4"))
+	})
+	if !errors.Is(err, storage.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported for a write in View, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+)
+
+// SQLiteTxn is an optimistic transaction. Writes are buffered and applied in a
+// single SQLite transaction on commit, after checking that nothing the
+// transaction read has changed in the meantime. This keeps the write lock out
+// of the caller's code, which may itself write to the store.
+type SQLiteTxn struct {
+	store    *SQLiteStore
+	readOnly bool
+	reads    map[string]txnRead
+	writes   []txnWrite
+}
+
+type txnRead struct {
+	value []byte
+	found bool
+}
+
+type txnWrite struct {
+	key       string
+	value     []byte
+	expiresAt any
+	delete    bool
+}
+
+func (s *SQLiteStore) Update(fn func(txn storage.Txn) error) error {
+	if s.closed.Load() {
+		return storage.ErrClosed
+	}
+
+	txn := &SQLiteTxn{store: s, reads: make(map[string]txnRead)}
+	if err := fn(txn); err != nil {
+		return err
+	}
+	return txn.commit()
+}
+
+func (s *SQLiteStore) View(fn func(txn storage.Txn) error) error {
+	if s.closed.Load() {
+		return storage.ErrClosed
+	}
+
+	return fn(&SQLiteTxn{store: s, readOnly: true, reads: make(map[string]txnRead)})
+}
+
+func (t *SQLiteTxn) Get(key string) ([]byte, error) {
+	for i := len(t.writes) - 1; i >= 0; i-- {
+		if t.writes[i].key == key {
+			if t.writes[i].delete {
+				return nil, storage.ErrNotFound
+			}
+			return t.writes[i].value, nil
+		}
+	}
+
+	read, ok := t.reads[key]
+	if !ok {
+		value, err := t.store.Get(key)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, err
+		}
+		read = txnRead{value: value, found: err == nil}
+		t.reads[key] = read
+	}
+
+	if !read.found {
+		return nil, storage.ErrNotFound
+	}
+	return read.value, nil
+}
+
+func (t *SQLiteTxn) Set(key string, value []byte) error {
+	return t.SetWithTTL(key, value, 0)
+}
+
+func (t *SQLiteTxn) SetWithTTL(key string, value []byte, ttl time.Duration) error {
+	if t.readOnly {
+		return storage.NewStorageError(storage.Unsupported, "Failed to set key/value pair: SQLite transaction is read-only")
+	}
+	t.writes = append(t.writes, txnWrite{key: key, value: value, expiresAt: expiresAt(ttl)})
+	return nil
+}
+
+func (t *SQLiteTxn) Delete(key string) error {
+	if t.readOnly {
+		return storage.NewStorageError(storage.Unsupported, "Failed to delete key: SQLite transaction is read-only")
+	}
+	t.writes = append(t.writes, txnWrite{key: key, delete: true})
+	return nil
+}
+
+func (t *SQLiteTxn) commit() error {
+	if len(t.writes) == 0 {
+		return nil
+	}
+
+	tx, err := t.store.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return wrapSQLiteError("Failed to begin SQLite transaction", err)
+	}
+	defer tx.Rollback()
+
+	now := nowMillis()
+	for key, read := range t.reads {
+		var value []byte
+		err := tx.QueryRow(getQuery, key, now).Scan(&value)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return wrapSQLiteError("Failed to validate SQLite transaction", err)
+		}
+		if found := err == nil; found != read.found || !bytes.Equal(value, read.value) {
+			return storage.NewStorageError(storage.Conflict, fmt.Sprintf("Failed to commit SQLite transaction: key '%s' was modified concurrently", key))
+		}
+	}
+
+	for _, write := range t.writes {
+		if write.delete {
+			_, err = tx.Exec(deleteQuery, write.key)
+		} else {
+			_, err = tx.Exec(upsertQuery, write.key, write.value, write.expiresAt)
+		}
+		if err != nil {
+			return wrapSQLiteError("Failed to apply SQLite transaction", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return wrapSQLiteError("Failed to commit SQLite transaction", err)
+	}
+	return nil
+}
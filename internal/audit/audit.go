@@ -0,0 +1,255 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Unfield/AmpKV/pkg/embedded"
+)
+
+const (
+	// entryPrefix is followed by the zero padded Unix time in nanoseconds, so
+	// entries are stored in time order.
+	entryPrefix    = "internal::audit::"
+	entryCost      = 1
+	queueSize      = 1024
+	maxWriteBatch  = 100
+	DefaultLimit   = 100
+	MaxQueryLimit  = 1000
+	timestampWidth = 20
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid audit log cursor")
+	ErrQueueFull     = errors.New("audit queue is full")
+)
+
+// Entry records one authenticated request.
+type Entry struct {
+	Time      time.Time `json:"time"`
+	KeyID     string    `json:"key_id"`
+	Protocol  string    `json:"protocol"`
+	Operation string    `json:"operation"`
+	// Keys are the data keys, or the api key IDs of admin requests, the
+	// request targeted.
+	Keys   []string `json:"keys,omitempty"`
+	Result string   `json:"result"`
+	Client string   `json:"client,omitempty"`
+}
+
+type Options struct {
+	// Retention is how long entries are kept, 0 keeps them forever.
+	Retention time.Duration
+	// Reads also records successful reads. Denied reads are always recorded.
+	Reads bool
+	// OnError is called when entries could not be written or were dropped
+	// because the queue was full. It may be nil.
+	OnError func(error)
+}
+
+// Log stores entries under the internal::audit:: prefix. Entries are written
+// in the background; Close writes the ones still queued. A nil *Log records
+// nothing.
+type Log struct {
+	ampKV *embedded.AmpKV
+	opts  Options
+
+	seq     atomic.Uint64
+	dropped atomic.Uint64
+	mu      sync.RWMutex
+	closed  bool
+	entries chan Entry
+	done    chan struct{}
+}
+
+func NewLog(ampKV *embedded.AmpKV, opts Options) *Log {
+	l := &Log{
+		ampKV:   ampKV,
+		opts:    opts,
+		entries: make(chan Entry, queueSize),
+		done:    make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// Records reports whether a request should be recorded. Successful reads are
+// skipped unless Options.Reads is set.
+func (l *Log) Records(read, succeeded bool) bool {
+	if l == nil {
+		return false
+	}
+	return !read || !succeeded || l.opts.Reads
+}
+
+// Record queues entry. It never blocks the request being recorded: while the
+// queue is full the entry is dropped, counted by Dropped and reported through
+// Options.OnError.
+func (l *Log) Record(entry Entry) {
+	if l == nil {
+		return
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return
+	}
+	select {
+	case l.entries <- entry:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// Dropped returns how many entries were dropped because the queue was full.
+func (l *Log) Dropped() uint64 {
+	if l == nil {
+		return 0
+	}
+	return l.dropped.Load()
+}
+
+func (l *Log) Close() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.entries)
+	}
+	l.mu.Unlock()
+	<-l.done
+}
+
+func (l *Log) run() {
+	defer close(l.done)
+
+	var reported uint64
+	batch := make([]embedded.BatchItem, 0, maxWriteBatch)
+	for entry := range l.entries {
+		batch = append(batch[:0], l.item(entry))
+		for len(batch) < maxWriteBatch {
+			next, ok := tryReceive(l.entries)
+			if !ok {
+				break
+			}
+			batch = append(batch, l.item(next))
+		}
+
+		if _, err := l.ampKV.SetMany(batch); err != nil && l.opts.OnError != nil {
+			l.opts.OnError(fmt.Errorf("failed to write %d audit entries: %w", len(batch), err))
+		}
+		reported = l.reportDropped(reported)
+	}
+	l.reportDropped(reported)
+}
+
+// reportDropped reports the entries dropped since reported, and returns the
+// new total.
+func (l *Log) reportDropped(reported uint64) uint64 {
+	dropped := l.dropped.Load()
+	if dropped > reported && l.opts.OnError != nil {
+		l.opts.OnError(fmt.Errorf("dropped %d audit entries: %w", dropped-reported, ErrQueueFull))
+	}
+	return dropped
+}
+
+func tryReceive(entries <-chan Entry) (Entry, bool) {
+	select {
+	case entry, ok := <-entries:
+		return entry, ok
+	default:
+		return Entry{}, false
+	}
+}
+
+func (l *Log) item(entry Entry) embedded.BatchItem {
+	data, _ := json.Marshal(entry)
+	return embedded.BatchItem{
+		Key:   fmt.Sprintf("%s%0*d::%d", entryPrefix, timestampWidth, entry.Time.UnixNano(), l.seq.Add(1)),
+		Value: data,
+		Cost:  entryCost,
+		TTL:   l.opts.Retention,
+	}
+}
+
+// Query selects entries, oldest first. Zero values match everything.
+type Query struct {
+	KeyID string
+	From  time.Time
+	To    time.Time
+	// Cursor continues a previous query with the same filters.
+	Cursor string
+	Limit  int
+}
+
+// Query returns the matching entries and a cursor for the next page, which is
+// empty once there are no more entries.
+func (l *Log) Query(q Query) ([]Entry, string, error) {
+	if l == nil {
+		return nil, "", errors.New("audit log is disabled")
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxQueryLimit)
+
+	cursor := q.Cursor
+	if cursor == "" && !q.From.IsZero() {
+		cursor = fmt.Sprintf("%s%0*d", entryPrefix, timestampWidth, q.From.UnixNano())
+	}
+	if cursor != "" && !strings.HasPrefix(cursor, entryPrefix) {
+		return nil, "", ErrInvalidCursor
+	}
+
+	entries := make([]Entry, 0, limit)
+	for {
+		page, err := l.ampKV.Scan(entryPrefix, cursor, embedded.MaxScanLimit)
+		if err != nil {
+			return nil, "", err
+		}
+
+		for _, item := range page.Items {
+			if !q.To.IsZero() && entryTime(item.Key).After(q.To) {
+				return entries, "", nil
+			}
+
+			var entry Entry
+			if err := json.Unmarshal(item.Value.Data, &entry); err != nil {
+				return nil, "", fmt.Errorf("failed to decode audit entry '%s': %w", item.Key, err)
+			}
+			if q.KeyID != "" && entry.KeyID != q.KeyID {
+				continue
+			}
+			if len(entries) == limit {
+				return entries, item.Key, nil
+			}
+			entries = append(entries, entry)
+		}
+
+		if page.NextCursor == "" {
+			return entries, "", nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+func entryTime(key string) time.Time {
+	raw, _, _ := strings.Cut(strings.TrimPrefix(key, entryPrefix), "::")
+	nanos, _ := strconv.ParseInt(raw, 10, 64)
+	return time.Unix(0, nanos)
+}
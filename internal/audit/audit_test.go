@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Unfield/AmpKV/drivers/cache/ristretto"
+	"github.com/Unfield/AmpKV/drivers/store/sqlite"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+)
+
+func newTestAmpKV(t *testing.T) *embedded.AmpKV {
+	t.Helper()
+	cache, err := ristretto.NewRistrettoCache(1e4, 1<<20, 64)
+	if err != nil {
+		t.Fatalf("Failed to initialize Cache: %v", err)
+	}
+	store, err := sqlite.NewSQLiteStore(filepath.Join(t.TempDir(), "audit.sqlite"), time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to initialize Store: %v", err)
+	}
+	ampkv, err := embedded.NewAmpKV(cache, store, embedded.AmpKVOptions{})
+	if err != nil {
+		t.Fatalf("Failed to initialize AmpKV: %v", err)
+	}
+	t.Cleanup(func() { ampkv.Close() })
+	return ampkv
+}
+
+func TestLogQuery(t *testing.T) {
+	ampkv := newTestAmpKV(t)
+	log := NewLog(ampkv, Options{Retention: time.Hour})
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 6 {
+		keyID := "KEY-A"
+		if i%2 == 1 {
+			keyID = "KEY-B"
+		}
+		log.Record(Entry{Time: start.Add(time.Duration(i) * time.Minute), KeyID: keyID, Operation: "Set", Keys: []string{"key"}, Result: "OK"})
+	}
+	log.Close()
+	log.Record(Entry{KeyID: "KEY-A"}) // ignored after Close
+
+	entries, next, err := log.Query(Query{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(entries) != 6 || next != "" {
+		t.Fatalf("Expected all 6 entries, got %d (cursor %q)", len(entries), next)
+	}
+
+	t.Run("KeyID", func(t *testing.T) {
+		first, next, err := log.Query(Query{KeyID: "KEY-B", Limit: 2})
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		if len(first) != 2 || next == "" || first[0].KeyID != "KEY-B" || !first[0].Time.Equal(start.Add(time.Minute)) {
+			t.Fatalf("Unexpected first page %+v (cursor %q)", first, next)
+		}
+
+		second, next, err := log.Query(Query{KeyID: "KEY-B", Limit: 2, Cursor: next})
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		if len(second) != 1 || next != "" || !second[0].Time.Equal(start.Add(5*time.Minute)) {
+			t.Fatalf("Unexpected last page %+v (cursor %q)", second, next)
+		}
+	})
+
+	t.Run("TimeRange", func(t *testing.T) {
+		entries, _, err := log.Query(Query{From: start.Add(2 * time.Minute), To: start.Add(4 * time.Minute)})
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		if len(entries) != 3 {
+			t.Fatalf("Expected 3 entries in range, got %d", len(entries))
+		}
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		if _, _, err := log.Query(Query{Cursor: "some-key"}); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor, got %v", err)
+		}
+	})
+}
+
+func TestLogQueueFull(t *testing.T) {
+	ampkv := newTestAmpKV(t)
+	var reported []error
+	// The writer is started once the queue is full.
+	log := &Log{
+		ampKV:   ampkv,
+		opts:    Options{OnError: func(err error) { reported = append(reported, err) }},
+		entries: make(chan Entry, 1),
+		done:    make(chan struct{}),
+	}
+
+	recorded := make(chan struct{})
+	go func() {
+		defer close(recorded)
+		for range 3 {
+			log.Record(Entry{KeyID: "KEY-A", Operation: "Set", Result: "OK"})
+		}
+	}()
+	select {
+	case <-recorded:
+	case <-time.After(time.Second):
+		t.Fatal("Record blocked on a full queue")
+	}
+	if dropped := log.Dropped(); dropped != 2 {
+		t.Errorf("Expected 2 dropped entries, got %d", dropped)
+	}
+
+	go log.run()
+	log.Close()
+	if len(reported) != 1 || !errors.Is(reported[0], ErrQueueFull) {
+		t.Errorf("Expected the dropped entries to be reported once, got %v", reported)
+	}
+	if entries, _, err := log.Query(Query{}); err != nil || len(entries) != 1 {
+		t.Errorf("Expected the queued entry to be written, got %d (%v)", len(entries), err)
+	}
+}
+
+func TestLogRecords(t *testing.T) {
+	var disabled *Log
+	if disabled.Records(false, true) {
+		t.Error("Expected a nil log to record nothing")
+	}
+
+	log := &Log{}
+	if log.Records(true, true) || !log.Records(true, false) || !log.Records(false, true) {
+		t.Error("Expected only successful reads to be skipped")
+	}
+	log.opts.Reads = true
+	if !log.Records(true, true) {
+		t.Error("Expected reads to be recorded with Options.Reads")
+	}
+}
@@ -5,9 +5,20 @@ import (
 	"encoding/gob"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
+
+	"github.com/Unfield/AmpKV/pkg/common"
 )
 
+// ReservedPrefix holds the server's own records, such as api keys. Keys
+// under it are never reachable through the data APIs.
+const ReservedPrefix = "internal::"
+
+func IsReservedKey(key string) bool {
+	return strings.HasPrefix(key, ReservedPrefix)
+}
+
 type Permission string
 
 const (
@@ -18,11 +29,36 @@ const (
 	PermAdmin Permission = "admin"
 )
 
+func ParsePermission(s string) (Permission, error) {
+	switch p := Permission(s); p {
+	case PermRead, PermWrite, PermDelete, PermAdmin:
+		return p, nil
+	default:
+		return "", NewKeyError(KeyMalformed, fmt.Sprintf("unknown permission %q", s))
+	}
+}
+
+// Scope grants Permissions on every key starting with Prefix, in addition to
+// the global permissions of the ApiKey.
+type Scope struct {
+	Prefix      string
+	Permissions []Permission
+}
+
 type ApiKey struct {
-	ID          string
-	Key         string
+	ID string
+	// Key is the token handed to the client, "<ID>.<secret>". It is only set
+	// on the ApiKey returned by CreateAPIKey and is never stored.
+	Key string
+	// SecretHash is the HMAC-SHA256 of the secret part of the token.
+	SecretHash []byte
+	// Legacy marks keys migrated from plaintext records. Their clients may
+	// still send the bare secret without the ID.
+	Legacy      bool
 	Name        string
 	Permissions []Permission
+	Scopes      []Scope
+	Limits      Limits
 	CreatedAt   time.Time
 	ExpiresAt   *time.Time
 	Disabled    bool
@@ -40,29 +76,70 @@ func (apr *ApiKey) HasPermission(p Permission) bool {
 	return false
 }
 
+// HasPermissionFor reports whether p is granted on key, either globally or by
+// a scope covering it. For a prefix it reports whether p is granted on every
+// key starting with it.
+func (apr *ApiKey) HasPermissionFor(key string, p Permission) bool {
+	if apr.HasPermission(p) {
+		return true
+	}
+
+	for _, scope := range apr.Scopes {
+		if strings.HasPrefix(key, scope.Prefix) && slices.Contains(scope.Permissions, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasAnyPermission reports whether p is granted globally or by any scope.
+func (apr *ApiKey) HasAnyPermission(p Permission) bool {
+	if apr.HasPermission(p) {
+		return true
+	}
+
+	for _, scope := range apr.Scopes {
+		if slices.Contains(scope.Permissions, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (apr *ApiKey) IsValid() bool {
 	if apr.Disabled {
 		return false
 	}
-	if apr.ExpiresAt != nil && time.Now().After(*apr.ExpiresAt) {
-		return false
-	}
-	return true
+	return !apr.IsExpired()
 }
 
-func (apr *ApiKey) ToByteSlice() ([]byte, error) {
-	var buffer bytes.Buffer
-	encoder := gob.NewEncoder(&buffer)
+func (apr *ApiKey) IsExpired() bool {
+	return apr.ExpiresAt != nil && time.Now().After(*apr.ExpiresAt)
+}
 
-	err := encoder.Encode(apr)
+// ToByteSlice encodes the key as a binary envelope record, see
+// common.AppendEnvelope.
+func (apr *ApiKey) ToByteSlice() ([]byte, error) {
+	data, err := encodeApiKey(apr)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to encode ApiKey to byte slice: %w", err)
 	}
-
-	return buffer.Bytes(), nil
+	return data, nil
 }
 
+// ApiKeyFromBuffer decodes a key written by ToByteSlice, or by the gob
+// encoding used before.
 func ApiKeyFromBuffer(buffer []byte) (*ApiKey, error) {
+	if common.IsEnvelope(buffer) {
+		apiKey, err := decodeApiKeyRecord(buffer)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode ApiKey from buffer: %w", err)
+		}
+		return apiKey, nil
+	}
+
 	decoder := gob.NewDecoder(bytes.NewReader(buffer))
 	var decodedApiKey ApiKey
 	err := decoder.Decode(&decodedApiKey)
@@ -0,0 +1,222 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+// apiKeyRecordTag is the envelope type tag of ApiKey records.
+const apiKeyRecordTag byte = 1
+
+var errTruncatedApiKey = errors.New("truncated api key record")
+
+func encodeApiKey(apr *ApiKey) ([]byte, error) {
+	var w recordWriter
+	w.string(apr.ID)
+	w.bytes(apr.SecretHash)
+	w.bool(apr.Legacy)
+	w.string(apr.Name)
+	w.permissions(apr.Permissions)
+	w.uvarint(uint64(len(apr.Scopes)))
+	for _, scope := range apr.Scopes {
+		w.string(scope.Prefix)
+		w.permissions(scope.Permissions)
+	}
+	w.uvarint(math.Float64bits(apr.Limits.RequestsPerSecond))
+	w.varint(int64(apr.Limits.Burst))
+	w.varint(apr.Limits.DailyOps)
+	w.varint(apr.Limits.DailyBytes)
+	if err := w.time(apr.CreatedAt); err != nil {
+		return nil, err
+	}
+	w.bool(apr.ExpiresAt != nil)
+	if apr.ExpiresAt != nil {
+		if err := w.time(*apr.ExpiresAt); err != nil {
+			return nil, err
+		}
+	}
+	w.bool(apr.Disabled)
+
+	return common.AppendEnvelope(make([]byte, 0, len(w.buf)+8), apiKeyRecordTag, 0, w.buf), nil
+}
+
+func decodeApiKeyRecord(data []byte) (*ApiKey, error) {
+	tag, _, payload, err := common.OpenEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+	if tag != apiKeyRecordTag {
+		return nil, errors.New("not an api key record")
+	}
+
+	r := recordReader{buf: payload}
+	apiKey := &ApiKey{
+		ID:          r.string(),
+		SecretHash:  bytes.Clone(r.bytes()),
+		Legacy:      r.bool(),
+		Name:        r.string(),
+		Permissions: r.permissions(),
+	}
+	if n := r.count(); n > 0 {
+		apiKey.Scopes = make([]Scope, n)
+		for i := range apiKey.Scopes {
+			apiKey.Scopes[i] = Scope{Prefix: r.string(), Permissions: r.permissions()}
+		}
+	}
+	apiKey.Limits = Limits{
+		RequestsPerSecond: math.Float64frombits(r.uvarint()),
+		Burst:             int(r.varint()),
+		DailyOps:          r.varint(),
+		DailyBytes:        r.varint(),
+	}
+	apiKey.CreatedAt = r.time()
+	if r.bool() {
+		expiresAt := r.time()
+		apiKey.ExpiresAt = &expiresAt
+	}
+	apiKey.Disabled = r.bool()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.buf) != 0 {
+		return nil, errors.New("trailing data in api key record")
+	}
+	return apiKey, nil
+}
+
+type recordWriter struct {
+	buf []byte
+}
+
+func (w *recordWriter) uvarint(v uint64) { w.buf = binary.AppendUvarint(w.buf, v) }
+func (w *recordWriter) varint(v int64)   { w.buf = binary.AppendVarint(w.buf, v) }
+
+func (w *recordWriter) bytes(b []byte) {
+	w.uvarint(uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *recordWriter) string(s string) {
+	w.uvarint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *recordWriter) bool(b bool) {
+	if b {
+		w.buf = append(w.buf, 1)
+	} else {
+		w.buf = append(w.buf, 0)
+	}
+}
+
+func (w *recordWriter) permissions(perms []Permission) {
+	w.uvarint(uint64(len(perms)))
+	for _, p := range perms {
+		w.string(string(p))
+	}
+}
+
+func (w *recordWriter) time(t time.Time) error {
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	w.bytes(data)
+	return nil
+}
+
+// recordReader reads what recordWriter wrote. After the first error every
+// read returns a zero value and err is kept.
+type recordReader struct {
+	buf []byte
+	err error
+}
+
+func (r *recordReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+	r.buf = nil
+}
+
+func (r *recordReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.fail(errTruncatedApiKey)
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *recordReader) varint() int64 {
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.fail(errTruncatedApiKey)
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+// count reads a length that has to fit into the rest of the record.
+func (r *recordReader) count() int {
+	n := r.uvarint()
+	if n > uint64(len(r.buf)) {
+		r.fail(errTruncatedApiKey)
+		return 0
+	}
+	return int(n)
+}
+
+func (r *recordReader) bytes() []byte {
+	n := r.count()
+	if r.err != nil || n == 0 {
+		return nil
+	}
+	b := r.buf[:n:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *recordReader) string() string {
+	return string(r.bytes())
+}
+
+func (r *recordReader) bool() bool {
+	if len(r.buf) == 0 {
+		r.fail(errTruncatedApiKey)
+		return false
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b != 0
+}
+
+func (r *recordReader) permissions() []Permission {
+	n := r.count()
+	if n == 0 {
+		return nil
+	}
+	perms := make([]Permission, n)
+	for i := range perms {
+		perms[i] = Permission(r.string())
+	}
+	return perms
+}
+
+func (r *recordReader) time() time.Time {
+	var t time.Time
+	if data := r.bytes(); r.err == nil {
+		if err := t.UnmarshalBinary(data); err != nil {
+			r.fail(err)
+		}
+	}
+	return t
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+func testApiKeyRecord() *ApiKey {
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 6, time.UTC)
+	return &ApiKey{
+		ID:          "key-id",
+		SecretHash:  bytes.Repeat([]byte{0xA7}, 32),
+		Name:        "codec-key",
+		Permissions: []Permission{PermRead, PermWrite},
+		Scopes:      []Scope{{Prefix: "tenant-a:", Permissions: []Permission{PermDelete}}},
+		Limits:      Limits{RequestsPerSecond: 2.5, Burst: 5, DailyOps: 1000, DailyBytes: 1 << 30},
+		CreatedAt:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:   &expiresAt,
+	}
+}
+
+func gobApiKey(t testing.TB, apiKey *ApiKey) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(apiKey); err != nil {
+		t.Fatalf("Failed to gob encode api key: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+func TestApiKeyCodec(t *testing.T) {
+	for name, want := range map[string]*ApiKey{
+		"Full":    testApiKeyRecord(),
+		"Minimal": {ID: "legacy-id", SecretHash: []byte{1}, Legacy: true, Disabled: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := want.ToByteSlice()
+			if err != nil {
+				t.Fatalf("ToByteSlice failed: %v", err)
+			}
+			if !common.IsEnvelope(data) {
+				t.Fatalf("Expected a binary envelope")
+			}
+			got, err := ApiKeyFromBuffer(data)
+			if err != nil {
+				t.Fatalf("ApiKeyFromBuffer failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected %+v, got %+v", want, got)
+			}
+
+			legacy, err := ApiKeyFromBuffer(gobApiKey(t, want))
+			if err != nil {
+				t.Fatalf("Failed to decode gob api key: %v", err)
+			}
+			if !reflect.DeepEqual(legacy, want) {
+				t.Errorf("Expected gob api key %+v, got %+v", want, legacy)
+			}
+
+			for i := range len(data) - 1 {
+				if _, err := ApiKeyFromBuffer(data[:i]); err == nil {
+					t.Fatalf("Expected an error for a record truncated to %d bytes", i)
+				}
+			}
+		})
+	}
+
+	t.Run("StoredAsBinary", func(t *testing.T) {
+		ampkv := newTestAmpKV(t)
+		manager, err := NewApiKeyManager(ampkv, testSecret)
+		if err != nil {
+			t.Fatalf("NewApiKeyManager failed: %v", err)
+		}
+		created, err := manager.CreateAPIKey("binary-key", []Permission{PermRead}, false, nil)
+		if err != nil {
+			t.Fatalf("CreateAPIKey failed: %v", err)
+		}
+		val, err := ampkv.Get(apiKeyKeyPrefix + created.ID)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if !common.IsEnvelope(val.Data) {
+			t.Error("Expected the api key record to be binary encoded")
+		}
+
+		// Records written with gob before the binary format keep working.
+		stored, err := manager.GetApiKeyByID(created.ID)
+		if err != nil {
+			t.Fatalf("GetApiKeyByID failed: %v", err)
+		}
+		if err := ampkv.Set(apiKeyKeyPrefix+created.ID, gobApiKey(t, stored), apiKeyCost); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if _, err := manager.GetApiKey(created.Key); err != nil {
+			t.Errorf("Expected the gob record to authenticate, got %v", err)
+		}
+	})
+}
+
+func BenchmarkApiKeyDecode(b *testing.B) {
+	apiKey := testApiKeyRecord()
+	binaryData, _ := apiKey.ToByteSlice()
+	gobData := gobApiKey(b, apiKey)
+
+	for name, data := range map[string][]byte{"Binary": binaryData, "Gob": gobData} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := ApiKeyFromBuffer(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,13 @@
 package auth
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Unfield/AmpKV/pkg/embedded"
@@ -9,35 +15,57 @@ import (
 )
 
 const (
-	apiKeyKeyPrefix                 = "internal::api::key::"
+	apiKeyKeyPrefix = "internal::api::key::"
+	// apiKeyLegacyPrefix maps the hash of a migrated plaintext key to its ID,
+	// so clients still sending the bare secret keep working.
+	apiKeyLegacyPrefix = "internal::api::legacy::"
+	// apiKeyBootstrapKey records that the first admin key has been created.
+	apiKeyBootstrapKey              = "internal::api::bootstrap"
+	apiKeyBootstrapName             = "bootstrap-admin"
+	apiKeyTokenSeparator            = "."
 	apiKeyMaxCreationAttempts uint8 = 10
 	apiKeyCost                      = 1
+	apiKeyMinSecretLength           = 16
 )
 
 type ApiKeyManager struct {
-	ampKV *embedded.AmpKV
+	ampKV          *embedded.AmpKV
+	secret         []byte
+	usage          *usageTracker
+	authenticators []Authenticator
 }
 
-func NewApiKeyManager(ampKVptr *embedded.AmpKV) (*ApiKeyManager, error) {
+// NewApiKeyManager creates a manager that stores keys in ampKVptr. Only an
+// HMAC of each key, keyed with secret, is persisted.
+func NewApiKeyManager(ampKVptr *embedded.AmpKV, secret []byte) (*ApiKeyManager, error) {
 	if ampKVptr == nil {
 		return nil, fmt.Errorf("Failed to create new Api Key Manager: ampKVptr must not be empty")
 	}
+	if len(secret) < apiKeyMinSecretLength {
+		return nil, fmt.Errorf("Failed to create new Api Key Manager: secret must be at least %d bytes long", apiKeyMinSecretLength)
+	}
 	return &ApiKeyManager{
-		ampKV: ampKVptr,
+		ampKV:  ampKVptr,
+		secret: secret,
+		usage:  newUsageTracker(ampKVptr),
 	}, nil
 }
 
 func (m *ApiKeyManager) CreateAPIKey(name string, perms []Permission, disabled bool, ttl *time.Duration) (*ApiKey, error) {
+	return m.CreateScopedAPIKey(name, perms, nil, disabled, ttl)
+}
+
+// CreateScopedAPIKey creates a key whose scopes grant extra permissions on
+// key prefixes. Either perms or scopes must be given.
+func (m *ApiKeyManager) CreateScopedAPIKey(name string, perms []Permission, scopes []Scope, disabled bool, ttl *time.Duration) (*ApiKey, error) {
 	if len(name) < 5 {
 		return nil, NewKeyError(KeyMalformed, "name must be at least 5 characters long")
 	}
-	if len(perms) < 1 {
+	if len(perms) < 1 && len(scopes) < 1 {
 		return nil, NewKeyError(KeyMalformed, "perms must contain at least 1 permission")
 	}
-
-	keyID, err := utils.NewID()
-	if err != nil {
-		return nil, NewKeyError(InternalError, "failed to create a unique id")
+	if err := validateScopes(scopes); err != nil {
+		return nil, err
 	}
 
 	var expirationDate *time.Time
@@ -46,184 +74,475 @@ func (m *ApiKeyManager) CreateAPIKey(name string, perms []Permission, disabled b
 		expirationDate = &exp
 	}
 
+	secret := utils.GenerateKey()
 	apiKey := ApiKey{
-		ID:          keyID,
-		Key:         "",
+		SecretHash:  m.hash(secret),
 		Name:        name,
 		Permissions: perms,
+		Scopes:      scopes,
 		CreatedAt:   time.Now(),
 		ExpiresAt:   expirationDate,
 		Disabled:    disabled,
 	}
 
 	for currentAttempt := range apiKeyMaxCreationAttempts {
-		key := utils.GenerateKey()
-		if _, found := m.ampKV.Get(apiKeyKeyPrefix + key); !found {
-			apiKey.Key = key
+		keyID, err := utils.NewID()
+		if err != nil {
+			return nil, NewKeyError(InternalError, "failed to create a unique id")
+		}
+		_, err = m.ampKV.Get(apiKeyKeyPrefix + keyID)
+		if errors.Is(err, embedded.ErrNotFound) {
+			apiKey.ID = keyID
 			break
 		}
+		if err != nil {
+			return nil, NewKeyErrorWithCause(InternalError, "failed to check id uniqueness", err)
+		}
 		time.Sleep(time.Duration(1<<currentAttempt) * 5 * time.Millisecond)
 	}
 
-	if apiKey.Key == "" {
-		return nil, &KeyError{Kind: InternalError, Message: "failed to create a unique key"}
+	if apiKey.ID == "" {
+		return nil, &KeyError{Kind: InternalError, Message: "failed to create a unique id"}
 	}
 
-	apiKeyByteSlice, err := apiKey.ToByteSlice()
+	err := m.ampKV.Update(func(tx *embedded.Txn) error {
+		return m.write(tx, nil, &apiKey)
+	})
 	if err != nil {
-		return nil, NewKeyErrorWithCause(InternalError, "failed to create byte slice from ApiKey", err)
+		return nil, NewKeyErrorWithCause(InternalError, "failed to save key to store", err)
 	}
 
-	if ttl != nil {
-		err = m.ampKV.SetWithTTL(apiKeyKeyPrefix+apiKey.Key, apiKeyByteSlice, apiKeyCost, *ttl)
-		if err != nil {
-			return nil, NewKeyErrorWithCause(InternalError, "failed to save key to storage", err)
+	apiKey.Key = apiKey.ID + apiKeyTokenSeparator + secret
+	return &apiKey, nil
+}
+
+func validateScopes(scopes []Scope) error {
+	for _, scope := range scopes {
+		if scope.Prefix == "" {
+			return NewKeyError(KeyMalformed, "scope prefix must not be empty")
 		}
-	} else {
-		err = m.ampKV.Set(apiKeyKeyPrefix+apiKey.Key, apiKeyByteSlice, apiKeyCost)
-		if err != nil {
-			return nil, NewKeyErrorWithCause(InternalError, "failed to save key to storage", err)
+		if IsReservedKey(scope.Prefix) {
+			return NewKeyError(KeyMalformed, fmt.Sprintf("scope prefix %q is reserved", scope.Prefix))
+		}
+		if len(scope.Permissions) < 1 {
+			return NewKeyError(KeyMalformed, fmt.Sprintf("scope %q must contain at least 1 permission", scope.Prefix))
+		}
+		if slices.Contains(scope.Permissions, PermAdmin) {
+			return NewKeyError(KeyMalformed, fmt.Sprintf("scope %q must not grant admin", scope.Prefix))
 		}
 	}
-
-	return &apiKey, nil
+	return nil
 }
 
-func (m *ApiKeyManager) GetApiKey(key string) (*ApiKey, error) {
-	if key == "" {
+// GetApiKey returns the key for a client token. Tokens are "<ID>.<secret>";
+// the bare secret of a migrated legacy key is accepted as well.
+func (m *ApiKeyManager) GetApiKey(token string) (*ApiKey, error) {
+	if token == "" {
 		return nil, ErrKeyMalformed
 	}
 
-	apiKeyValue, found := m.ampKV.Get(apiKeyKeyPrefix + key)
-	if !found {
-		return nil, ErrKeyNotFound
+	id, secret, ok := strings.Cut(token, apiKeyTokenSeparator)
+	if !ok {
+		legacyID, err := m.lookupLegacyID(token)
+		if err != nil {
+			return nil, err
+		}
+		id, secret = legacyID, token
+	}
+	if id == "" || secret == "" {
+		return nil, ErrKeyMalformed
 	}
 
-	apiKey, err := ApiKeyFromBuffer(apiKeyValue.Data)
+	apiKey, err := m.load(id)
 	if err != nil {
-		return nil, NewKeyErrorWithCause(InternalError, "failed to convert byte slice into ApiKey", err)
+		return nil, err
 	}
 
-	if !apiKey.IsValid() {
+	if !hmac.Equal(apiKey.SecretHash, m.hash(secret)) {
+		return nil, ErrKeyNotFound
+	}
+
+	if apiKey.Disabled {
+		return nil, ErrKeyDisabled
+	}
+	if apiKey.IsExpired() {
 		return nil, ErrKeyExpired
 	}
 
 	return apiKey, nil
 }
 
-func (m *ApiKeyManager) DisabledApiKey(key string) error {
-	apiKeyValue, found := m.ampKV.Get(apiKeyKeyPrefix + key)
-	if !found {
-		return ErrKeyNotFound
+func (m *ApiKeyManager) DisabledApiKey(id string) error {
+	return m.update(id, func(apiKey *ApiKey) error {
+		apiKey.Disabled = true
+		return nil
+	})
+}
+
+func (m *ApiKeyManager) EnableApiKey(id string) error {
+	return m.update(id, func(apiKey *ApiKey) error {
+		apiKey.Disabled = false
+		return nil
+	})
+}
+
+func (m *ApiKeyManager) SetExpiration(id string, newExpiration time.Time) error {
+	if newExpiration.Before(time.Now()) {
+		return NewKeyError(KeyMalformed, "newExpriration can not be in the past")
 	}
 
-	apiKey, err := ApiKeyFromBuffer(apiKeyValue.Data)
+	return m.update(id, func(apiKey *ApiKey) error {
+		apiKey.ExpiresAt = &newExpiration
+		return nil
+	})
+}
+
+func (m *ApiKeyManager) RemoveExpiration(id string) error {
+	return m.update(id, func(apiKey *ApiKey) error {
+		apiKey.ExpiresAt = nil
+		return nil
+	})
+}
+
+// RotateApiKey replaces the secret of a key. The returned ApiKey carries the
+// new token in Key; the old token stops working immediately.
+func (m *ApiKeyManager) RotateApiKey(id string) (*ApiKey, error) {
+	secret := utils.GenerateKey()
+
+	var rotated ApiKey
+	err := m.update(id, func(apiKey *ApiKey) error {
+		apiKey.SecretHash = m.hash(secret)
+		apiKey.Legacy = false
+		rotated = *apiKey
+		return nil
+	})
 	if err != nil {
-		return NewKeyErrorWithCause(InternalError, "failed to convert byte slice into ApiKey", err)
+		return nil, err
 	}
 
-	if !apiKey.IsValid() {
-		return ErrKeyExpired
-	}
+	rotated.Key = rotated.ID + apiKeyTokenSeparator + secret
+	return &rotated, nil
+}
 
-	apiKey.Disabled = true
+// GetApiKeyByID returns a key without checking its secret or validity.
+func (m *ApiKeyManager) GetApiKeyByID(id string) (*ApiKey, error) {
+	return m.load(id)
+}
+
+// ListApiKeys returns up to limit keys ordered by ID, starting at cursor. The
+// returned cursor is empty once every key has been listed.
+func (m *ApiKeyManager) ListApiKeys(cursor string, limit int) ([]*ApiKey, string, error) {
+	start := ""
+	if cursor != "" {
+		start = apiKeyKeyPrefix + cursor
+	}
 
-	apiKeyBytes, err := apiKey.ToByteSlice()
+	page, err := m.ampKV.Scan(apiKeyKeyPrefix, start, limit)
 	if err != nil {
-		return NewKeyErrorWithCause(InternalError, "failed to convert ApiKey to byte slice", err)
+		return nil, "", NewKeyErrorWithCause(InternalError, "failed to list keys", err)
 	}
 
-	if apiKey.ExpiresAt != nil {
-		err := m.ampKV.SetWithTTL(apiKeyKeyPrefix+key, apiKeyBytes, apiKeyCost, time.Until(*apiKey.ExpiresAt))
+	apiKeys := make([]*ApiKey, 0, len(page.Items))
+	for _, item := range page.Items {
+		apiKey, err := decodeApiKey(item.Value.Data)
 		if err != nil {
-			return NewKeyErrorWithCause(InternalError, "failed to save key to store", err)
+			return nil, "", err
 		}
-	} else {
-		err := m.ampKV.Set(apiKeyKeyPrefix+key, apiKeyBytes, apiKeyCost)
-		if err != nil {
-			return NewKeyErrorWithCause(InternalError, "failed to save key to store", err)
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	return apiKeys, strings.TrimPrefix(page.NextCursor, apiKeyKeyPrefix), nil
+}
+
+func (m *ApiKeyManager) DeleteKey(id string) error {
+	apiKey, err := m.load(id)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = m.ampKV.Update(func(tx *embedded.Txn) error {
+		if apiKey.Legacy {
+			if err := tx.Delete(legacyIndexKey(apiKey.SecretHash)); err != nil {
+				return err
+			}
 		}
+		return tx.Delete(apiKeyKeyPrefix + id)
+	})
+	if err != nil {
+		return NewKeyErrorWithCause(InternalError, "failed to delete key from store", err)
 	}
+	m.usage.forget(id)
 	return nil
 }
 
-func (m *ApiKeyManager) EnableApiKey(key string) error {
-	apiKeyValue, found := m.ampKV.Get(apiKeyKeyPrefix + key)
-	if !found {
-		return ErrKeyNotFound
+// BootstrapAdminKey creates the first admin key so that a fresh server can be
+// managed at all. token may provide the "<ID>.<secret>" to use, otherwise one
+// is generated. Bootstrapping happens once: if it already did, or keys exist
+// from before bootstrapping was introduced, nil is returned.
+func (m *ApiKeyManager) BootstrapAdminKey(token string) (*ApiKey, error) {
+	var id, secret string
+	if token != "" {
+		var ok bool
+		id, secret, ok = strings.Cut(token, apiKeyTokenSeparator)
+		if !ok || id == "" || len(secret) < apiKeyMinSecretLength {
+			return nil, NewKeyError(KeyMalformed, fmt.Sprintf("bootstrap token must have the form <id>.<secret> with a secret of at least %d characters", apiKeyMinSecretLength))
+		}
+	} else {
+		var err error
+		id, err = utils.NewID()
+		if err != nil {
+			return nil, NewKeyError(InternalError, "failed to create a unique id")
+		}
+		secret = utils.GenerateKey()
 	}
 
-	apiKey, err := ApiKeyFromBuffer(apiKeyValue.Data)
+	hasKeys, err := m.hasKeys()
 	if err != nil {
-		return NewKeyErrorWithCause(InternalError, "failed to convert byte slice into ApiKey", err)
+		return nil, err
 	}
 
-	if !apiKey.IsValid() {
-		return ErrKeyExpired
+	apiKey := &ApiKey{
+		ID:          id,
+		SecretHash:  m.hash(secret),
+		Name:        apiKeyBootstrapName,
+		Permissions: []Permission{PermAdmin},
+		CreatedAt:   time.Now(),
+	}
+
+	created := false
+	err = m.ampKV.Update(func(tx *embedded.Txn) error {
+		created = false
+		_, err := tx.Get(apiKeyBootstrapKey)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, embedded.ErrNotFound) {
+			return err
+		}
+
+		if hasKeys {
+			return tx.Set(apiKeyBootstrapKey, []byte{}, apiKeyCost)
+		}
+
+		if _, err := tx.Get(apiKeyKeyPrefix + id); err == nil {
+			return NewKeyError(KeyMalformed, "bootstrap token id is already in use")
+		} else if !errors.Is(err, embedded.ErrNotFound) {
+			return err
+		}
+		if err := m.write(tx, nil, apiKey); err != nil {
+			return err
+		}
+		created = true
+		return tx.Set(apiKeyBootstrapKey, []byte(id), apiKeyCost)
+	})
+	var keyErr *KeyError
+	if err != nil && !errors.As(err, &keyErr) {
+		return nil, NewKeyErrorWithCause(InternalError, "failed to bootstrap admin key", err)
+	}
+	if err != nil || !created {
+		return nil, err
 	}
 
-	apiKey.Disabled = false
+	apiKey.Key = id + apiKeyTokenSeparator + secret
+	return apiKey, nil
+}
 
-	apiKeyBytes, err := apiKey.ToByteSlice()
+func (m *ApiKeyManager) hasKeys() (bool, error) {
+	page, err := m.ampKV.Scan(apiKeyKeyPrefix, "", 1)
+	if errors.Is(err, embedded.ErrUnsupported) {
+		return false, nil
+	}
 	if err != nil {
-		return NewKeyErrorWithCause(InternalError, "failed to convert ApiKey to byte slice", err)
+		return false, NewKeyErrorWithCause(InternalError, "failed to scan keys", err)
 	}
+	return len(page.Items) > 0, nil
+}
 
-	if apiKey.ExpiresAt != nil {
-		err := m.ampKV.SetWithTTL(apiKeyKeyPrefix+key, apiKeyBytes, apiKeyCost, time.Until(*apiKey.ExpiresAt))
+// MigrateLegacyKeys rewrites keys that were stored under their plaintext
+// secret into hashed records stored under their ID. It returns the number of
+// migrated keys.
+func (m *ApiKeyManager) MigrateLegacyKeys() (int, error) {
+	var (
+		migrated int
+		cursor   string
+	)
+	for {
+		page, err := m.ampKV.Scan(apiKeyKeyPrefix, cursor, embedded.MaxScanLimit)
+		if errors.Is(err, embedded.ErrUnsupported) {
+			// Nothing is persisted without a store, so there is nothing to
+			// migrate either.
+			return 0, nil
+		}
 		if err != nil {
-			return NewKeyErrorWithCause(InternalError, "failed to save key to store", err)
+			return migrated, NewKeyErrorWithCause(InternalError, "failed to scan keys", err)
 		}
-	} else {
-		err := m.ampKV.Set(apiKeyKeyPrefix+key, apiKeyBytes, apiKeyCost)
+
+		for _, item := range page.Items {
+			apiKey, err := ApiKeyFromBuffer(item.Value.Data)
+			if err != nil {
+				return migrated, NewKeyErrorWithCause(KeyCorrupted, "failed to convert byte slice into ApiKey", err)
+			}
+			if len(apiKey.SecretHash) > 0 {
+				continue
+			}
+
+			if err := m.migrate(strings.TrimPrefix(item.Key, apiKeyKeyPrefix), apiKey); err != nil {
+				return migrated, err
+			}
+			migrated++
+		}
+
+		if page.NextCursor == "" {
+			return migrated, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+func (m *ApiKeyManager) migrate(secret string, apiKey *ApiKey) error {
+	if apiKey.ID == "" || apiKey.ID == secret {
+		id, err := utils.NewID()
 		if err != nil {
-			return NewKeyErrorWithCause(InternalError, "failed to save key to store", err)
+			return NewKeyError(InternalError, "failed to create a unique id")
 		}
+		apiKey.ID = id
+	}
+	apiKey.SecretHash = m.hash(secret)
+	apiKey.Legacy = true
+
+	err := m.ampKV.Update(func(tx *embedded.Txn) error {
+		if err := tx.Delete(apiKeyKeyPrefix + secret); err != nil {
+			return err
+		}
+		if apiKey.IsExpired() {
+			return nil
+		}
+		return m.write(tx, nil, apiKey)
+	})
+	if err != nil {
+		return NewKeyErrorWithCause(InternalError, "failed to migrate key", err)
 	}
 	return nil
 }
 
-func (m *ApiKeyManager) SetExpiration(key string, newExpiration time.Time) error {
-	apiKeyValue, found := m.ampKV.Get(apiKeyKeyPrefix + key)
-	if !found {
-		return ErrKeyNotFound
+func (m *ApiKeyManager) lookupLegacyID(secret string) (string, error) {
+	value, err := m.ampKV.Get(legacyIndexKey(m.hash(secret)))
+	if err != nil {
+		return "", lookupError(err)
 	}
+	return string(value.Data), nil
+}
 
-	if newExpiration.Before(time.Now()) {
-		return NewKeyError(KeyMalformed, "newExpriration can not be in the past")
+func (m *ApiKeyManager) load(id string) (*ApiKey, error) {
+	if id == "" {
+		return nil, ErrKeyMalformed
 	}
 
-	apiKey, err := ApiKeyFromBuffer(apiKeyValue.Data)
+	apiKeyValue, err := m.ampKV.Get(apiKeyKeyPrefix + id)
 	if err != nil {
-		return NewKeyErrorWithCause(InternalError, "failed to convert byte slice into ApiKey", err)
+		return nil, lookupError(err)
 	}
+	return decodeApiKey(apiKeyValue.Data)
+}
 
-	if !apiKey.IsValid() {
-		return ErrKeyExpired
+// update applies fn to the stored key inside a transaction. fn may run more
+// than once if the transaction conflicts.
+func (m *ApiKeyManager) update(id string, fn func(apiKey *ApiKey) error) error {
+	if id == "" {
+		return ErrKeyMalformed
 	}
 
-	apiKey.ExpiresAt = &newExpiration
+	err := m.ampKV.Update(func(tx *embedded.Txn) error {
+		apiKeyValue, err := tx.Get(apiKeyKeyPrefix + id)
+		if err != nil {
+			return lookupError(err)
+		}
+		apiKey, err := decodeApiKey(apiKeyValue.Data)
+		if err != nil {
+			return err
+		}
+		if apiKey.IsExpired() {
+			return ErrKeyExpired
+		}
+
+		previous := *apiKey
+		if err := fn(apiKey); err != nil {
+			return err
+		}
+		return m.write(tx, &previous, apiKey)
+	})
+	var keyErr *KeyError
+	if err != nil && !errors.As(err, &keyErr) {
+		return NewKeyErrorWithCause(InternalError, "failed to save key to store", err)
+	}
+	return err
+}
 
-	apiKeyBytes, err := apiKey.ToByteSlice()
+// write stores apiKey and keeps the legacy index in sync with it. previous is
+// the stored version of the key, if any.
+func (m *ApiKeyManager) write(tx *embedded.Txn, previous, apiKey *ApiKey) error {
+	record := *apiKey
+	record.Key = ""
+
+	apiKeyBytes, err := record.ToByteSlice()
 	if err != nil {
 		return NewKeyErrorWithCause(InternalError, "failed to convert ApiKey to byte slice", err)
 	}
 
-	if apiKey.ExpiresAt != nil {
-		err := m.ampKV.SetWithTTL(apiKeyKeyPrefix+key, apiKeyBytes, apiKeyCost, time.Until(*apiKey.ExpiresAt))
-		if err != nil {
-			return NewKeyErrorWithCause(InternalError, "failed to save key to store", err)
+	ttl, expired := apiKeyTTL(&record)
+	if expired {
+		return ErrKeyExpired
+	}
+
+	if previous != nil && previous.Legacy && (!record.Legacy || !hmac.Equal(previous.SecretHash, record.SecretHash)) {
+		if err := tx.Delete(legacyIndexKey(previous.SecretHash)); err != nil {
+			return err
 		}
-	} else {
-		err := m.ampKV.Set(apiKeyKeyPrefix+key, apiKeyBytes, apiKeyCost)
-		if err != nil {
-			return NewKeyErrorWithCause(InternalError, "failed to save key to store", err)
+	}
+	if record.Legacy {
+		if err := tx.SetWithTTL(legacyIndexKey(record.SecretHash), []byte(record.ID), apiKeyCost, ttl); err != nil {
+			return err
 		}
 	}
-	return nil
+	return tx.SetWithTTL(apiKeyKeyPrefix+record.ID, apiKeyBytes, apiKeyCost, ttl)
 }
 
-func (m *ApiKeyManager) DeleteKey(key string) error {
-	m.ampKV.Delete(apiKeyKeyPrefix + key)
-	return nil
+func (m *ApiKeyManager) hash(secret string) []byte {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(secret))
+	return mac.Sum(nil)
+}
+
+// apiKeyTTL returns the storage TTL for a key, 0 if it does not expire.
+func apiKeyTTL(apiKey *ApiKey) (time.Duration, bool) {
+	if apiKey.ExpiresAt == nil {
+		return 0, false
+	}
+	ttl := time.Until(*apiKey.ExpiresAt)
+	return ttl, ttl <= 0
+}
+
+func legacyIndexKey(secretHash []byte) string {
+	return apiKeyLegacyPrefix + hex.EncodeToString(secretHash)
+}
+
+func decodeApiKey(data []byte) (*ApiKey, error) {
+	apiKey, err := ApiKeyFromBuffer(data)
+	if err != nil {
+		return nil, NewKeyErrorWithCause(KeyCorrupted, "failed to convert byte slice into ApiKey", err)
+	}
+	if len(apiKey.SecretHash) == 0 {
+		return nil, NewKeyError(KeyCorrupted, "api key has no secret hash")
+	}
+	return apiKey, nil
+}
+
+func lookupError(err error) error {
+	if errors.Is(err, embedded.ErrNotFound) {
+		return ErrKeyNotFound
+	}
+	return NewKeyErrorWithCause(InternalError, "failed to read key from store", err)
 }
@@ -0,0 +1,376 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Unfield/AmpKV/drivers/cache/ristretto"
+	"github.com/Unfield/AmpKV/drivers/store/sqlite"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+)
+
+var testSecret = []byte("apikey-manager-test-secret")
+
+func newTestAmpKV(t *testing.T) *embedded.AmpKV {
+	t.Helper()
+	cache, err := ristretto.NewRistrettoCache(1e4, 1<<20, 64)
+	if err != nil {
+		t.Fatalf("Failed to initialize Cache: %v", err)
+	}
+	store, err := sqlite.NewSQLiteStore(filepath.Join(t.TempDir(), "auth.sqlite"), time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to initialize Store: %v", err)
+	}
+	ampkv, err := embedded.NewAmpKV(cache, store, embedded.AmpKVOptions{})
+	if err != nil {
+		t.Fatalf("Failed to initialize AmpKV: %v", err)
+	}
+	t.Cleanup(func() { ampkv.Close() })
+	return ampkv
+}
+
+func TestApiKeyManager(t *testing.T) {
+	ampkv := newTestAmpKV(t)
+	manager, err := NewApiKeyManager(ampkv, testSecret)
+	if err != nil {
+		t.Fatalf("NewApiKeyManager failed: %v", err)
+	}
+
+	apiKey, err := manager.CreateAPIKey("test-key", []Permission{PermRead}, false, nil)
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	id, secret, ok := strings.Cut(apiKey.Key, ".")
+	if !ok || id != apiKey.ID {
+		t.Fatalf("Expected token '<ID>.<secret>', got '%s'", apiKey.Key)
+	}
+
+	t.Run("NoPlaintextStored", func(t *testing.T) {
+		page, err := ampkv.Scan("internal::", "", 0)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		for _, item := range page.Items {
+			if strings.Contains(item.Key, secret) || strings.Contains(string(item.Value.Data), secret) {
+				t.Errorf("Secret found in stored record '%s'", item.Key)
+			}
+		}
+	})
+
+	t.Run("Lookup", func(t *testing.T) {
+		found, err := manager.GetApiKey(apiKey.Key)
+		if err != nil {
+			t.Fatalf("GetApiKey failed: %v", err)
+		}
+		if found.Name != "test-key" || found.Key != "" {
+			t.Errorf("Unexpected key returned: %+v", found)
+		}
+
+		for _, token := range []string{id + ".wrong", "unknown." + secret, secret, id + "."} {
+			if _, err := manager.GetApiKey(token); err == nil {
+				t.Errorf("Expected token '%s' to be rejected", token)
+			}
+		}
+	})
+
+	t.Run("DisableEnable", func(t *testing.T) {
+		if err := manager.DisabledApiKey(id); err != nil {
+			t.Fatalf("DisabledApiKey failed: %v", err)
+		}
+		if _, err := manager.GetApiKey(apiKey.Key); !errors.Is(err, ErrKeyDisabled) {
+			t.Errorf("Expected ErrKeyDisabled, got %v", err)
+		}
+		if err := manager.EnableApiKey(id); err != nil {
+			t.Fatalf("EnableApiKey failed: %v", err)
+		}
+		if _, err := manager.GetApiKey(apiKey.Key); err != nil {
+			t.Errorf("Expected enabled key to be valid, got %v", err)
+		}
+	})
+
+	t.Run("OtherSecret", func(t *testing.T) {
+		other, err := NewApiKeyManager(ampkv, []byte("a-completely-different-secret"))
+		if err != nil {
+			t.Fatalf("NewApiKeyManager failed: %v", err)
+		}
+		if _, err := other.GetApiKey(apiKey.Key); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("Expected ErrKeyNotFound with another secret, got %v", err)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := manager.DeleteKey(id); err != nil {
+			t.Fatalf("DeleteKey failed: %v", err)
+		}
+		if _, err := manager.GetApiKey(apiKey.Key); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("Expected ErrKeyNotFound after delete, got %v", err)
+		}
+	})
+}
+
+func TestMigrateLegacyKeys(t *testing.T) {
+	ampkv := newTestAmpKV(t)
+
+	const legacySecret = "legacy-plaintext-secret"
+	legacy := ApiKey{ID: "LEGACYID", Key: legacySecret, Name: "legacy-key", Permissions: []Permission{PermWrite}, CreatedAt: time.Now()}
+	data, err := legacy.ToByteSlice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ampkv.Set(apiKeyKeyPrefix+legacySecret, data, apiKeyCost); err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := NewApiKeyManager(ampkv, testSecret)
+	if err != nil {
+		t.Fatalf("NewApiKeyManager failed: %v", err)
+	}
+	created, err := manager.CreateAPIKey("new-key", []Permission{PermRead}, false, nil)
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+
+	migrated, err := manager.MigrateLegacyKeys()
+	if err != nil {
+		t.Fatalf("MigrateLegacyKeys failed: %v", err)
+	}
+	if migrated != 1 {
+		t.Errorf("Expected 1 migrated key, got %d", migrated)
+	}
+
+	if _, err := ampkv.Get(apiKeyKeyPrefix + legacySecret); !errors.Is(err, embedded.ErrNotFound) {
+		t.Errorf("Expected plaintext record to be removed, got %v", err)
+	}
+
+	for _, token := range []string{legacySecret, "LEGACYID." + legacySecret} {
+		found, err := manager.GetApiKey(token)
+		if err != nil {
+			t.Fatalf("GetApiKey('%s') failed after migration: %v", token, err)
+		}
+		if found.Name != "legacy-key" || !found.HasPermission(PermWrite) {
+			t.Errorf("Unexpected migrated key: %+v", found)
+		}
+	}
+	if _, err := manager.GetApiKey(created.Key); err != nil {
+		t.Errorf("Expected new key to survive migration, got %v", err)
+	}
+
+	if migrated, err := manager.MigrateLegacyKeys(); err != nil || migrated != 0 {
+		t.Errorf("Expected second migration to be a no-op, got %d, %v", migrated, err)
+	}
+
+	if err := manager.DeleteKey("LEGACYID"); err != nil {
+		t.Fatalf("DeleteKey failed: %v", err)
+	}
+	if _, err := manager.GetApiKey(legacySecret); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected legacy token to be gone after delete, got %v", err)
+	}
+}
+
+func TestBootstrapAdminKey(t *testing.T) {
+	t.Run("Generated", func(t *testing.T) {
+		manager, err := NewApiKeyManager(newTestAmpKV(t), testSecret)
+		if err != nil {
+			t.Fatalf("NewApiKeyManager failed: %v", err)
+		}
+
+		apiKey, err := manager.BootstrapAdminKey("")
+		if err != nil {
+			t.Fatalf("BootstrapAdminKey failed: %v", err)
+		}
+		if apiKey == nil || !apiKey.HasPermission(PermAdmin) {
+			t.Fatalf("Expected an admin key, got %+v", apiKey)
+		}
+		if _, err := manager.GetApiKey(apiKey.Key); err != nil {
+			t.Errorf("Expected bootstrap token to be valid, got %v", err)
+		}
+
+		// Deleting the key must not trigger a second bootstrap.
+		if err := manager.DeleteKey(apiKey.ID); err != nil {
+			t.Fatalf("DeleteKey failed: %v", err)
+		}
+		again, err := manager.BootstrapAdminKey("")
+		if err != nil || again != nil {
+			t.Errorf("Expected bootstrap to happen only once, got %+v, %v", again, err)
+		}
+	})
+
+	t.Run("ProvidedToken", func(t *testing.T) {
+		manager, err := NewApiKeyManager(newTestAmpKV(t), testSecret)
+		if err != nil {
+			t.Fatalf("NewApiKeyManager failed: %v", err)
+		}
+
+		if _, err := manager.BootstrapAdminKey("no-separator"); !errors.Is(err, ErrKeyMalformed) {
+			t.Errorf("Expected ErrKeyMalformed for a malformed token, got %v", err)
+		}
+
+		const token = "OPS.provided-bootstrap-secret"
+		apiKey, err := manager.BootstrapAdminKey(token)
+		if err != nil || apiKey == nil || apiKey.ID != "OPS" {
+			t.Fatalf("Expected key 'OPS', got %+v, %v", apiKey, err)
+		}
+		if _, err := manager.GetApiKey(token); err != nil {
+			t.Errorf("Expected provided token to be valid, got %v", err)
+		}
+	})
+
+	t.Run("ExistingKeys", func(t *testing.T) {
+		manager, err := NewApiKeyManager(newTestAmpKV(t), testSecret)
+		if err != nil {
+			t.Fatalf("NewApiKeyManager failed: %v", err)
+		}
+		if _, err := manager.CreateAPIKey("existing-admin", []Permission{PermAdmin}, false, nil); err != nil {
+			t.Fatalf("CreateAPIKey failed: %v", err)
+		}
+
+		apiKey, err := manager.BootstrapAdminKey("")
+		if err != nil || apiKey != nil {
+			t.Errorf("Expected no bootstrap with existing keys, got %+v, %v", apiKey, err)
+		}
+	})
+}
+
+func TestLoadOrCreateSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets", "auth.secret")
+
+	secret, err := LoadOrCreateSecret(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateSecret failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected secret file to be created: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("Expected mode 0600, got %o", perm)
+	}
+
+	again, err := LoadOrCreateSecret(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateSecret failed: %v", err)
+	}
+	if string(again) != string(secret) {
+		t.Error("Expected the existing secret to be loaded")
+	}
+}
+
+func TestScopedApiKey(t *testing.T) {
+	manager, err := NewApiKeyManager(newTestAmpKV(t), testSecret)
+	if err != nil {
+		t.Fatalf("NewApiKeyManager failed: %v", err)
+	}
+
+	for _, scopes := range [][]Scope{
+		{{Prefix: "", Permissions: []Permission{PermRead}}},
+		{{Prefix: ReservedPrefix + "api::", Permissions: []Permission{PermRead}}},
+		{{Prefix: "tenant/", Permissions: []Permission{PermAdmin}}},
+		{{Prefix: "tenant/"}},
+	} {
+		if _, err := manager.CreateScopedAPIKey("scoped-key", nil, scopes, false, nil); !errors.Is(err, ErrKeyMalformed) {
+			t.Errorf("Expected ErrKeyMalformed for scopes %+v, got %v", scopes, err)
+		}
+	}
+
+	created, err := manager.CreateScopedAPIKey("scoped-key", []Permission{PermRead}, []Scope{{Prefix: "tenant/", Permissions: []Permission{PermWrite}}}, false, nil)
+	if err != nil {
+		t.Fatalf("CreateScopedAPIKey failed: %v", err)
+	}
+	apiKey, err := manager.GetApiKey(created.Key)
+	if err != nil {
+		t.Fatalf("GetApiKey failed: %v", err)
+	}
+
+	if !apiKey.HasPermissionFor("other", PermRead) || !apiKey.HasPermissionFor("tenant/a", PermWrite) {
+		t.Error("Expected global read and scoped write")
+	}
+	if apiKey.HasPermissionFor("other", PermWrite) || apiKey.HasPermissionFor("tenant", PermWrite) || apiKey.HasPermissionFor("tenant/a", PermDelete) {
+		t.Error("Expected write to be limited to the scope")
+	}
+	if !apiKey.HasAnyPermission(PermWrite) || apiKey.HasAnyPermission(PermDelete) {
+		t.Error("Unexpected result from HasAnyPermission")
+	}
+}
+
+func TestApiKeyLimits(t *testing.T) {
+	ampkv := newTestAmpKV(t)
+	manager, err := NewApiKeyManager(ampkv, testSecret)
+	if err != nil {
+		t.Fatalf("NewApiKeyManager failed: %v", err)
+	}
+	created, err := manager.CreateAPIKey("limited-key", []Permission{PermRead}, false, nil)
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+
+	if err := manager.SetLimits(created.ID, Limits{Burst: 2}); !errors.Is(err, ErrKeyMalformed) {
+		t.Errorf("Expected ErrKeyMalformed for a burst without rate, got %v", err)
+	}
+
+	t.Run("RateLimit", func(t *testing.T) {
+		apiKey := &ApiKey{ID: "RATE", Limits: Limits{RequestsPerSecond: 1, Burst: 2}}
+		now := time.Now()
+		for i := range 2 {
+			if err := manager.usage.allow(apiKey, now); err != nil {
+				t.Fatalf("Request %d: expected to be allowed, got %v", i, err)
+			}
+		}
+
+		err := manager.usage.allow(apiKey, now)
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) || !errors.Is(err, ErrRateLimited) || limitErr.RetryAfter <= 0 || limitErr.RetryAfter > time.Second {
+			t.Fatalf("Expected ErrRateLimited with a retry delay, got %v", err)
+		}
+		if err := manager.usage.allow(apiKey, now.Add(time.Second)); err != nil {
+			t.Errorf("Expected the bucket to refill, got %v", err)
+		}
+	})
+
+	t.Run("QuotaSurvivesRestart", func(t *testing.T) {
+		if err := manager.SetLimits(created.ID, Limits{DailyOps: 2, DailyBytes: 100}); err != nil {
+			t.Fatalf("SetLimits failed: %v", err)
+		}
+		apiKey, err := manager.GetApiKey(created.Key)
+		if err != nil {
+			t.Fatalf("GetApiKey failed: %v", err)
+		}
+
+		if err := manager.CheckLimits(apiKey); err != nil {
+			t.Fatalf("Expected first request to be allowed, got %v", err)
+		}
+		manager.RecordBytes(apiKey, 10)
+		if err := manager.FlushUsage(); err != nil {
+			t.Fatalf("FlushUsage failed: %v", err)
+		}
+
+		restarted, err := NewApiKeyManager(ampkv, testSecret)
+		if err != nil {
+			t.Fatalf("NewApiKeyManager failed: %v", err)
+		}
+		if err := restarted.CheckLimits(apiKey); err != nil {
+			t.Fatalf("Expected second request to be allowed, got %v", err)
+		}
+		if err := restarted.CheckLimits(apiKey); !errors.Is(err, ErrQuotaExceeded) {
+			t.Errorf("Expected ErrQuotaExceeded after a restart, got %v", err)
+		}
+	})
+
+	t.Run("ByteQuota", func(t *testing.T) {
+		apiKey := &ApiKey{ID: "BYTES", Limits: Limits{DailyBytes: 100}}
+		now := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+		manager.usage.record(apiKey, 100, now)
+
+		err := manager.usage.allow(apiKey, now)
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) || limitErr.Kind != KeyQuotaExceeded || limitErr.RetryAfter != time.Hour {
+			t.Fatalf("Expected the quota to reset at midnight, got %v", err)
+		}
+		if err := manager.usage.allow(apiKey, now.Add(time.Hour)); err != nil {
+			t.Errorf("Expected a fresh quota the next day, got %v", err)
+		}
+	})
+}
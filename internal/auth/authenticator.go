@@ -0,0 +1,45 @@
+package auth
+
+// Authenticator resolves bearer tokens of one format, e.g. JWTs, to the
+// ApiKey their requests are authorized with. The returned ApiKey does not
+// have to be stored; its ID only has to be stable for the same client.
+type Authenticator interface {
+	// Accepts reports whether token has the format the authenticator handles.
+	Accepts(token string) bool
+	Authenticate(token string) (*ApiKey, error)
+}
+
+// AddAuthenticator makes Authenticate hand tokens accepted by a to it instead
+// of looking them up as api keys. It must be called before the manager is
+// used to serve requests.
+func (m *ApiKeyManager) AddAuthenticator(a Authenticator) {
+	m.authenticators = append(m.authenticators, a)
+}
+
+// Authenticate resolves a client token with the first authenticator that
+// accepts it, or as an api key. Unlike GetApiKey it also rejects keys that
+// are no longer valid.
+func (m *ApiKeyManager) Authenticate(token string) (*ApiKey, error) {
+	for _, a := range m.authenticators {
+		if a.Accepts(token) {
+			return checkValid(a.Authenticate(token))
+		}
+	}
+	return checkValid(m.GetApiKey(token))
+}
+
+func checkValid(apiKey *ApiKey, err error) (*ApiKey, error) {
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil {
+		return nil, ErrKeyNotFound
+	}
+	if apiKey.Disabled {
+		return nil, ErrKeyDisabled
+	}
+	if apiKey.IsExpired() {
+		return nil, ErrKeyExpired
+	}
+	return apiKey, nil
+}
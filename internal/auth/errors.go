@@ -1,5 +1,10 @@
 package auth
 
+import (
+	"fmt"
+	"time"
+)
+
 type ErrorKind uint8
 
 var (
@@ -7,6 +12,9 @@ var (
 	ErrKeyDisabled  = &KeyError{Kind: KeyDisabled, Message: "key is disabled"}
 	ErrKeyExpired   = &KeyError{Kind: KeyExpired, Message: "key has expired"}
 	ErrKeyMalformed = &KeyError{Kind: KeyMalformed, Message: "malformed key"}
+
+	ErrRateLimited   = &KeyError{Kind: KeyRateLimited, Message: "rate limit exceeded"}
+	ErrQuotaExceeded = &KeyError{Kind: KeyQuotaExceeded, Message: "daily quota exceeded"}
 )
 
 const (
@@ -16,6 +24,8 @@ const (
 	KeyMalformed
 	InternalError
 	KeyCorrupted
+	KeyRateLimited
+	KeyQuotaExceeded
 )
 
 type KeyError struct {
@@ -41,6 +51,10 @@ func (e *KeyError) Error() string {
 		return "internal error"
 	case KeyCorrupted:
 		return "api key corrupted"
+	case KeyRateLimited:
+		return "rate limit exceeded"
+	case KeyQuotaExceeded:
+		return "daily quota exceeded"
 	default:
 		return "unknown error"
 	}
@@ -62,3 +76,20 @@ func NewKeyError(kind ErrorKind, msg string) *KeyError {
 func NewKeyErrorWithCause(kind ErrorKind, msg string, cause error) *KeyError {
 	return &KeyError{Kind: kind, Message: msg, cause: cause}
 }
+
+// LimitError rejects a request that exceeds the limits of its api key. It
+// matches ErrRateLimited or ErrQuotaExceeded.
+type LimitError struct {
+	Kind ErrorKind
+	// RetryAfter is when the request would be allowed again.
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%v, retry after %v", &KeyError{Kind: e.Kind}, e.RetryAfter)
+}
+
+func (e *LimitError) Is(target error) bool {
+	t, ok := target.(*KeyError)
+	return ok && e.Kind == t.Kind
+}
@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksMinReloadInterval throttles reloads triggered by tokens signed with
+	// an unknown key ID.
+	jwksMinReloadInterval = 10 * time.Second
+	jwksFetchTimeout      = 10 * time.Second
+	jwksMaxSize           = 1 << 20
+)
+
+// JWKS is a cached JSON Web Key Set. It is reloaded every refresh interval,
+// and when a token names a key it does not know yet, so rotated signing keys
+// are picked up. Reloads happen at most every jwksMinReloadInterval.
+type JWKS struct {
+	load    func() ([]byte, error)
+	refresh time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]jwk
+	loadedAt  time.Time
+	attemptAt time.Time
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+
+	key crypto.PublicKey
+}
+
+// NewJWKSFile reads the key set from path. The file is read again after
+// refresh, 0 only rereads it for unknown key IDs.
+func NewJWKSFile(path string, refresh time.Duration) (*JWKS, error) {
+	jwks := &JWKS{
+		load:    func() ([]byte, error) { return os.ReadFile(path) },
+		refresh: refresh,
+	}
+	if err := jwks.Reload(); err != nil {
+		return nil, err
+	}
+	return jwks, nil
+}
+
+// NewJWKSURL fetches the key set from url on first use and again after
+// refresh.
+func NewJWKSURL(url string, refresh time.Duration) *JWKS {
+	client := &http.Client{Timeout: jwksFetchTimeout}
+	return &JWKS{
+		load: func() ([]byte, error) {
+			resp, err := client.Get(url)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("unexpected status %s", resp.Status)
+			}
+			return io.ReadAll(io.LimitReader(resp.Body, jwksMaxSize))
+		},
+		refresh: refresh,
+	}
+}
+
+// Reload loads the key set now. On failure the previous keys are kept.
+func (s *JWKS) Reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reload(time.Now())
+}
+
+// reload is called with mu held.
+func (s *JWKS) reload(now time.Time) error {
+	s.attemptAt = now
+
+	data, err := s.load()
+	if err != nil {
+		return fmt.Errorf("failed to load jwks: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+	s.keys = keys
+	s.loadedAt = now
+	return nil
+}
+
+// Key returns the verification key kid for tokens signed with alg. An empty
+// kid matches a set with a single key.
+func (s *JWKS) Key(kid, alg string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	stale := s.keys == nil || (s.refresh > 0 && now.Sub(s.loadedAt) >= s.refresh)
+	if stale && now.Sub(s.attemptAt) >= jwksMinReloadInterval {
+		if err := s.reload(now); err != nil && s.keys == nil {
+			return nil, NewKeyErrorWithCause(InternalError, "failed to load jwks", err)
+		}
+	}
+	if s.keys == nil {
+		return nil, NewKeyError(InternalError, "jwks not loaded")
+	}
+
+	key, ok := s.lookup(kid)
+	if !ok && now.Sub(s.attemptAt) >= jwksMinReloadInterval {
+		if err := s.reload(now); err == nil {
+			key, ok = s.lookup(kid)
+		}
+	}
+	if !ok {
+		return nil, NewKeyError(KeyNotFound, fmt.Sprintf("unknown signing key %q", kid))
+	}
+	if key.Alg != "" && key.Alg != alg {
+		return nil, NewKeyError(KeyNotFound, fmt.Sprintf("signing key %q is not used with %s", kid, alg))
+	}
+	return key.key, nil
+}
+
+func (s *JWKS) lookup(kid string) (jwk, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// parseJWKS returns the signing keys of a key set by ID. Keys of other types
+// or for encryption are skipped.
+func parseJWKS(data []byte) (map[string]jwk, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks: %w", err)
+	}
+
+	keys := make(map[string]jwk, len(set.Keys))
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		publicKey, err := key.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %q: %w", key.Kid, err)
+		}
+		if publicKey == nil {
+			continue
+		}
+		key.key = publicKey
+		keys[key.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("rsa exponent out of range")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		if _, err := key.ECDH(); err != nil {
+			return nil, fmt.Errorf("invalid ec key: %w", err)
+		}
+		return key, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,328 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPermissionsClaim = "permissions"
+	DefaultScopesClaim      = "scopes"
+	// JWTKeyIDPrefix is followed by the subject of a token to form the ID of
+	// the ApiKey its requests are authorized with.
+	JWTKeyIDPrefix = "jwt:"
+)
+
+// KeySet looks up the key a token was signed with, e.g. a JWKS.
+type KeySet interface {
+	Key(kid, alg string) (crypto.PublicKey, error)
+}
+
+type JWTOptions struct {
+	// Issuer and Audience, if set, must match the iss and aud claims.
+	Issuer   string
+	Audience string
+	// PermissionsClaim holds the global permissions, as an array or a space
+	// separated string. Values that are no Permission are ignored.
+	PermissionsClaim string
+	// ScopesClaim holds an array of {"prefix": ..., "permissions": [...]}.
+	ScopesClaim string
+	// Leeway tolerates clock skew when checking exp and nbf.
+	Leeway time.Duration
+}
+
+// JWTAuthenticator accepts signed JWTs and maps their claims to permissions
+// and scopes. Tokens must have a subject and an expiration time.
+type JWTAuthenticator struct {
+	keys KeySet
+	opts JWTOptions
+}
+
+func NewJWTAuthenticator(keys KeySet, opts JWTOptions) *JWTAuthenticator {
+	if opts.PermissionsClaim == "" {
+		opts.PermissionsClaim = DefaultPermissionsClaim
+	}
+	if opts.ScopesClaim == "" {
+		opts.ScopesClaim = DefaultScopesClaim
+	}
+	return &JWTAuthenticator{keys: keys, opts: opts}
+}
+
+// Accepts reports whether token is a compact JWS. Api key tokens contain a
+// single separator.
+func (a *JWTAuthenticator) Accepts(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Issuer    string       `json:"iss"`
+	Subject   string       `json:"sub"`
+	Audience  jwtAudience  `json:"aud"`
+	ExpiresAt *numericDate `json:"exp"`
+	NotBefore *numericDate `json:"nbf"`
+	IssuedAt  *numericDate `json:"iat"`
+}
+
+func (a *JWTAuthenticator) Authenticate(token string) (*ApiKey, error) {
+	payload, err := a.verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims jwtClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, NewKeyErrorWithCause(KeyMalformed, "malformed token claims", err)
+	}
+	if err := a.checkClaims(&claims, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var custom map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &custom); err != nil {
+		return nil, NewKeyErrorWithCause(KeyMalformed, "malformed token claims", err)
+	}
+	perms, err := parsePermissionsClaim(custom[a.opts.PermissionsClaim])
+	if err != nil {
+		return nil, err
+	}
+	scopes, err := parseScopesClaim(custom[a.opts.ScopesClaim])
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := claims.ExpiresAt.Time().Add(a.opts.Leeway)
+	apiKey := &ApiKey{
+		ID:          JWTKeyIDPrefix + claims.Subject,
+		Name:        claims.Subject,
+		Permissions: perms,
+		Scopes:      scopes,
+		ExpiresAt:   &expiresAt,
+	}
+	if claims.IssuedAt != nil {
+		apiKey.CreatedAt = claims.IssuedAt.Time()
+	}
+	return apiKey, nil
+}
+
+// verify checks the signature of token and returns its payload.
+func (a *JWTAuthenticator) verify(token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, NewKeyError(KeyMalformed, "malformed token")
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, NewKeyErrorWithCause(KeyMalformed, "malformed token header", err)
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return nil, NewKeyErrorWithCause(KeyMalformed, "malformed token header", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, NewKeyErrorWithCause(KeyMalformed, "malformed token signature", err)
+	}
+
+	key, err := a.keys.Key(header.Kid, header.Alg)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, NewKeyErrorWithCause(KeyMalformed, "malformed token payload", err)
+	}
+	return payload, nil
+}
+
+func (a *JWTAuthenticator) checkClaims(claims *jwtClaims, now time.Time) error {
+	if claims.Subject == "" {
+		return NewKeyError(KeyMalformed, "token has no subject")
+	}
+	if claims.ExpiresAt == nil {
+		return NewKeyError(KeyMalformed, "token has no expiration time")
+	}
+	if now.After(claims.ExpiresAt.Time().Add(a.opts.Leeway)) {
+		return ErrKeyExpired
+	}
+	if claims.NotBefore != nil && now.Add(a.opts.Leeway).Before(claims.NotBefore.Time()) {
+		return NewKeyError(KeyDisabled, "token is not valid yet")
+	}
+	if a.opts.Issuer != "" && claims.Issuer != a.opts.Issuer {
+		return NewKeyError(KeyNotFound, "token issuer not accepted")
+	}
+	if a.opts.Audience != "" && !slices.Contains(claims.Audience, a.opts.Audience) {
+		return NewKeyError(KeyNotFound, "token audience not accepted")
+	}
+	return nil
+}
+
+// ecdsaCurves is the only curve each ECDSA algorithm may be used with.
+var ecdsaCurves = map[string]string{
+	"ES256": "P-256",
+	"ES384": "P-384",
+	"ES512": "P-521",
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed, signature []byte) error {
+	invalid := NewKeyError(KeyNotFound, "invalid token signature")
+
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		hash = crypto.SHA512
+	case "EdDSA":
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(pub, signed, signature) {
+			return invalid
+		}
+		return nil
+	default:
+		return NewKeyError(KeyMalformed, fmt.Sprintf("unsupported token algorithm %q", alg))
+	}
+
+	hasher := hash.New()
+	hasher.Write(signed)
+	digest := hasher.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		var err error
+		switch alg[0] {
+		case 'R':
+			err = rsa.VerifyPKCS1v15(pub, hash, digest, signature)
+		case 'P':
+			err = rsa.VerifyPSS(pub, hash, digest, signature, nil)
+		default:
+			return invalid
+		}
+		if err != nil {
+			return invalid
+		}
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if pub.Curve.Params().Name != ecdsaCurves[alg] || len(signature) != 2*size {
+			return invalid
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return invalid
+		}
+	default:
+		return invalid
+	}
+	return nil
+}
+
+func parsePermissionsClaim(raw json.RawMessage) ([]Permission, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	var values []string
+	var spaced string
+	if err := json.Unmarshal(raw, &spaced); err == nil {
+		values = strings.Fields(spaced)
+	} else if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, NewKeyErrorWithCause(KeyMalformed, "malformed permissions claim", err)
+	}
+
+	var perms []Permission
+	for _, value := range values {
+		if p, err := ParsePermission(value); err == nil && !slices.Contains(perms, p) {
+			perms = append(perms, p)
+		}
+	}
+	return perms, nil
+}
+
+func parseScopesClaim(raw json.RawMessage) ([]Scope, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	var claimed []struct {
+		Prefix      string   `json:"prefix"`
+		Permissions []string `json:"permissions"`
+	}
+	if err := json.Unmarshal(raw, &claimed); err != nil {
+		return nil, NewKeyErrorWithCause(KeyMalformed, "malformed scopes claim", err)
+	}
+
+	scopes := make([]Scope, 0, len(claimed))
+	for _, c := range claimed {
+		scope := Scope{Prefix: c.Prefix}
+		for _, value := range c.Permissions {
+			p, err := ParsePermission(value)
+			if err != nil {
+				return nil, err
+			}
+			scope.Permissions = append(scope.Permissions, p)
+		}
+		scopes = append(scopes, scope)
+	}
+	if err := validateScopes(scopes); err != nil {
+		return nil, err
+	}
+	return scopes, nil
+}
+
+// jwtAudience is a single audience or an array of them.
+type jwtAudience []string
+
+func (a *jwtAudience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = jwtAudience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// numericDate is a JWT time in seconds since the epoch, possibly fractional.
+type numericDate float64
+
+func (d *numericDate) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return err
+	}
+	*d = numericDate(seconds)
+	return nil
+}
+
+func (d *numericDate) Time() time.Time {
+	seconds, fraction := math.Modf(float64(*d))
+	return time.Unix(int64(seconds), int64(fraction*1e9))
+}
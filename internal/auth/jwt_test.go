@@ -0,0 +1,226 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testSigningKeys struct {
+	rsa   *rsa.PrivateKey
+	ec    *ecdsa.PrivateKey
+	ec384 *ecdsa.PrivateKey
+	ed    ed25519.PrivateKey
+	other *ecdsa.PrivateKey
+}
+
+func newTestSigningKeys(t *testing.T) *testSigningKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ec key: %v", err)
+	}
+	ec384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ec key: %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ed25519 key: %v", err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ec key: %v", err)
+	}
+	return &testSigningKeys{rsa: rsaKey, ec: ecKey, ec384: ec384Key, ed: edKey, other: otherKey}
+}
+
+// writeJWKS writes the public keys, except other, to a JWKS file.
+func (k *testSigningKeys) writeJWKS(t *testing.T) string {
+	t.Helper()
+	b64 := base64.RawURLEncoding.EncodeToString
+	set := map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": b64(k.rsa.N.Bytes()), "e": b64(big.NewInt(int64(k.rsa.E)).Bytes())},
+		{"kty": "EC", "kid": "ec", "alg": "ES256", "crv": "P-256", "x": b64(k.ec.X.FillBytes(make([]byte, 32))), "y": b64(k.ec.Y.FillBytes(make([]byte, 32)))},
+		{"kty": "EC", "kid": "ec384", "crv": "P-384", "x": b64(k.ec384.X.FillBytes(make([]byte, 48))), "y": b64(k.ec384.Y.FillBytes(make([]byte, 48)))},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": b64(k.ed.Public().(ed25519.PublicKey))},
+		{"kty": "oct", "kid": "hmac", "k": b64([]byte("ignored"))},
+	}}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("Failed to encode jwks: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write jwks: %v", err)
+	}
+	return path
+}
+
+func signTestJWT(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("Failed to encode claims: %v", err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	hash := crypto.SHA256
+	if strings.HasSuffix(alg, "384") {
+		hash = crypto.SHA384
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(signed))
+	digest := hasher.Sum(nil)
+
+	var signature []byte
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, []byte(signed))
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		signature = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+	case *rsa.PrivateKey:
+		if alg == "PS256" {
+			signature, err = rsa.SignPSS(rand.Reader, k, hash, digest, nil)
+		} else {
+			signature, err = rsa.SignPKCS1v15(rand.Reader, k, hash, digest)
+		}
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	keys := newTestSigningKeys(t)
+	jwks, err := NewJWKSFile(keys.writeJWKS(t), 0)
+	if err != nil {
+		t.Fatalf("NewJWKSFile failed: %v", err)
+	}
+	authenticator := NewJWTAuthenticator(jwks, JWTOptions{Issuer: "https://idp.example", Audience: "ampkv"})
+
+	claims := func(overrides map[string]any) map[string]any {
+		c := map[string]any{
+			"iss":         "https://idp.example",
+			"aud":         []string{"ampkv", "other"},
+			"sub":         "workload-1",
+			"exp":         time.Now().Add(time.Minute).Unix(),
+			"permissions": "read openid",
+			"scopes":      []map[string]any{{"prefix": "tenant-a:", "permissions": []string{"write"}}},
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+
+	for _, tc := range []struct {
+		alg, kid string
+		key      crypto.Signer
+	}{
+		{"RS256", "rsa", keys.rsa},
+		{"PS256", "rsa", keys.rsa},
+		{"ES256", "ec", keys.ec},
+		{"ES384", "ec384", keys.ec384},
+		{"EdDSA", "ed", keys.ed},
+	} {
+		t.Run(tc.alg, func(t *testing.T) {
+			if !authenticator.Accepts(signTestJWT(t, tc.alg, tc.kid, tc.key, claims(nil))) {
+				t.Fatal("Expected the token to be accepted")
+			}
+			apiKey, err := authenticator.Authenticate(signTestJWT(t, tc.alg, tc.kid, tc.key, claims(nil)))
+			if err != nil {
+				t.Fatalf("Authenticate failed: %v", err)
+			}
+			if apiKey.ID != "jwt:workload-1" || !apiKey.IsValid() {
+				t.Errorf("Unexpected api key %+v", apiKey)
+			}
+			if !apiKey.HasPermission(PermRead) || apiKey.HasPermission(PermWrite) {
+				t.Errorf("Expected only the read permission, got %v", apiKey.Permissions)
+			}
+			if !apiKey.HasPermissionFor("tenant-a:x", PermWrite) || apiKey.HasPermissionFor("tenant-b:x", PermWrite) {
+				t.Errorf("Expected write on tenant-a: only, got %+v", apiKey.Scopes)
+			}
+		})
+	}
+
+	rejected := []struct {
+		name  string
+		token string
+		kind  ErrorKind
+	}{
+		{"Expired", signTestJWT(t, "ES256", "ec", keys.ec, claims(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()})), KeyExpired},
+		{"NoExpiration", signTestJWT(t, "ES256", "ec", keys.ec, claims(map[string]any{"exp": nil})), KeyMalformed},
+		{"NotYetValid", signTestJWT(t, "ES256", "ec", keys.ec, claims(map[string]any{"nbf": time.Now().Add(time.Hour).Unix()})), KeyDisabled},
+		{"WrongIssuer", signTestJWT(t, "ES256", "ec", keys.ec, claims(map[string]any{"iss": "https://evil.example"})), KeyNotFound},
+		{"WrongAudience", signTestJWT(t, "ES256", "ec", keys.ec, claims(map[string]any{"aud": "other"})), KeyNotFound},
+		{"ReservedScope", signTestJWT(t, "ES256", "ec", keys.ec, claims(map[string]any{"scopes": []map[string]any{{"prefix": "internal::", "permissions": []string{"read"}}}})), KeyMalformed},
+		{"UnknownKey", signTestJWT(t, "ES256", "missing", keys.ec, claims(nil)), KeyNotFound},
+		{"ForeignKey", signTestJWT(t, "ES256", "ec", keys.other, claims(nil)), KeyNotFound},
+		{"AlgorithmMismatch", signTestJWT(t, "EdDSA", "ec", keys.ed, claims(nil)), KeyNotFound},
+		{"CurveMismatch", signTestJWT(t, "ES256", "ec384", keys.ec384, claims(nil)), KeyNotFound},
+		{"AlgNone", signTestJWT(t, "none", "ed", keys.ed, claims(nil)), KeyMalformed},
+	}
+	for _, tc := range rejected {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := authenticator.Authenticate(tc.token)
+			var keyErr *KeyError
+			if !errors.As(err, &keyErr) || keyErr.Kind != tc.kind {
+				t.Errorf("Expected error kind %d, got %v", tc.kind, err)
+			}
+		})
+	}
+
+	t.Run("Manager", func(t *testing.T) {
+		manager, err := NewApiKeyManager(newTestAmpKV(t), testSecret)
+		if err != nil {
+			t.Fatalf("NewApiKeyManager failed: %v", err)
+		}
+		manager.AddAuthenticator(authenticator)
+
+		created, err := manager.CreateAPIKey("static-key", []Permission{PermRead}, false, nil)
+		if err != nil {
+			t.Fatalf("CreateAPIKey failed: %v", err)
+		}
+		if apiKey, err := manager.Authenticate(created.Key); err != nil || apiKey.ID != created.ID {
+			t.Errorf("Expected the api key to authenticate, got %v", err)
+		}
+		if apiKey, err := manager.Authenticate(signTestJWT(t, "EdDSA", "ed", keys.ed, claims(nil))); err != nil || apiKey.ID != "jwt:workload-1" {
+			t.Errorf("Expected the jwt to authenticate, got %v", err)
+		}
+
+		if err := manager.DisabledApiKey(created.ID); err != nil {
+			t.Fatalf("DisabledApiKey failed: %v", err)
+		}
+		if _, err := manager.Authenticate(created.Key); !errors.Is(err, ErrKeyDisabled) {
+			t.Errorf("Expected ErrKeyDisabled, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"golang.org/x/time/rate"
+)
+
+const (
+	apiKeyUsagePrefix = "internal::api::usage::"
+	// apiKeyUsageRetention keeps yesterday's counters around for a while,
+	// then lets them expire.
+	apiKeyUsageRetention = 48 * time.Hour
+	usageDayLayout       = "2006-01-02"
+)
+
+// Limits throttle a single api key. Zero values disable the limit.
+type Limits struct {
+	// RequestsPerSecond refills a token bucket holding up to Burst requests.
+	// Burst defaults to RequestsPerSecond, rounded up.
+	RequestsPerSecond float64
+	Burst             int
+	// DailyOps caps the requests and DailyBytes the request and response
+	// bytes per UTC day.
+	DailyOps   int64
+	DailyBytes int64
+}
+
+func (l Limits) validate() error {
+	if l.RequestsPerSecond < 0 || l.Burst < 0 || l.DailyOps < 0 || l.DailyBytes < 0 {
+		return NewKeyError(KeyMalformed, "limits must not be negative")
+	}
+	if l.Burst > 0 && l.RequestsPerSecond == 0 {
+		return NewKeyError(KeyMalformed, "burst requires requests per second")
+	}
+	return nil
+}
+
+func (l Limits) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return max(1, int(math.Ceil(l.RequestsPerSecond)))
+}
+
+func (l Limits) hasQuota() bool {
+	return l.DailyOps > 0 || l.DailyBytes > 0
+}
+
+// Usage counts what an api key did on one UTC day.
+type Usage struct {
+	Day   string
+	Ops   int64
+	Bytes int64
+}
+
+type trackedUsage struct {
+	Usage
+	dirty bool
+}
+
+type keyLimiter struct {
+	limits  Limits
+	limiter *rate.Limiter
+}
+
+// usageTracker enforces Limits. Token buckets live in memory; the daily
+// counters are loaded from the store and written back by flush.
+type usageTracker struct {
+	ampKV *embedded.AmpKV
+
+	mu       sync.Mutex
+	limiters map[string]*keyLimiter
+	usage    map[string]*trackedUsage
+}
+
+func newUsageTracker(ampKV *embedded.AmpKV) *usageTracker {
+	return &usageTracker{
+		ampKV:    ampKV,
+		limiters: make(map[string]*keyLimiter),
+		usage:    make(map[string]*trackedUsage),
+	}
+}
+
+func (t *usageTracker) allow(apiKey *ApiKey, now time.Time) error {
+	limits := apiKey.Limits
+	if limits.RequestsPerSecond == 0 && !limits.hasQuota() {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var usage *trackedUsage
+	if limits.hasQuota() {
+		var err error
+		usage, err = t.today(apiKey.ID, now)
+		if err != nil {
+			return err
+		}
+		if (limits.DailyOps > 0 && usage.Ops >= limits.DailyOps) || (limits.DailyBytes > 0 && usage.Bytes >= limits.DailyBytes) {
+			return &LimitError{Kind: KeyQuotaExceeded, RetryAfter: nextDay(now).Sub(now)}
+		}
+	}
+
+	if limits.RequestsPerSecond > 0 {
+		reservation := t.limiter(apiKey.ID, limits).ReserveN(now, 1)
+		if delay := reservation.DelayFrom(now); delay > 0 {
+			reservation.CancelAt(now)
+			return &LimitError{Kind: KeyRateLimited, RetryAfter: delay}
+		}
+	}
+
+	if usage != nil {
+		usage.Ops++
+		usage.dirty = true
+	}
+	return nil
+}
+
+func (t *usageTracker) record(apiKey *ApiKey, n int64, now time.Time) error {
+	if !apiKey.Limits.hasQuota() || n <= 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage, err := t.today(apiKey.ID, now)
+	if err != nil {
+		return err
+	}
+	usage.Bytes += n
+	usage.dirty = true
+	return nil
+}
+
+// limiter returns the token bucket of id, replacing it when the limits
+// changed. Callers hold mu.
+func (t *usageTracker) limiter(id string, limits Limits) *rate.Limiter {
+	current, ok := t.limiters[id]
+	if !ok || current.limits != limits {
+		current = &keyLimiter{limits: limits, limiter: rate.NewLimiter(rate.Limit(limits.RequestsPerSecond), limits.burst())}
+		t.limiters[id] = current
+	}
+	return current.limiter
+}
+
+// today returns the counters of id for the day of now, loading them from the
+// store on first use. Callers hold mu.
+func (t *usageTracker) today(id string, now time.Time) (*trackedUsage, error) {
+	day := now.UTC().Format(usageDayLayout)
+	if usage, ok := t.usage[id]; ok && usage.Day == day {
+		return usage, nil
+	}
+
+	usage := &trackedUsage{Usage: Usage{Day: day}}
+	val, err := t.ampKV.Get(usageKey(id, day))
+	switch {
+	case err == nil:
+		if err := gob.NewDecoder(bytes.NewReader(val.Data)).Decode(&usage.Usage); err != nil {
+			return nil, NewKeyErrorWithCause(KeyCorrupted, "failed to decode api key usage", err)
+		}
+	case !errors.Is(err, embedded.ErrNotFound):
+		return nil, NewKeyErrorWithCause(InternalError, "failed to load api key usage", err)
+	}
+
+	// Counters of the previous day may not have been flushed yet.
+	if previous, ok := t.usage[id]; ok && previous.dirty {
+		if err := t.write(id, previous.Usage); err != nil {
+			return nil, err
+		}
+	}
+	t.usage[id] = usage
+	return usage, nil
+}
+
+// flush writes the counters changed since the last flush.
+func (t *usageTracker) flush() error {
+	t.mu.Lock()
+	pending := make(map[string]Usage)
+	for id, usage := range t.usage {
+		if usage.dirty {
+			pending[id] = usage.Usage
+			usage.dirty = false
+		}
+	}
+	t.mu.Unlock()
+
+	var firstErr error
+	for id, usage := range pending {
+		if err := t.write(id, usage); err != nil {
+			t.mu.Lock()
+			if current, ok := t.usage[id]; ok && current.Day == usage.Day {
+				current.dirty = true
+			}
+			t.mu.Unlock()
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+func (t *usageTracker) write(id string, usage Usage) error {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(usage); err != nil {
+		return NewKeyErrorWithCause(InternalError, "failed to encode api key usage", err)
+	}
+	if err := t.ampKV.SetWithTTL(usageKey(id, usage.Day), buffer.Bytes(), apiKeyCost, apiKeyUsageRetention); err != nil {
+		return NewKeyErrorWithCause(InternalError, "failed to save api key usage", err)
+	}
+	return nil
+}
+
+func (t *usageTracker) forget(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.limiters, id)
+	delete(t.usage, id)
+}
+
+func usageKey(id, day string) string {
+	return fmt.Sprintf("%s%s::%s", apiKeyUsagePrefix, id, day)
+}
+
+func nextDay(now time.Time) time.Time {
+	year, month, day := now.UTC().Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+}
+
+// CheckLimits counts a request against the limits of apiKey. It returns a
+// *LimitError once they are exceeded.
+func (m *ApiKeyManager) CheckLimits(apiKey *ApiKey) error {
+	return m.usage.allow(apiKey, time.Now())
+}
+
+// RecordBytes adds the request and response bytes of a request to the daily
+// usage of apiKey. If the counters can not be loaded the bytes are dropped;
+// the next CheckLimits reports the failure.
+func (m *ApiKeyManager) RecordBytes(apiKey *ApiKey, n int64) {
+	m.usage.record(apiKey, n, time.Now())
+}
+
+// FlushUsage persists the daily counters so quotas survive restarts.
+func (m *ApiKeyManager) FlushUsage() error {
+	return m.usage.flush()
+}
+
+// RunUsageFlusher calls FlushUsage every interval, and once more when ctx is
+// done. Errors are passed to onError, which may be nil.
+func (m *ApiKeyManager) RunUsageFlusher(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := m.FlushUsage(); err != nil && onError != nil {
+				onError(err)
+			}
+			return
+		case <-ticker.C:
+			if err := m.FlushUsage(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+func (m *ApiKeyManager) SetLimits(id string, limits Limits) error {
+	if err := limits.validate(); err != nil {
+		return err
+	}
+	return m.update(id, func(apiKey *ApiKey) error {
+		apiKey.Limits = limits
+		return nil
+	})
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const secretLength = 32
+
+// LoadOrCreateSecret reads the key used to hash api keys from path. If the
+// file does not exist a random secret is generated and written to it with
+// 0600 permissions. Losing the file invalidates every api key.
+func LoadOrCreateSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		secret := []byte(strings.TrimSpace(string(data)))
+		if len(secret) < apiKeyMinSecretLength {
+			return nil, fmt.Errorf("auth secret in %s must be at least %d bytes long", path, apiKeyMinSecretLength)
+		}
+		return secret, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read auth secret: %w", err)
+	}
+
+	random := make([]byte, secretLength)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate auth secret: %w", err)
+	}
+	secret := fmt.Appendf(nil, "%x", random)
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create auth secret directory: %w", err)
+		}
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auth secret: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(secret, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to write auth secret: %w", err)
+	}
+	return secret, file.Sync()
+}
@@ -0,0 +1,354 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"gopkg.in/yaml.v3"
+)
+
+const envPrefix = "AMPKV_"
+
+type Config struct {
+	GRPC    GRPCConfig    `yaml:"grpc"`
+	HTTP    HTTPConfig    `yaml:"http"`
+	Storage StorageConfig `yaml:"storage"`
+	TLS     TLSConfig     `yaml:"tls"`
+	Log     LogConfig     `yaml:"log"`
+	Auth    AuthConfig    `yaml:"auth"`
+	Audit   AuditConfig   `yaml:"audit"`
+}
+
+type GRPCConfig struct {
+	Address string `yaml:"address"`
+	Port    int    `yaml:"port"`
+}
+
+type HTTPConfig struct {
+	Address string `yaml:"address"`
+	// Port defaults to 8080, or 4443 in https mode.
+	Port int `yaml:"port"`
+	// Mode is "http" or "https". In https mode the tls certificates are used,
+	// or, if there are none, certificates are obtained via ACME and cached in
+	// AutocertCacheDir.
+	Mode             string `yaml:"mode"`
+	AutocertCacheDir string `yaml:"autocert_cache_dir"`
+}
+
+type StorageConfig struct {
+	Mode        string        `yaml:"mode"`
+	CacheDriver string        `yaml:"cache_driver"`
+	StoreDriver string        `yaml:"store_driver"`
+	DefaultTTL  time.Duration `yaml:"default_ttl"`
+	// Drivers holds per-driver options, e.g. drivers.ristretto.max-cost.
+	Drivers map[string]map[string]string `yaml:"drivers"`
+}
+
+// TLSConfig enables TLS for gRPC, and for Http in https mode, using
+// certificates from files. The files are reloaded on SIGHUP and when they
+// change.
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// ClientCAFile enables mutual TLS.
+	ClientCAFile string `yaml:"client_ca_file"`
+	// ClientAuth is "require" or "verify-if-given".
+	ClientAuth string `yaml:"client_auth"`
+	// ReloadInterval is how often the files are checked for changes, 0
+	// disables the check.
+	ReloadInterval time.Duration `yaml:"reload_interval"`
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+type LogConfig struct {
+	// Level is one of debug, info, warn or error.
+	Level string `yaml:"level"`
+	// Format is "console" or "json".
+	Format string `yaml:"format"`
+}
+
+type AuthConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// SecretFile holds the key api keys are hashed with. It is created on
+	// first start; losing it invalidates every api key.
+	SecretFile string `yaml:"secret_file"`
+	// BootstrapTokenFile is where the admin token created on first start is
+	// written. If the file already exists its token is used instead. Without
+	// it the token is printed once.
+	BootstrapTokenFile string `yaml:"bootstrap_token_file"`
+	// UsageFlushInterval is how often the daily quota counters of api keys
+	// are persisted. Usage since the last flush is lost on a crash.
+	UsageFlushInterval time.Duration `yaml:"usage_flush_interval"`
+	// StreamRevalidateInterval is how often the api key of an open stream,
+	// such as a watch, is checked again. 0 only checks it once.
+	StreamRevalidateInterval time.Duration `yaml:"stream_revalidate_interval"`
+	JWT                      JWTConfig     `yaml:"jwt"`
+}
+
+// JWTConfig accepts JWTs signed by a key of a JWKS as bearer tokens next to
+// api keys. It is enabled by setting JWKSFile or JWKSURL.
+type JWTConfig struct {
+	JWKSFile string `yaml:"jwks_file"`
+	JWKSURL  string `yaml:"jwks_url"`
+	// JWKSRefreshInterval is how often the key set is loaded again. Unknown
+	// key IDs trigger a reload as well.
+	JWKSRefreshInterval time.Duration `yaml:"jwks_refresh_interval"`
+	// Issuer and Audience, if set, must match the iss and aud claims.
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+	// PermissionsClaim names the claim with the global permissions and
+	// ScopesClaim the one with the prefix scopes.
+	PermissionsClaim string `yaml:"permissions_claim"`
+	ScopesClaim      string `yaml:"scopes_claim"`
+	// Leeway tolerates clock skew when checking expiration times.
+	Leeway time.Duration `yaml:"leeway"`
+}
+
+func (c JWTConfig) Enabled() bool {
+	return c.JWKSFile != "" || c.JWKSURL != ""
+}
+
+// AuditConfig records authenticated requests under internal::audit::. It has
+// no effect while auth is disabled.
+type AuditConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Retention is how long entries are kept, 0 keeps them forever.
+	Retention time.Duration `yaml:"retention"`
+	// Reads also records successful reads.
+	Reads bool `yaml:"reads"`
+}
+
+func Default() *Config {
+	return &Config{
+		GRPC: GRPCConfig{
+			Address: "0.0.0.0",
+			Port:    50051,
+		},
+		HTTP: HTTPConfig{
+			Address:          "0.0.0.0",
+			Mode:             "http",
+			AutocertCacheDir: "/var/www/.cache",
+		},
+		Storage: StorageConfig{
+			Mode:        "default",
+			CacheDriver: "ristretto",
+			StoreDriver: "badger",
+			DefaultTTL:  10 * time.Minute,
+			Drivers:     make(map[string]map[string]string),
+		},
+		TLS: TLSConfig{
+			ClientAuth:     "require",
+			ReloadInterval: 10 * time.Second,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "console",
+		},
+		Auth: AuthConfig{
+			Enabled:                  true,
+			SecretFile:               "ampkv_auth.secret",
+			UsageFlushInterval:       10 * time.Second,
+			StreamRevalidateInterval: 30 * time.Second,
+			JWT: JWTConfig{
+				JWKSRefreshInterval: time.Hour,
+				PermissionsClaim:    "permissions",
+				ScopesClaim:         "scopes",
+				Leeway:              30 * time.Second,
+			},
+		},
+		Audit: AuditConfig{
+			Retention: 30 * 24 * time.Hour,
+		},
+	}
+}
+
+// Load reads the YAML file at path on top of the defaults. Unknown keys are
+// rejected so that typos do not go unnoticed.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// DriverOption returns the value configured for a driver option and whether
+// it was set at all.
+func (c *Config) DriverOption(driver, option string) (string, bool) {
+	value, ok := c.Storage.Drivers[driver][option]
+	return value, ok
+}
+
+func (c *Config) SetDriverOption(driver, option, value string) {
+	if c.Storage.Drivers == nil {
+		c.Storage.Drivers = make(map[string]map[string]string)
+	}
+	if c.Storage.Drivers[driver] == nil {
+		c.Storage.Drivers[driver] = make(map[string]string)
+	}
+	c.Storage.Drivers[driver][option] = value
+}
+
+// Finalize fills in values that depend on other settings and validates the
+// result. It is called once every source has been applied.
+func (c *Config) Finalize() error {
+	if c.HTTP.Port == 0 {
+		c.HTTP.Port = 8080
+		if c.HTTP.Mode == "https" {
+			c.HTTP.Port = 4443
+		}
+	}
+	return c.Validate()
+}
+
+// Validate reports every problem with the configuration at once.
+func (c *Config) Validate() error {
+	var errs []error
+	addErr := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.GRPC.Port < 1 || c.GRPC.Port > 65535 {
+		addErr("grpc.port: %d is not a valid port", c.GRPC.Port)
+	}
+	if c.HTTP.Port < 1 || c.HTTP.Port > 65535 {
+		addErr("http.port: %d is not a valid port", c.HTTP.Port)
+	}
+	if c.GRPC.Port == c.HTTP.Port && c.GRPC.Address == c.HTTP.Address {
+		addErr("grpc and http can not listen on the same address %s:%d", c.GRPC.Address, c.GRPC.Port)
+	}
+	switch c.HTTP.Mode {
+	case "http":
+	case "https":
+		if !c.TLS.Enabled() && c.HTTP.AutocertCacheDir == "" {
+			addErr("http.autocert_cache_dir: must not be empty in https mode")
+		}
+	default:
+		addErr("http.mode: %q must be http or https", c.HTTP.Mode)
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		addErr("tls: cert_file and key_file must be set together")
+	}
+	if c.TLS.ClientCAFile != "" && !c.TLS.Enabled() {
+		addErr("tls.client_ca_file: requires cert_file and key_file")
+	}
+	switch c.TLS.ClientAuth {
+	case "require", "verify-if-given":
+	default:
+		addErr("tls.client_auth: %q must be require or verify-if-given", c.TLS.ClientAuth)
+	}
+	if c.TLS.ReloadInterval < 0 {
+		addErr("tls.reload_interval: must not be negative")
+	}
+
+	mode, err := embedded.ParseAmpKVStorageMode(c.Storage.Mode)
+	if err != nil {
+		addErr("storage.mode: %w", err)
+	}
+	if c.Storage.DefaultTTL < 0 {
+		addErr("storage.default_ttl: must not be negative")
+	}
+	if _, ok := storage.LookupCacheDriver(c.Storage.CacheDriver); !ok && mode != embedded.AmpKVStorageModeStoreOnly {
+		addErr("storage.cache_driver: unknown driver %q", c.Storage.CacheDriver)
+	}
+	if _, ok := storage.LookupStoreDriver(c.Storage.StoreDriver); !ok && mode != embedded.AmpKVStorageModeCacheOnly {
+		addErr("storage.store_driver: unknown driver %q", c.Storage.StoreDriver)
+	}
+	for driver, options := range c.Storage.Drivers {
+		declared, ok := declaredOptions(driver)
+		if !ok {
+			addErr("storage.drivers.%s: unknown driver", driver)
+			continue
+		}
+		for option := range options {
+			if !slices.ContainsFunc(declared, func(o storage.DriverOption) bool { return o.Name == option }) {
+				addErr("storage.drivers.%s.%s: unknown option", driver, option)
+			}
+		}
+	}
+
+	if c.Auth.Enabled && c.Auth.SecretFile == "" {
+		addErr("auth.secret_file: must not be empty when auth is enabled")
+	}
+	if c.Auth.UsageFlushInterval <= 0 {
+		addErr("auth.usage_flush_interval: must be positive")
+	}
+	if c.Auth.StreamRevalidateInterval < 0 {
+		addErr("auth.stream_revalidate_interval: must not be negative")
+	}
+	if c.Auth.JWT.JWKSFile != "" && c.Auth.JWT.JWKSURL != "" {
+		addErr("auth.jwt: jwks_file and jwks_url can not be set together")
+	}
+	if c.Auth.JWT.JWKSRefreshInterval < 0 {
+		addErr("auth.jwt.jwks_refresh_interval: must not be negative")
+	}
+	if c.Auth.JWT.Leeway < 0 {
+		addErr("auth.jwt.leeway: must not be negative")
+	}
+	if c.Audit.Retention < 0 {
+		addErr("audit.retention: must not be negative")
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		addErr("log.level: %q must be debug, info, warn or error", c.Log.Level)
+	}
+	switch c.Log.Format {
+	case "console", "json":
+	default:
+		addErr("log.format: %q must be console or json", c.Log.Format)
+	}
+
+	return errors.Join(errs...)
+}
+
+// YAML renders the configuration in the format read by Load.
+func (c *Config) YAML() ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// declaredOptions returns the options of the cache or store driver name.
+func declaredOptions(name string) ([]storage.DriverOption, bool) {
+	if driver, ok := storage.LookupCacheDriver(name); ok {
+		return driver.Options, true
+	}
+	if driver, ok := storage.LookupStoreDriver(name); ok {
+		return driver.Options, true
+	}
+	return nil, false
+}
+
+// envName maps a config path like "storage.default_ttl" to AMPKV_STORAGE_DEFAULT_TTL.
+func envName(path string) string {
+	return envPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(path))
+}
@@ -0,0 +1,179 @@
+package config_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/Unfield/AmpKV/drivers/cache/ristretto"
+	_ "github.com/Unfield/AmpKV/drivers/store/badger"
+	_ "github.com/Unfield/AmpKV/drivers/store/sqlite"
+	"github.com/Unfield/AmpKV/internal/config"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ampkv.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestResolvePrecedence(t *testing.T) {
+	path := writeConfig(t, `
+grpc:
+  port: 6000
+http:
+  port: 6001
+storage:
+  store_driver: sqlite
+  default_ttl: 1m
+  drivers:
+    sqlite:
+      path: file.sqlite
+log:
+  level: debug
+`)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := config.RegisterFlags(fs)
+	if err := fs.Parse([]string{"--grpc-port=7000", "--sqlite-path=flag.sqlite"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+
+	environ := []string{
+		"AMPKV_GRPC_PORT=6500",
+		"AMPKV_LOG_LEVEL=warn",
+		"AMPKV_STORAGE_DRIVERS_SQLITE_REAP_INTERVAL=30s",
+		"UNRELATED=1",
+	}
+
+	cfg, err := config.Resolve(path, environ, flags)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	if cfg.GRPC.Port != 7000 {
+		t.Errorf("Expected flag to win for grpc.port, got %d", cfg.GRPC.Port)
+	}
+	if cfg.HTTP.Port != 6001 {
+		t.Errorf("Expected http.port from file, got %d", cfg.HTTP.Port)
+	}
+	if cfg.Log.Level != "warn" {
+		t.Errorf("Expected env to win for log.level, got '%s'", cfg.Log.Level)
+	}
+	if cfg.Storage.DefaultTTL != time.Minute {
+		t.Errorf("Expected default_ttl from file, got %v", cfg.Storage.DefaultTTL)
+	}
+	if value, _ := cfg.DriverOption("sqlite", "path"); value != "flag.sqlite" {
+		t.Errorf("Expected sqlite path from flag, got '%s'", value)
+	}
+	if value, _ := cfg.DriverOption("sqlite", "reap-interval"); value != "30s" {
+		t.Errorf("Expected sqlite reap-interval from env, got '%s'", value)
+	}
+	if cfg.GRPC.Address != "0.0.0.0" || cfg.Storage.CacheDriver != "ristretto" {
+		t.Errorf("Expected defaults for unset values, got %+v", cfg)
+	}
+}
+
+func TestResolveDefaults(t *testing.T) {
+	cfg, err := config.Resolve("", nil, nil)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if cfg.HTTP.Port != 8080 {
+		t.Errorf("Expected http port 8080, got %d", cfg.HTTP.Port)
+	}
+
+	cfg, err = config.Resolve("", []string{"AMPKV_HTTP_MODE=https"}, nil)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if cfg.HTTP.Port != 4443 {
+		t.Errorf("Expected http port 4443 in https mode, got %d", cfg.HTTP.Port)
+	}
+}
+
+func TestResolveDeprecatedDBPath(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := config.RegisterFlags(fs)
+	if err := fs.Parse([]string{"--store-driver=sqlite", "--db-path=old.sqlite"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+
+	cfg, err := config.Resolve("", nil, flags)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if value, _ := cfg.DriverOption("sqlite", "path"); value != "old.sqlite" {
+		t.Errorf("Expected --db-path to set the sqlite path, got '%s'", value)
+	}
+}
+
+func TestResolveInvalid(t *testing.T) {
+	path := writeConfig(t, `
+grpc:
+  port: 70000
+storage:
+  mode: somewhere
+  cache_driver: memcached
+  drivers:
+    badger:
+      compression: zstd
+tls:
+  key_file: server.key
+  client_auth: maybe
+log:
+  format: xml
+`)
+
+	_, err := config.Resolve(path, nil, nil)
+	if err == nil {
+		t.Fatal("Expected an error for an invalid configuration")
+	}
+	for _, want := range []string{"grpc.port", "storage.mode", "storage.cache_driver", "storage.drivers.badger.compression", "tls: cert_file and key_file", "tls.client_auth", "log.format"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %s, got: %v", want, err)
+		}
+	}
+
+	if _, err := config.Resolve("", []string{"AMPKV_GRPC_PORT=abc"}, nil); err == nil {
+		t.Error("Expected an error for a malformed environment variable")
+	}
+}
+
+func TestLoadUnknownKey(t *testing.T) {
+	path := writeConfig(t, "grcp:\n  port: 6000\n")
+
+	if _, err := config.Load(path); err == nil {
+		t.Fatal("Expected an error for an unknown key")
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	cfg := config.Default()
+	cfg.SetDriverOption("ristretto", "max-cost", "1024")
+	if err := cfg.Finalize(); err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+
+	out, err := cfg.YAML()
+	if err != nil {
+		t.Fatalf("YAML failed: %v", err)
+	}
+
+	loaded, err := config.Load(writeConfig(t, string(out)))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if value, _ := loaded.DriverOption("ristretto", "max-cost"); value != "1024" {
+		t.Errorf("Expected max-cost to survive a round trip, got '%s'", value)
+	}
+	if loaded.Storage.DefaultTTL != cfg.Storage.DefaultTTL {
+		t.Errorf("Expected default_ttl %v, got %v", cfg.Storage.DefaultTTL, loaded.Storage.DefaultTTL)
+	}
+}
@@ -0,0 +1,268 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+)
+
+// field binds a config value to its path in the file, its environment
+// variable (derived from the path) and its command line flag.
+type field struct {
+	path  string
+	flag  string
+	usage string
+	value any
+}
+
+func (c *Config) fields() []field {
+	return []field{
+		{"grpc.address", "grpc-address", "Address the gRPC server binds to", &c.GRPC.Address},
+		{"grpc.port", "grpc-port", "The gRPC server port", &c.GRPC.Port},
+		{"http.address", "http-address", "Address the Http server binds to", &c.HTTP.Address},
+		{"http.port", "http-port", "The Http server port (default 8080, or 4443 in https mode)", &c.HTTP.Port},
+		{"http.mode", "http-mode", "Http/Https mode for the Http Server", &c.HTTP.Mode},
+		{"http.autocert_cache_dir", "http-autocert-cache-dir", "Directory for ACME certificates in https mode", &c.HTTP.AutocertCacheDir},
+		{"storage.mode", "storage-mode", "Storage mode: default, cache-only or store-only", &c.Storage.Mode},
+		{"storage.cache_driver", "cache-driver", "Cache driver, one of: " + strings.Join(cacheDriverNames(), ", "), &c.Storage.CacheDriver},
+		{"storage.store_driver", "store-driver", "Store driver, one of: " + strings.Join(storeDriverNames(), ", "), &c.Storage.StoreDriver},
+		{"storage.default_ttl", "default-ttl", "TTL of values copied from the store into the cache", &c.Storage.DefaultTTL},
+		{"tls.cert_file", "tls-cert-file", "TLS certificate file, enables TLS for gRPC and https mode", &c.TLS.CertFile},
+		{"tls.key_file", "tls-key-file", "TLS private key file", &c.TLS.KeyFile},
+		{"tls.client_ca_file", "tls-client-ca-file", "CA file to verify client certificates against (mutual TLS)", &c.TLS.ClientCAFile},
+		{"tls.client_auth", "tls-client-auth", "Client certificate policy: require or verify-if-given", &c.TLS.ClientAuth},
+		{"tls.reload_interval", "tls-reload-interval", "How often to check the TLS files for changes, 0 to disable", &c.TLS.ReloadInterval},
+		{"log.level", "log-level", "Log level: debug, info, warn or error", &c.Log.Level},
+		{"log.format", "log-format", "Log format: console or json", &c.Log.Format},
+		{"auth.enabled", "auth-enabled", "Require an api key for every request", &c.Auth.Enabled},
+		{"auth.secret_file", "auth-secret-file", "File with the key api keys are hashed with, created if missing", &c.Auth.SecretFile},
+		{"auth.bootstrap_token_file", "auth-bootstrap-token-file", "File the first admin token is written to, or read from if it exists", &c.Auth.BootstrapTokenFile},
+		{"auth.usage_flush_interval", "auth-usage-flush-interval", "How often api key quota usage is persisted", &c.Auth.UsageFlushInterval},
+		{"auth.stream_revalidate_interval", "auth-stream-revalidate-interval", "How often the api key of an open stream is checked again, 0 to disable", &c.Auth.StreamRevalidateInterval},
+		{"auth.jwt.jwks_file", "auth-jwt-jwks-file", "JWKS file to verify JWT bearer tokens with", &c.Auth.JWT.JWKSFile},
+		{"auth.jwt.jwks_url", "auth-jwt-jwks-url", "JWKS URL to verify JWT bearer tokens with", &c.Auth.JWT.JWKSURL},
+		{"auth.jwt.jwks_refresh_interval", "auth-jwt-jwks-refresh-interval", "How often the JWKS is loaded again, 0 only on unknown key IDs", &c.Auth.JWT.JWKSRefreshInterval},
+		{"auth.jwt.issuer", "auth-jwt-issuer", "Required iss claim of JWTs", &c.Auth.JWT.Issuer},
+		{"auth.jwt.audience", "auth-jwt-audience", "Required aud claim of JWTs", &c.Auth.JWT.Audience},
+		{"auth.jwt.permissions_claim", "auth-jwt-permissions-claim", "JWT claim with the global permissions", &c.Auth.JWT.PermissionsClaim},
+		{"auth.jwt.scopes_claim", "auth-jwt-scopes-claim", "JWT claim with the prefix scopes", &c.Auth.JWT.ScopesClaim},
+		{"auth.jwt.leeway", "auth-jwt-leeway", "Clock skew tolerated when checking JWT expiration", &c.Auth.JWT.Leeway},
+		{"audit.enabled", "audit-enabled", "Record authenticated requests in the audit log", &c.Audit.Enabled},
+		{"audit.retention", "audit-retention", "How long audit entries are kept, 0 to keep them forever", &c.Audit.Retention},
+		{"audit.reads", "audit-reads", "Also record successful reads in the audit log", &c.Audit.Reads},
+	}
+}
+
+// driverField is an option of a registered driver. It lives under
+// storage.drivers.<driver>.<option> and uses the flag <driver>-<option>.
+type driverField struct {
+	driver string
+	option storage.DriverOption
+}
+
+func (f driverField) path() string { return "storage.drivers." + f.driver + "." + f.option.Name }
+func (f driverField) flag() string { return f.driver + "-" + f.option.Name }
+
+func driverFields() []driverField {
+	var fields []driverField
+	for _, driver := range storage.CacheDrivers() {
+		for _, option := range driver.Options {
+			fields = append(fields, driverField{driver: driver.Name, option: option})
+		}
+	}
+	for _, driver := range storage.StoreDrivers() {
+		for _, option := range driver.Options {
+			fields = append(fields, driverField{driver: driver.Name, option: option})
+		}
+	}
+	return fields
+}
+
+// ApplyEnv overrides the configuration with AMPKV_* variables from environ,
+// e.g. AMPKV_GRPC_PORT or AMPKV_STORAGE_DRIVERS_BADGER_PATH.
+func (c *Config) ApplyEnv(environ []string) error {
+	env := make(map[string]string, len(environ))
+	for _, entry := range environ {
+		if name, value, ok := strings.Cut(entry, "="); ok && strings.HasPrefix(name, envPrefix) {
+			env[name] = value
+		}
+	}
+
+	for _, f := range c.fields() {
+		if value, ok := env[envName(f.path)]; ok {
+			if err := setValue(f.value, value); err != nil {
+				return fmt.Errorf("%s: %w", envName(f.path), err)
+			}
+		}
+	}
+	for _, f := range driverFields() {
+		if value, ok := env[envName(f.path())]; ok {
+			c.SetDriverOption(f.driver, f.option.Name, value)
+		}
+	}
+	return nil
+}
+
+// Flags are the command line flags of every config value. Only flags that
+// were given on the command line override the configuration.
+type Flags struct {
+	values       map[string]*flagValue
+	driverValues map[string]*flagValue
+	dbPath       *flagValue
+}
+
+type flagValue struct {
+	value string
+	set   bool
+}
+
+func (v *flagValue) String() string { return v.value }
+
+func (v *flagValue) Set(value string) error {
+	v.value = value
+	v.set = true
+	return nil
+}
+
+// RegisterFlags defines a flag for every config value on fs, using the
+// defaults for the help output.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	flags := &Flags{
+		values:       make(map[string]*flagValue),
+		driverValues: make(map[string]*flagValue),
+		dbPath:       &flagValue{},
+	}
+
+	for _, f := range Default().fields() {
+		value := &flagValue{value: formatValue(f.value)}
+		flags.values[f.path] = value
+		fs.Var(value, f.flag, f.usage)
+	}
+	for _, f := range driverFields() {
+		value := &flagValue{value: f.option.Default}
+		flags.driverValues[f.path()] = value
+		fs.Var(value, f.flag(), f.option.Usage)
+	}
+	fs.Var(flags.dbPath, "db-path", "Deprecated: use --<store-driver>-path")
+
+	return flags
+}
+
+// Apply overrides the configuration with the flags that were set.
+func (f *Flags) Apply(c *Config) error {
+	for _, field := range c.fields() {
+		value := f.values[field.path]
+		if value == nil || !value.set {
+			continue
+		}
+		if err := setValue(field.value, value.value); err != nil {
+			return fmt.Errorf("--%s: %w", field.flag, err)
+		}
+	}
+	for _, field := range driverFields() {
+		value := f.driverValues[field.path()]
+		if value == nil || !value.set {
+			continue
+		}
+		c.SetDriverOption(field.driver, field.option.Name, value.value)
+	}
+
+	if f.dbPath.set {
+		if _, ok := storage.LookupStoreDriver(c.Storage.StoreDriver); !ok {
+			return fmt.Errorf("--db-path: unknown store driver %q", c.Storage.StoreDriver)
+		}
+		c.SetDriverOption(c.Storage.StoreDriver, "path", f.dbPath.value)
+	}
+	return nil
+}
+
+func setValue(target any, value string) error {
+	switch t := target.(type) {
+	case *string:
+		*t = value
+	case *int:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		*t = parsed
+	case *bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		*t = parsed
+	case *time.Duration:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", value)
+		}
+		*t = parsed
+	default:
+		return fmt.Errorf("unsupported config type %T", target)
+	}
+	return nil
+}
+
+func formatValue(target any) string {
+	switch t := target.(type) {
+	case *string:
+		return *t
+	case *int:
+		return strconv.Itoa(*t)
+	case *bool:
+		return strconv.FormatBool(*t)
+	case *time.Duration:
+		return t.String()
+	default:
+		return ""
+	}
+}
+
+func cacheDriverNames() []string {
+	var names []string
+	for _, driver := range storage.CacheDrivers() {
+		names = append(names, driver.Name)
+	}
+	return names
+}
+
+func storeDriverNames() []string {
+	var names []string
+	for _, driver := range storage.StoreDrivers() {
+		names = append(names, driver.Name)
+	}
+	return names
+}
+
+// Resolve builds the configuration from every source, in increasing order of
+// precedence: defaults, the file at path (if any), the environment and the
+// flags that were set.
+func Resolve(path string, environ []string, flags *Flags) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		var err error
+		cfg, err = Load(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.ApplyEnv(environ); err != nil {
+		return nil, err
+	}
+	if flags != nil {
+		if err := flags.Apply(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.Finalize(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"fmt"
 	"log"
 
 	"go.uber.org/zap"
@@ -17,6 +18,34 @@ func InitLogger() {
 	zap.ReplaceGlobals(baseLogger)
 }
 
+// Configure replaces the logger with one using the given level ("debug",
+// "info", "warn" or "error") and format ("console" or "json").
+func Configure(level, format string) error {
+	atomicLevel, err := zap.ParseAtomicLevel(level)
+	if err != nil {
+		return fmt.Errorf("failed to parse log level: %w", err)
+	}
+
+	var config zap.Config
+	switch format {
+	case "json":
+		config = zap.NewProductionConfig()
+	case "console":
+		config = zap.NewDevelopmentConfig()
+	default:
+		return fmt.Errorf("unknown log format %q", format)
+	}
+	config.Level = atomicLevel
+
+	logger, err := config.Build()
+	if err != nil {
+		return fmt.Errorf("failed to initialize zap: %w", err)
+	}
+	baseLogger = logger
+	zap.ReplaceGlobals(baseLogger)
+	return nil
+}
+
 func GetLogger() *zap.Logger {
 	if baseLogger == nil {
 		InitLogger()
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Unfield/AmpKV/drivers/cache/ristretto"
+	"github.com/Unfield/AmpKV/drivers/store/sqlite"
+	"github.com/Unfield/AmpKV/internal/auth"
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestManager(t *testing.T) (*embedded.AmpKV, *auth.ApiKeyManager, string) {
+	t.Helper()
+	cache, err := ristretto.NewRistrettoCache(1e4, 1<<20, 64)
+	if err != nil {
+		t.Fatalf("Failed to initialize Cache: %v", err)
+	}
+	store, err := sqlite.NewSQLiteStore(filepath.Join(t.TempDir(), "admin.sqlite"), time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to initialize Store: %v", err)
+	}
+	ampkv, err := embedded.NewAmpKV(cache, store, embedded.AmpKVOptions{})
+	if err != nil {
+		t.Fatalf("Failed to initialize AmpKV: %v", err)
+	}
+	t.Cleanup(func() { ampkv.Close() })
+
+	manager, err := auth.NewApiKeyManager(ampkv, []byte("admin-test-secret-0123456789"))
+	if err != nil {
+		t.Fatalf("Failed to initialize api key manager: %v", err)
+	}
+	admin, err := manager.CreateAPIKey("admin-key", []auth.Permission{auth.PermAdmin}, false, nil)
+	if err != nil {
+		t.Fatalf("Failed to create admin key: %v", err)
+	}
+	return ampkv, manager, admin.Key
+}
+
+func doRequest(t *testing.T, handler http.Handler, method, path, token, body string, out any) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if out != nil && rec.Code < 300 {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: failed to decode response %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func TestHttpAdminKeys(t *testing.T) {
+	ampkv, manager, adminToken := newTestManager(t)
+	handler := NewAmpKVHttpServer(ampkv, manager, nil).e
+
+	var created apiKeySuccessResponse
+	code := doRequest(t, handler, http.MethodPost, "/api/v1/admin/keys", adminToken, `{"name":"reader-key","permissions":["read"]}`, &created)
+	if code != http.StatusCreated || created.Token == "" || created.Key.ID == "" {
+		t.Fatalf("Expected key to be created, got %d %+v", code, created)
+	}
+	readerToken := created.Token
+
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/admin/keys", adminToken, `{"name":"broken-key","permissions":["everything"]}`, nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown permission, got %d", code)
+	}
+
+	t.Run("RequiresAdmin", func(t *testing.T) {
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/admin/keys", readerToken, "", nil); code != http.StatusForbidden {
+			t.Errorf("Expected 403 for a non-admin key, got %d", code)
+		}
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/admin/keys", "", "", nil); code != http.StatusUnauthorized {
+			t.Errorf("Expected 401 without a key, got %d", code)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		var first apiKeyListSuccessResponse
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/admin/keys?limit=1", adminToken, "", &first); code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", code)
+		}
+		if len(first.Keys) != 1 || first.NextCursor == "" {
+			t.Fatalf("Expected one key and a cursor, got %+v", first)
+		}
+
+		var second apiKeyListSuccessResponse
+		doRequest(t, handler, http.MethodGet, "/api/v1/admin/keys?limit=1&cursor="+first.NextCursor, adminToken, "", &second)
+		if len(second.Keys) != 1 || second.NextCursor != "" || second.Keys[0].ID == first.Keys[0].ID {
+			t.Fatalf("Expected the other key on the last page, got %+v", second)
+		}
+	})
+
+	t.Run("DisableEnable", func(t *testing.T) {
+		var disabled apiKeySuccessResponse
+		doRequest(t, handler, http.MethodPost, "/api/v1/admin/keys/"+created.Key.ID+"/disable", adminToken, "", &disabled)
+		if !disabled.Key.Disabled {
+			t.Errorf("Expected key to be disabled, got %+v", disabled.Key)
+		}
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/some-key", readerToken, "", nil); code != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for a disabled key, got %d", code)
+		}
+
+		doRequest(t, handler, http.MethodPost, "/api/v1/admin/keys/"+created.Key.ID+"/enable", adminToken, "", nil)
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/some-key", readerToken, "", nil); code != http.StatusNotFound {
+			t.Errorf("Expected 404 for an enabled key, got %d", code)
+		}
+	})
+
+	t.Run("Expiration", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		var updated apiKeySuccessResponse
+		doRequest(t, handler, http.MethodPut, "/api/v1/admin/keys/"+created.Key.ID+"/expiration", adminToken, `{"expires_at":"`+expiresAt.Format(time.RFC3339)+`"}`, &updated)
+		if updated.Key.ExpiresAt == nil || !updated.Key.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("Expected expiration %v, got %v", expiresAt, updated.Key.ExpiresAt)
+		}
+
+		var removed apiKeySuccessResponse
+		doRequest(t, handler, http.MethodPut, "/api/v1/admin/keys/"+created.Key.ID+"/expiration", adminToken, `{"expires_at":null}`, &removed)
+		if removed.Key.ExpiresAt != nil {
+			t.Errorf("Expected expiration to be removed, got %v", removed.Key.ExpiresAt)
+		}
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		var rotated apiKeySuccessResponse
+		doRequest(t, handler, http.MethodPost, "/api/v1/admin/keys/"+created.Key.ID+"/rotate", adminToken, "", &rotated)
+		if rotated.Token == "" || rotated.Token == readerToken {
+			t.Fatalf("Expected a new token, got %+v", rotated)
+		}
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/some-key", readerToken, "", nil); code != http.StatusUnauthorized {
+			t.Errorf("Expected the old token to be rejected, got %d", code)
+		}
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/some-key", rotated.Token, "", nil); code != http.StatusNotFound {
+			t.Errorf("Expected the new token to work, got %d", code)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if code := doRequest(t, handler, http.MethodDelete, "/api/v1/admin/keys/"+created.Key.ID, adminToken, "", nil); code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", code)
+		}
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/admin/keys/"+created.Key.ID, adminToken, "", nil); code != http.StatusNotFound {
+			t.Errorf("Expected 404 after delete, got %d", code)
+		}
+	})
+
+	t.Run("DataKeyNamedAdmin", func(t *testing.T) {
+		var writer apiKeySuccessResponse
+		doRequest(t, handler, http.MethodPost, "/api/v1/admin/keys", adminToken, `{"name":"writer-key","permissions":["read","write"]}`, &writer)
+
+		if code := doRequest(t, handler, http.MethodPost, "/api/v1/", writer.Token, `{"key":"admin","value":"data"}`, nil); code != http.StatusCreated {
+			t.Fatalf("Expected 201, got %d", code)
+		}
+		var got getSuccessResponse
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/admin", writer.Token, "", &got); code != http.StatusOK || string(got.Value) != "data" {
+			t.Errorf("Expected the data key 'admin', got %d %+v", code, got)
+		}
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/admin/ttl", writer.Token, "", nil); code != http.StatusOK {
+			t.Errorf("Expected the TTL of the data key 'admin', got %d", code)
+		}
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/admin/keys", writer.Token, "", nil); code != http.StatusForbidden {
+			t.Errorf("Expected the admin routes to still require admin, got %d", code)
+		}
+	})
+}
+
+func TestGrpcAdminService(t *testing.T) {
+	_, manager, adminToken := newTestManager(t)
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(grpc.UnaryInterceptor(AuthUnaryServerInterceptor(manager, nil)))
+	pb.RegisterAdminServiceServer(s, NewAmpKVAdminGrpcServer(manager, nil))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	admin := pb.NewAdminServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	adminCtx := metadata.AppendToOutgoingContext(ctx, apiKeyMetadataKey, adminToken)
+
+	created, err := admin.CreateApiKey(adminCtx, &pb.CreateApiKeyRequest{Name: "writer-key", Permissions: []string{"write"}, TtlSeconds: 3600})
+	if err != nil {
+		t.Fatalf("CreateApiKey failed: %v", err)
+	}
+	if created.Token == "" || created.Key.ExpiresAt == 0 {
+		t.Errorf("Expected a token and an expiration, got %+v", created)
+	}
+
+	writerCtx := metadata.AppendToOutgoingContext(ctx, apiKeyMetadataKey, created.Token)
+	if _, err := admin.ListApiKeys(writerCtx, &pb.ListApiKeysRequest{}); status.Code(err) == codes.OK {
+		t.Error("Expected a non-admin key to be rejected")
+	}
+
+	list, err := admin.ListApiKeys(adminCtx, &pb.ListApiKeysRequest{})
+	if err != nil {
+		t.Fatalf("ListApiKeys failed: %v", err)
+	}
+	if len(list.Keys) != 2 {
+		t.Errorf("Expected 2 keys, got %d", len(list.Keys))
+	}
+
+	info, err := admin.SetApiKeyDisabled(adminCtx, &pb.SetApiKeyDisabledRequest{Id: created.Key.Id, Disabled: true})
+	if err != nil || !info.Disabled {
+		t.Errorf("Expected key to be disabled, got %+v, %v", info, err)
+	}
+
+	if _, err := admin.GetApiKey(adminCtx, &pb.ApiKeyIdRequest{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Unfield/AmpKV/internal/audit"
+	"github.com/Unfield/AmpKV/internal/auth"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	auditKeysContextKey = "audit-keys"
+	adminRoutePrefix    = "/api/v1/admin"
+)
+
+func auditGrpcRequest(ctx context.Context, auditLog *audit.Log, apiKeyRecord *auth.ApiKey, fullMethod string, req any, err error) {
+	access, isData := requestKeyAccess(req)
+	read := isData && !strings.HasPrefix(fullMethod, adminServicePrefix)
+	keys := make([]string, 0, len(access))
+	for _, a := range access {
+		read = read && a.perm == auth.PermRead
+		keys = append(keys, a.key)
+	}
+	if !auditLog.Records(read, err == nil) {
+		return
+	}
+
+	if r, ok := req.(interface{ GetId() string }); ok && r.GetId() != "" {
+		keys = append(keys, r.GetId())
+	}
+
+	entry := audit.Entry{
+		KeyID:     apiKeyRecord.ID,
+		Protocol:  "grpc",
+		Operation: fullMethod,
+		Keys:      keys,
+		Result:    status.Code(err).String(),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		entry.Client = p.Addr.String()
+	}
+	auditLog.Record(entry)
+}
+
+func auditHttpRequest(ctx echo.Context, auditLog *audit.Log, apiKeyRecord *auth.ApiKey, err error) {
+	code := ctx.Response().Status
+	if err != nil {
+		code = http.StatusInternalServerError
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			code = httpErr.Code
+		}
+	}
+
+	read := ctx.Request().Method == http.MethodGet && !strings.HasPrefix(ctx.Path(), adminRoutePrefix)
+	if !auditLog.Records(read, code < http.StatusBadRequest) {
+		return
+	}
+
+	keys, _ := ctx.Get(auditKeysContextKey).([]string)
+	if len(keys) == 0 {
+		for _, param := range []string{"key", "id"} {
+			if value := ctx.Param(param); value != "" {
+				keys = append(keys, value)
+			}
+		}
+	}
+
+	auditLog.Record(audit.Entry{
+		KeyID:     apiKeyRecord.ID,
+		Protocol:  "http",
+		Operation: ctx.Request().Method + " " + ctx.Path(),
+		Keys:      keys,
+		Result:    strconv.Itoa(code),
+		Client:    ctx.RealIP(),
+	})
+}
+
+// addAuditKeys remembers the keys a handler touched for the audit entry of
+// the request.
+func addAuditKeys(ctx echo.Context, keys ...string) {
+	existing, _ := ctx.Get(auditKeysContextKey).([]string)
+	ctx.Set(auditKeysContextKey, append(existing, keys...))
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/audit"
+)
+
+func TestHttpAuditLog(t *testing.T) {
+	ampkv, manager, adminToken := newTestManager(t)
+	auditLog := audit.NewLog(ampkv, audit.Options{})
+	t.Cleanup(auditLog.Close)
+	handler := NewAmpKVHttpServer(ampkv, manager, auditLog).e
+
+	var created apiKeySuccessResponse
+	doRequest(t, handler, http.MethodPost, "/api/v1/admin/keys", adminToken, `{"name":"writer-key","permissions":["write","read"]}`, &created)
+	writerID, writerToken := created.Key.ID, created.Token
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/", strings.NewReader(`{"key":"audited","value":"1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+writerToken)
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	req.Header.Set("X-Real-IP", "203.0.113.7")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	doRequest(t, handler, http.MethodGet, "/api/v1/audited", writerToken, "", nil)
+	doRequest(t, handler, http.MethodDelete, "/api/v1/audited", writerToken, "", nil)
+
+	var page auditListSuccessResponse
+	deadline := time.Now().Add(5 * time.Second)
+	for len(page.Entries) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		page = auditListSuccessResponse{}
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/admin/audit?key_id="+writerID, adminToken, "", &page); code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", code)
+		}
+	}
+
+	if len(page.Entries) != 2 {
+		t.Fatalf("Expected the write and the denied delete, got %+v", page.Entries)
+	}
+	set, del := page.Entries[0], page.Entries[1]
+	if set.Operation != "POST /api/v1/" || set.Result != "201" || len(set.Keys) != 1 || set.Keys[0] != "audited" || set.Client != "192.0.2.1" {
+		t.Errorf("Unexpected entry for the write: %+v", set)
+	}
+	if del.Operation != "DELETE /api/v1/:key" || del.Result != "403" || len(del.Keys) != 1 || del.Keys[0] != "audited" {
+		t.Errorf("Unexpected entry for the denied delete: %+v", del)
+	}
+
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/admin/audit?from=yesterday", adminToken, "", nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a malformed time, got %d", code)
+	}
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/admin/audit", writerToken, "", nil); code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a non-admin key, got %d", code)
+	}
+}
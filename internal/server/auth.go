@@ -3,108 +3,245 @@ package server
 import (
 	"context"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/Unfield/AmpKV/internal/audit"
 	"github.com/Unfield/AmpKV/internal/auth"
 	"github.com/labstack/echo/v4"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 const (
-	apiKeyMetadataKey = "api-key"
+	apiKeyMetadataKey  = "api-key"
+	apiKeyContextKey   = "api-key"
+	adminServicePrefix = "/ampkv.AdminService/"
 )
 
-func AuthUnaryServerInterceptor(manager *auth.ApiKeyManager) grpc.UnaryServerInterceptor {
+// AuthUnaryServerInterceptor authenticates every request with an api key, or
+// any token an authenticator added to manager accepts, authorizes it and
+// records it in auditLog, which may be nil.
+func AuthUnaryServerInterceptor(manager *auth.ApiKeyManager, auditLog *audit.Log) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Errorf(codes.Unauthenticated, "authentication required: missing metadata")
+		apiKeyRecord, _, err := authenticateGrpc(ctx, manager)
+		if err != nil {
+			return nil, err
 		}
 
-		apiKeys := md.Get(apiKeyMetadataKey)
-		if len(apiKeys) == 0 {
-			return nil, status.Errorf(codes.Unauthenticated, "authentication failed: api-key missing")
-		}
+		resp, err := serveGrpcRequest(ctx, manager, apiKeyRecord, info, req, handler)
+		auditGrpcRequest(ctx, auditLog, apiKeyRecord, info.FullMethod, req, err)
+		return resp, err
+	}
+}
 
-		clientApiKey := apiKeys[0]
+// authenticateGrpc looks up the api key sent in the request metadata and
+// returns it together with the token.
+func authenticateGrpc(ctx context.Context, manager *auth.ApiKeyManager) (*auth.ApiKey, string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, "", status.Errorf(codes.Unauthenticated, "authentication required: missing metadata")
+	}
 
-		apiKeyRecord, err := manager.GetApiKey(clientApiKey)
-		if err != nil {
-			if errors.Is(err, auth.ErrKeyExpired) || errors.Is(err, auth.ErrKeyDisabled) {
-				return nil, status.Errorf(codes.Unauthenticated, "authentication failed: api-key expired or disabled")
-			}
-			return nil, status.Errorf(codes.Unauthenticated, "authentication error: %v", err)
-		}
+	apiKeys := md.Get(apiKeyMetadataKey)
+	if len(apiKeys) == 0 {
+		return nil, "", status.Errorf(codes.Unauthenticated, "authentication failed: api-key missing")
+	}
 
-		if apiKeyRecord == nil || !apiKeyRecord.IsValid() {
-			return nil, status.Errorf(codes.Unauthenticated, "authentication failed: api-key invalid or expired")
-		}
+	clientApiKey := apiKeys[0]
 
-		requiredPerm := methodToPermission(info.FullMethod)
-		if requiredPerm != "" && !apiKeyRecord.HasPermission(requiredPerm) {
-			return nil, status.Errorf(codes.Unauthenticated, "authorization failed: insufficient permissions for method: %s", info.FullMethod)
-		}
+	apiKeyRecord, err := manager.Authenticate(clientApiKey)
+	if err != nil {
+		return nil, "", apiKeyErrorToStatus(err)
+	}
+	return apiKeyRecord, clientApiKey, nil
+}
 
-		return handler(ctx, req)
+func apiKeyErrorToStatus(err error) error {
+	if errors.Is(err, auth.ErrKeyExpired) || errors.Is(err, auth.ErrKeyDisabled) {
+		return status.Errorf(codes.Unauthenticated, "authentication failed: api-key expired or disabled")
 	}
+	if errors.Is(err, auth.ErrKeyNotFound) || errors.Is(err, auth.ErrKeyMalformed) {
+		return status.Errorf(codes.Unauthenticated, "authentication failed: api-key invalid")
+	}
+	return status.Errorf(codes.Internal, "authentication error: %v", err)
 }
 
-func HttpAuthMiddleware(manager *auth.ApiKeyManager) echo.MiddlewareFunc {
+func serveGrpcRequest(ctx context.Context, manager *auth.ApiKeyManager, apiKeyRecord *auth.ApiKey, info *grpc.UnaryServerInfo, req any, handler grpc.UnaryHandler) (any, error) {
+	if err := authorizeRequest(apiKeyRecord, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	if err := manager.CheckLimits(apiKeyRecord); err != nil {
+		return nil, limitErrorToStatus(err)
+	}
+
+	resp, err := handler(ctx, req)
+	manager.RecordBytes(apiKeyRecord, int64(messageSize(req)+messageSize(resp)))
+	return resp, err
+}
+
+// HttpAuthMiddleware authenticates every request like
+// AuthUnaryServerInterceptor, authorizes it and records it in auditLog, which
+// may be nil.
+func HttpAuthMiddleware(manager *auth.ApiKeyManager, auditLog *audit.Log) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) (err error) {
-			authHeader := ctx.Request().Header.Get("authorization")
-			authHeaderParts := strings.Fields(authHeader)
-			if len(authHeaderParts) != 2 || strings.ToLower(authHeaderParts[0]) != "bearer:" {
+			token, ok := bearerToken(ctx.Request().Header.Get("authorization"))
+			if !ok {
 				return echo.NewHTTPError(http.StatusUnauthorized, "bearer token not found or malformed")
 			}
 
-			apiKeyRecord, err := manager.GetApiKey(authHeaderParts[1])
+			apiKeyRecord, err := manager.Authenticate(token)
 			if err != nil {
 				if errors.Is(err, auth.ErrKeyExpired) || errors.Is(err, auth.ErrKeyDisabled) {
 					return echo.NewHTTPError(http.StatusUnauthorized, "apikey expired or disabled")
 				}
+				if errors.Is(err, auth.ErrKeyNotFound) || errors.Is(err, auth.ErrKeyMalformed) {
+					return echo.NewHTTPError(http.StatusUnauthorized, "apikey invalid")
+				}
 				return echo.NewHTTPError(http.StatusInternalServerError, "failed to verify apikey")
 			}
 
-			if apiKeyRecord == nil || !apiKeyRecord.IsValid() {
-				return echo.NewHTTPError(http.StatusUnauthorized, "apikey invalid or expired")
-			}
+			err = serveHttpRequest(ctx, manager, apiKeyRecord, next)
+			auditHttpRequest(ctx, auditLog, apiKeyRecord, err)
+			return err
+		}
+	}
+}
 
-			requiredPerm := httpMethodToPermission(ctx.Request().Method)
-			if requiredPerm != "" && !apiKeyRecord.HasPermission(requiredPerm) {
-				return echo.NewHTTPError(http.StatusUnauthorized, "insufficent permissions for method: %s", ctx.Request().Method)
-			}
+// bearerToken extracts the token from an RFC 6750 "Bearer <token>"
+// authorization header. The legacy "Bearer: <token>" form is accepted too.
+func bearerToken(header string) (string, bool) {
+	parts := strings.Fields(header)
+	if len(parts) != 2 {
+		return "", false
+	}
+	scheme := strings.TrimSuffix(parts[0], ":")
+	if !strings.EqualFold(scheme, "bearer") {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func serveHttpRequest(ctx echo.Context, manager *auth.ApiKeyManager, apiKeyRecord *auth.ApiKey, next echo.HandlerFunc) error {
+	requiredPerm := httpMethodToPermission(ctx.Request().Method)
+	if ctx.Path() == batchRoute {
+		// A batch may only read; handleBatch authorizes every operation.
+		requiredPerm = ""
+	}
+	if requiredPerm != "" && !apiKeyRecord.HasAnyPermission(requiredPerm) {
+		return echo.NewHTTPError(http.StatusForbidden, "insufficent permissions for method: %s", ctx.Request().Method)
+	}
+	if key := ctx.Param("key"); key != "" && requiredPerm != "" && !apiKeyRecord.HasPermissionFor(key, requiredPerm) {
+		return echo.NewHTTPError(http.StatusForbidden, "insufficent permissions for key: "+key)
+	}
+
+	if err := manager.CheckLimits(apiKeyRecord); err != nil {
+		return limitErrorToHTTPError(ctx, err)
+	}
+
+	ctx.Set(apiKeyContextKey, apiKeyRecord)
+	err := next(ctx)
+	manager.RecordBytes(apiKeyRecord, max(ctx.Request().ContentLength, 0)+ctx.Response().Size)
+	return err
+}
 
+// RequirePermission rejects requests whose api key, as authenticated by
+// HttpAuthMiddleware, lacks perm.
+func RequirePermission(perm auth.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			apiKeyRecord, ok := ctx.Get(apiKeyContextKey).(*auth.ApiKey)
+			if !ok || !apiKeyRecord.HasPermission(perm) {
+				return echo.NewHTTPError(http.StatusForbidden, "insufficent permissions")
+			}
 			return next(ctx)
 		}
 	}
 }
 
-func methodToPermission(fullMethod string) auth.Permission {
-	switch fullMethod {
-	case "/ampkv.AmpKVService/Get":
-		return auth.PermRead
-	case "/ampkv.AmpKVService/Set", "/ampkv.AmpKVService/SetWithTTL":
-		return auth.PermRead
-	case "/ampkv.AmpKVService/Delete":
-		return auth.PermRead
-	default:
-		return ""
+// authorizeRequest checks the permissions a request needs on every key it
+// touches. Admin methods, and anything that is not a known data request,
+// require admin.
+func authorizeRequest(apiKeyRecord *auth.ApiKey, fullMethod string, req any) error {
+	access, ok := requestKeyAccess(req)
+	if strings.HasPrefix(fullMethod, adminServicePrefix) || !ok {
+		if !apiKeyRecord.HasPermission(auth.PermAdmin) {
+			return status.Errorf(codes.PermissionDenied, "authorization failed: insufficient permissions for method: %s", fullMethod)
+		}
+		return nil
+	}
+
+	for _, a := range access {
+		if !apiKeyRecord.HasPermissionFor(a.key, a.perm) {
+			return status.Errorf(codes.PermissionDenied, "authorization failed: %s permission required on key %q", a.perm, a.key)
+		}
 	}
+	return nil
+}
+
+// authorizeKeys checks keys a handler reads from the body or query against
+// the scopes of the authenticated api key. Reserved keys are always refused.
+func authorizeKeys(ctx echo.Context, perm auth.Permission, keys ...string) error {
+	apiKeyRecord, _ := ctx.Get(apiKeyContextKey).(*auth.ApiKey)
+	addAuditKeys(ctx, keys...)
+	for _, key := range keys {
+		if auth.IsReservedKey(key) {
+			return echo.NewHTTPError(http.StatusForbidden, "key prefix "+auth.ReservedPrefix+" is reserved")
+		}
+		if apiKeyRecord != nil && !apiKeyRecord.HasPermissionFor(key, perm) {
+			return echo.NewHTTPError(http.StatusForbidden, "insufficent permissions for key: "+key)
+		}
+	}
+	return nil
+}
+
+func limitErrorToStatus(err error) error {
+	var limitErr *auth.LimitError
+	if !errors.As(err, &limitErr) {
+		return status.Errorf(codes.Internal, "failed to check limits: %v", err)
+	}
+
+	st := status.New(codes.ResourceExhausted, limitErr.Error())
+	if detailed, detailErr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(limitErr.RetryAfter)}); detailErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+func limitErrorToHTTPError(ctx echo.Context, err error) *echo.HTTPError {
+	var limitErr *auth.LimitError
+	if !errors.As(err, &limitErr) {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to check limits")
+	}
+
+	retryAfter := int64(math.Ceil(limitErr.RetryAfter.Seconds()))
+	ctx.Response().Header().Set("Retry-After", strconv.FormatInt(max(retryAfter, 1), 10))
+	return echo.NewHTTPError(http.StatusTooManyRequests, limitErr.Error())
+}
+
+func messageSize(msg any) int {
+	if m, ok := msg.(proto.Message); ok {
+		return proto.Size(m)
+	}
+	return 0
 }
 
 func httpMethodToPermission(method string) auth.Permission {
 	switch method {
 	case "GET":
 		return auth.PermRead
-	case "POST":
-		return auth.PermRead
+	case "POST", "PUT", "PATCH":
+		return auth.PermWrite
 	case "DELETE":
-		return auth.PermRead
+		return auth.PermDelete
 	default:
 		return ""
 	}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/audit"
+	"github.com/Unfield/AmpKV/internal/auth"
+	"google.golang.org/grpc"
+)
+
+// AuthStreamServerInterceptor is the streaming counterpart of
+// AuthUnaryServerInterceptor. Every received message is authorized like a
+// unary request. While the stream is open the api key is looked up again
+// every revalidateInterval, so disabling, expiring, rotating or deleting it
+// ends the stream with Unauthenticated. An interval of 0 disables this.
+func AuthStreamServerInterceptor(manager *auth.ApiKeyManager, auditLog *audit.Log, revalidateInterval time.Duration) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		apiKeyRecord, token, err := authenticateGrpc(ss.Context(), manager)
+		if err != nil {
+			return err
+		}
+		if err := manager.CheckLimits(apiKeyRecord); err != nil {
+			return limitErrorToStatus(err)
+		}
+
+		ctx, cancel := context.WithCancel(ss.Context())
+		defer cancel()
+		stream := &authServerStream{
+			ServerStream: ss,
+			ctx:          ctx,
+			fullMethod:   info.FullMethod,
+			apiKey:       apiKeyRecord,
+		}
+
+		if revalidateInterval > 0 {
+			go stream.revalidate(manager, token, revalidateInterval, cancel)
+		}
+
+		err = handler(srv, stream)
+		if revokeErr := stream.revoked(); revokeErr != nil {
+			err = revokeErr
+		}
+
+		stream.mu.Lock()
+		manager.RecordBytes(apiKeyRecord, stream.bytes)
+		req := stream.req
+		stream.mu.Unlock()
+		auditGrpcRequest(ss.Context(), auditLog, apiKeyRecord, info.FullMethod, req, err)
+		return err
+	}
+}
+
+type authServerStream struct {
+	grpc.ServerStream
+	ctx        context.Context
+	fullMethod string
+
+	mu        sync.Mutex
+	apiKey    *auth.ApiKey
+	req       any
+	bytes     int64
+	revokeErr error
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authServerStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bytes += int64(messageSize(m))
+	if s.req == nil {
+		s.req = m
+	}
+	return authorizeRequest(s.apiKey, s.fullMethod, m)
+}
+
+func (s *authServerStream) SendMsg(m any) error {
+	s.mu.Lock()
+	s.bytes += int64(messageSize(m))
+	s.mu.Unlock()
+	return s.ServerStream.SendMsg(m)
+}
+
+// revalidate cancels the stream once its api key is no longer valid or no
+// longer grants the first request. Lookup failures of the store are not
+// treated as revocation.
+func (s *authServerStream) revalidate(manager *auth.ApiKeyManager, token string, interval time.Duration, cancel context.CancelFunc) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		apiKeyRecord, err := manager.Authenticate(token)
+		var keyErr *auth.KeyError
+		if err != nil && errors.As(err, &keyErr) && keyErr.Kind == auth.InternalError {
+			continue
+		}
+
+		s.mu.Lock()
+		switch {
+		case err != nil:
+			s.revokeErr = apiKeyErrorToStatus(err)
+		default:
+			s.apiKey = apiKeyRecord
+			if s.req != nil {
+				s.revokeErr = authorizeRequest(apiKeyRecord, s.fullMethod, s.req)
+			}
+		}
+		revoked := s.revokeErr != nil
+		s.mu.Unlock()
+
+		if revoked {
+			cancel()
+			return
+		}
+	}
+}
+
+func (s *authServerStream) revoked() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.revokeErr
+}
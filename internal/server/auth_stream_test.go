@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/auth"
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGrpcStreamAuth(t *testing.T) {
+	ampkv, manager, adminToken := newTestManager(t)
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(AuthUnaryServerInterceptor(manager, nil)),
+		grpc.StreamInterceptor(AuthStreamServerInterceptor(manager, nil, 20*time.Millisecond)))
+	pb.RegisterAmpKVServiceServer(s, NewAmpKVGrpcServer(ampkv))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewAmpKVServiceClient(conn)
+
+	apiKey, err := manager.CreateScopedAPIKey("watch-key", nil, []auth.Scope{{Prefix: "tenant-a:", Permissions: []auth.Permission{auth.PermRead}}}, false, nil)
+	if err != nil {
+		t.Fatalf("CreateScopedAPIKey failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	keyCtx := metadata.AppendToOutgoingContext(ctx, apiKeyMetadataKey, apiKey.Key)
+
+	t.Run("MissingKey", func(t *testing.T) {
+		stream, err := client.Scan(ctx, &pb.ScanRequest{})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Expected Unauthenticated, got %v", err)
+		}
+	})
+
+	t.Run("OutOfScope", func(t *testing.T) {
+		stream, err := client.Scan(keyCtx, &pb.ScanRequest{Prefix: "tenant-b:"})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied, got %v", err)
+		}
+	})
+
+	t.Run("ReservedHidden", func(t *testing.T) {
+		adminCtx := metadata.AppendToOutgoingContext(ctx, apiKeyMetadataKey, adminToken)
+		stream, err := client.Scan(adminCtx, &pb.ScanRequest{})
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if resp, err := stream.Recv(); err != io.EOF {
+			t.Errorf("Expected no visible keys, got %v, %v", resp, err)
+		}
+	})
+
+	t.Run("RevokedWhileWatching", func(t *testing.T) {
+		stream, err := client.Watch(keyCtx, &pb.WatchRequest{Key: "tenant-a:", Prefix: true})
+		if err != nil {
+			t.Fatalf("Watch failed: %v", err)
+		}
+		if _, err := stream.Header(); err != nil {
+			t.Fatalf("Watch was not registered: %v", err)
+		}
+
+		if err := manager.DisabledApiKey(apiKey.ID); err != nil {
+			t.Fatalf("DisabledApiKey failed: %v", err)
+		}
+		if _, err := stream.Recv(); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Expected the watch to end with Unauthenticated, got %v", err)
+		}
+	})
+}
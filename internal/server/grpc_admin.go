@@ -0,0 +1,266 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/audit"
+	"github.com/Unfield/AmpKV/internal/auth"
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AmpKVAdminGrpcServer struct {
+	pb.UnimplementedAdminServiceServer
+	manager  *auth.ApiKeyManager
+	auditLog *audit.Log
+}
+
+// NewAmpKVAdminGrpcServer creates the AdminService. A nil auditLog disables
+// QueryAuditLog.
+func NewAmpKVAdminGrpcServer(manager *auth.ApiKeyManager, auditLog *audit.Log) *AmpKVAdminGrpcServer {
+	return &AmpKVAdminGrpcServer{
+		manager:  manager,
+		auditLog: auditLog,
+	}
+}
+
+func (s *AmpKVAdminGrpcServer) CreateApiKey(ctx context.Context, req *pb.CreateApiKeyRequest) (*pb.ApiKeyTokenResponse, error) {
+	perms, err := parsePermissions(req.Permissions)
+	if err != nil {
+		return nil, keyErrorToStatus(err, "CreateApiKeyRequest")
+	}
+	scopes := make([]auth.Scope, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scopePerms, err := parsePermissions(scope.Permissions)
+		if err != nil {
+			return nil, keyErrorToStatus(err, "CreateApiKeyRequest")
+		}
+		scopes = append(scopes, auth.Scope{Prefix: scope.Prefix, Permissions: scopePerms})
+	}
+	if req.TtlSeconds < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "CreateApiKeyRequest: TTL in seconds must not be negative")
+	}
+
+	var ttl *time.Duration
+	if req.TtlSeconds > 0 {
+		d := time.Duration(req.TtlSeconds) * time.Second
+		ttl = &d
+	}
+
+	apiKey, err := s.manager.CreateScopedAPIKey(req.Name, perms, scopes, req.Disabled, ttl)
+	if err != nil {
+		return nil, keyErrorToStatus(err, "failed to create api key")
+	}
+
+	return &pb.ApiKeyTokenResponse{Key: toApiKeyInfo(apiKey), Token: apiKey.Key}, nil
+}
+
+func (s *AmpKVAdminGrpcServer) ListApiKeys(ctx context.Context, req *pb.ListApiKeysRequest) (*pb.ListApiKeysResponse, error) {
+	if req.Limit < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "ListApiKeysRequest: limit must not be negative")
+	}
+
+	apiKeys, nextCursor, err := s.manager.ListApiKeys(req.Cursor, int(req.Limit))
+	if err != nil {
+		return nil, keyErrorToStatus(err, "failed to list api keys")
+	}
+
+	response := &pb.ListApiKeysResponse{
+		Keys:       make([]*pb.ApiKeyInfo, 0, len(apiKeys)),
+		NextCursor: nextCursor,
+	}
+	for _, apiKey := range apiKeys {
+		response.Keys = append(response.Keys, toApiKeyInfo(apiKey))
+	}
+	return response, nil
+}
+
+func (s *AmpKVAdminGrpcServer) GetApiKey(ctx context.Context, req *pb.ApiKeyIdRequest) (*pb.ApiKeyInfo, error) {
+	return s.apiKeyInfo(req.Id)
+}
+
+func (s *AmpKVAdminGrpcServer) SetApiKeyDisabled(ctx context.Context, req *pb.SetApiKeyDisabledRequest) (*pb.ApiKeyInfo, error) {
+	var err error
+	if req.Disabled {
+		err = s.manager.DisabledApiKey(req.Id)
+	} else {
+		err = s.manager.EnableApiKey(req.Id)
+	}
+	if err != nil {
+		return nil, keyErrorToStatus(err, "failed to update api key")
+	}
+	return s.apiKeyInfo(req.Id)
+}
+
+func (s *AmpKVAdminGrpcServer) SetApiKeyExpiration(ctx context.Context, req *pb.SetApiKeyExpirationRequest) (*pb.ApiKeyInfo, error) {
+	var err error
+	if req.ExpiresAt == 0 {
+		err = s.manager.RemoveExpiration(req.Id)
+	} else {
+		err = s.manager.SetExpiration(req.Id, time.Unix(req.ExpiresAt, 0))
+	}
+	if err != nil {
+		return nil, keyErrorToStatus(err, "failed to update api key")
+	}
+	return s.apiKeyInfo(req.Id)
+}
+
+func (s *AmpKVAdminGrpcServer) SetApiKeyLimits(ctx context.Context, req *pb.SetApiKeyLimitsRequest) (*pb.ApiKeyInfo, error) {
+	limits := auth.Limits{
+		RequestsPerSecond: req.GetLimits().GetRequestsPerSecond(),
+		Burst:             int(req.GetLimits().GetBurst()),
+		DailyOps:          req.GetLimits().GetDailyOps(),
+		DailyBytes:        req.GetLimits().GetDailyBytes(),
+	}
+	if err := s.manager.SetLimits(req.Id, limits); err != nil {
+		return nil, keyErrorToStatus(err, "failed to update api key")
+	}
+	return s.apiKeyInfo(req.Id)
+}
+
+func (s *AmpKVAdminGrpcServer) RotateApiKey(ctx context.Context, req *pb.ApiKeyIdRequest) (*pb.ApiKeyTokenResponse, error) {
+	apiKey, err := s.manager.RotateApiKey(req.Id)
+	if err != nil {
+		return nil, keyErrorToStatus(err, "failed to rotate api key")
+	}
+	return &pb.ApiKeyTokenResponse{Key: toApiKeyInfo(apiKey), Token: apiKey.Key}, nil
+}
+
+func (s *AmpKVAdminGrpcServer) DeleteApiKey(ctx context.Context, req *pb.ApiKeyIdRequest) (*pb.OperationResponse, error) {
+	if req.Id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "ApiKeyIdRequest: id must not be empty")
+	}
+
+	if err := s.manager.DeleteKey(req.Id); err != nil {
+		return nil, keyErrorToStatus(err, "failed to delete api key")
+	}
+
+	return &pb.OperationResponse{
+		Success: true,
+		Message: "Api key deleted successfully",
+	}, nil
+}
+
+func (s *AmpKVAdminGrpcServer) QueryAuditLog(ctx context.Context, req *pb.QueryAuditLogRequest) (*pb.QueryAuditLogResponse, error) {
+	if s.auditLog == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "audit log is disabled")
+	}
+	if req.Limit < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "QueryAuditLogRequest: limit must not be negative")
+	}
+
+	query := audit.Query{
+		KeyID:  req.KeyId,
+		Cursor: req.Cursor,
+		Limit:  int(req.Limit),
+	}
+	if req.From != 0 {
+		query.From = time.Unix(req.From, 0)
+	}
+	if req.To != 0 {
+		query.To = time.Unix(req.To, 0)
+	}
+
+	entries, nextCursor, err := s.auditLog.Query(query)
+	if errors.Is(err, audit.ErrInvalidCursor) {
+		return nil, status.Errorf(codes.InvalidArgument, "QueryAuditLogRequest: %v", err)
+	}
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to query audit log")
+	}
+
+	response := &pb.QueryAuditLogResponse{
+		Entries:    make([]*pb.AuditEntry, 0, len(entries)),
+		NextCursor: nextCursor,
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, &pb.AuditEntry{
+			Time:      entry.Time.UnixNano(),
+			KeyId:     entry.KeyID,
+			Protocol:  entry.Protocol,
+			Operation: entry.Operation,
+			Keys:      entry.Keys,
+			Result:    entry.Result,
+			Client:    entry.Client,
+		})
+	}
+	return response, nil
+}
+
+func (s *AmpKVAdminGrpcServer) apiKeyInfo(id string) (*pb.ApiKeyInfo, error) {
+	apiKey, err := s.manager.GetApiKeyByID(id)
+	if err != nil {
+		return nil, keyErrorToStatus(err, "failed to read api key")
+	}
+	return toApiKeyInfo(apiKey), nil
+}
+
+func parsePermissions(names []string) ([]auth.Permission, error) {
+	perms := make([]auth.Permission, 0, len(names))
+	for _, name := range names {
+		perm, err := auth.ParsePermission(name)
+		if err != nil {
+			return nil, err
+		}
+		perms = append(perms, perm)
+	}
+	return perms, nil
+}
+
+func permissionNames(perms []auth.Permission) []string {
+	names := make([]string, 0, len(perms))
+	for _, perm := range perms {
+		names = append(names, string(perm))
+	}
+	return names
+}
+
+func toApiKeyInfo(apiKey *auth.ApiKey) *pb.ApiKeyInfo {
+	info := &pb.ApiKeyInfo{
+		Id:          apiKey.ID,
+		Name:        apiKey.Name,
+		Permissions: permissionNames(apiKey.Permissions),
+		CreatedAt:   apiKey.CreatedAt.Unix(),
+		Disabled:    apiKey.Disabled,
+		Legacy:      apiKey.Legacy,
+	}
+	for _, scope := range apiKey.Scopes {
+		info.Scopes = append(info.Scopes, &pb.ApiKeyScope{Prefix: scope.Prefix, Permissions: permissionNames(scope.Permissions)})
+	}
+	if apiKey.ExpiresAt != nil {
+		info.ExpiresAt = apiKey.ExpiresAt.Unix()
+	}
+	if apiKey.Limits != (auth.Limits{}) {
+		info.Limits = &pb.ApiKeyLimits{
+			RequestsPerSecond: apiKey.Limits.RequestsPerSecond,
+			Burst:             int32(apiKey.Limits.Burst),
+			DailyOps:          apiKey.Limits.DailyOps,
+			DailyBytes:        apiKey.Limits.DailyBytes,
+		}
+	}
+	return info
+}
+
+func keyErrorToStatus(err error, msg string) error {
+	var keyErr *auth.KeyError
+	if !errors.As(err, &keyErr) || keyErr.Kind == auth.InternalError {
+		if cause := errors.Unwrap(err); cause != nil {
+			return storageErrorToStatus(cause, msg)
+		}
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+
+	switch keyErr.Kind {
+	case auth.KeyNotFound:
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case auth.KeyMalformed:
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	case auth.KeyExpired, auth.KeyDisabled:
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxBatchSize = 1000
+
+func (s *AmpKVGrpcServer) MultiGet(ctx context.Context, req *pb.MultiGetRequest) (*pb.MultiGetResponse, error) {
+	if err := validateBatchKeys("MultiGetRequest", req.Keys); err != nil {
+		return nil, err
+	}
+	if err := rejectReservedKeys(req); err != nil {
+		return nil, err
+	}
+
+	values, err := s.store.GetMany(req.Keys)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to get keys from store")
+	}
+
+	results := make([]*pb.GetResponse, 0, len(req.Keys))
+	for _, key := range req.Keys {
+		val, found := values[key]
+		if !found {
+			results = append(results, &pb.GetResponse{Found: false})
+			continue
+		}
+		results = append(results, toGetResponse(key, val))
+	}
+
+	return &pb.MultiGetResponse{
+		Results: results,
+	}, nil
+}
+
+func (s *AmpKVGrpcServer) MultiSet(ctx context.Context, req *pb.MultiSetRequest) (*pb.MultiSetResponse, error) {
+	if len(req.Items) == 0 || len(req.Items) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "MultiSetRequest: between 1 and %d items must be provided", maxBatchSize)
+	}
+
+	if err := rejectReservedKeys(req); err != nil {
+		return nil, err
+	}
+
+	items := make([]embedded.BatchItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		if !isValidKeyValue(item.Kv) {
+			return nil, status.Errorf(codes.InvalidArgument, "MultiSetRequest: key, value and kv fields must be provided")
+		}
+		if item.TtlSeconds < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "MultiSetRequest: TTL in seconds must not be negative")
+		}
+		value, err := valueFromKeyValue("MultiSetRequest", item.Kv)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, embedded.BatchItem{
+			Key:   item.Kv.Key,
+			Value: value,
+			Cost:  item.Kv.Cost,
+			TTL:   time.Duration(item.TtlSeconds) * time.Second,
+		})
+	}
+
+	versions, err := s.store.SetMany(items)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to set keys in store")
+	}
+
+	return &pb.MultiSetResponse{
+		Versions: versions,
+	}, nil
+}
+
+func (s *AmpKVGrpcServer) MultiDelete(ctx context.Context, req *pb.MultiDeleteRequest) (*pb.OperationResponse, error) {
+	if err := validateBatchKeys("MultiDeleteRequest", req.Keys); err != nil {
+		return nil, err
+	}
+	if err := rejectReservedKeys(req); err != nil {
+		return nil, err
+	}
+
+	err := s.store.DeleteMany(req.Keys)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to delete keys from store")
+	}
+
+	return &pb.OperationResponse{
+		Success: true,
+		Message: "Keys deleted successfully",
+	}, nil
+}
+
+func validateBatchKeys(request string, keys []string) error {
+	if len(keys) == 0 || len(keys) > maxBatchSize {
+		return status.Errorf(codes.InvalidArgument, "%s: between 1 and %d keys must be provided", request, maxBatchSize)
+	}
+	for _, key := range keys {
+		if key == "" {
+			return status.Errorf(codes.InvalidArgument, "%s: keys must not be empty", request)
+		}
+	}
+	return nil
+}
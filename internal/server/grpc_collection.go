@@ -0,0 +1,265 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"github.com/Unfield/AmpKV/pkg/common"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// keyedRequest is implemented by every collection request.
+type keyedRequest interface {
+	GetKey() string
+}
+
+// checkCollectionRequest validates the key of a collection request.
+func checkCollectionRequest(name string, req keyedRequest) error {
+	if req.GetKey() == "" {
+		return status.Errorf(codes.InvalidArgument, "%s: key must not be empty", name)
+	}
+	return rejectReservedKeys(req)
+}
+
+// valueFromTyped keeps the type sent by the client like valueFromKeyValue.
+func valueFromTyped(name string, v *pb.TypedValue) (any, error) {
+	if err := checkValueType(name, v.Type); err != nil {
+		return nil, err
+	}
+	if v.Type == pb.AmpKVDataTypeProto_AMP_KV_DATA_TYPE_UNKNOWN {
+		return v.Value, nil
+	}
+	return &common.AmpKVValue{Type: common.AmpKVDataType(v.Type), Data: v.Value}, nil
+}
+
+func toTypedValue(val *common.AmpKVValue) *pb.TypedValue {
+	return &pb.TypedValue{Type: pb.AmpKVDataTypeProto(val.Type), Value: val.Data}
+}
+
+func (s *AmpKVGrpcServer) ListPush(ctx context.Context, req *pb.ListPushRequest) (*pb.CountResponse, error) {
+	if err := checkCollectionRequest("ListPushRequest", req); err != nil {
+		return nil, err
+	}
+	if len(req.Values) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "ListPushRequest: values must not be empty")
+	}
+
+	values := make([]any, 0, len(req.Values))
+	for _, value := range req.Values {
+		if value == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "ListPushRequest: values must not be null")
+		}
+		typed, err := valueFromTyped("ListPushRequest", value)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, typed)
+	}
+
+	var (
+		length int64
+		err    error
+	)
+	if req.Left {
+		length, err = s.store.LPush(req.Key, values...)
+	} else {
+		length, err = s.store.RPush(req.Key, values...)
+	}
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to push to list")
+	}
+	return &pb.CountResponse{Count: length}, nil
+}
+
+func (s *AmpKVGrpcServer) ListPop(ctx context.Context, req *pb.ListPopRequest) (*pb.ListPopResponse, error) {
+	if err := checkCollectionRequest("ListPopRequest", req); err != nil {
+		return nil, err
+	}
+
+	var (
+		value *common.AmpKVValue
+		err   error
+	)
+	if req.Left {
+		value, err = s.store.LPop(req.Key)
+	} else {
+		value, err = s.store.RPop(req.Key)
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		return &pb.ListPopResponse{Found: false}, nil
+	}
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to pop from list")
+	}
+	return &pb.ListPopResponse{Found: true, Value: toTypedValue(value)}, nil
+}
+
+func (s *AmpKVGrpcServer) ListRange(ctx context.Context, req *pb.ListRangeRequest) (*pb.ListRangeResponse, error) {
+	if err := checkCollectionRequest("ListRangeRequest", req); err != nil {
+		return nil, err
+	}
+
+	values, err := s.store.LRange(req.Key, req.Start, req.Stop)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to read list")
+	}
+
+	resp := &pb.ListRangeResponse{Values: make([]*pb.TypedValue, 0, len(values))}
+	for _, value := range values {
+		resp.Values = append(resp.Values, toTypedValue(value))
+	}
+	return resp, nil
+}
+
+func (s *AmpKVGrpcServer) SetAdd(ctx context.Context, req *pb.SetAddRequest) (*pb.CountResponse, error) {
+	if err := checkCollectionRequest("SetAddRequest", req); err != nil {
+		return nil, err
+	}
+
+	added, err := s.store.SAdd(req.Key, req.Members...)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to add to set")
+	}
+	return &pb.CountResponse{Count: int64(added)}, nil
+}
+
+func (s *AmpKVGrpcServer) SetRemove(ctx context.Context, req *pb.SetRemoveRequest) (*pb.CountResponse, error) {
+	if err := checkCollectionRequest("SetRemoveRequest", req); err != nil {
+		return nil, err
+	}
+
+	removed, err := s.store.SRem(req.Key, req.Members...)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to remove from set")
+	}
+	return &pb.CountResponse{Count: int64(removed)}, nil
+}
+
+func (s *AmpKVGrpcServer) SetMembers(ctx context.Context, req *pb.SetMembersRequest) (*pb.SetMembersResponse, error) {
+	if err := checkCollectionRequest("SetMembersRequest", req); err != nil {
+		return nil, err
+	}
+
+	members, err := s.store.SMembers(req.Key)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to read set")
+	}
+	return &pb.SetMembersResponse{Members: members}, nil
+}
+
+func (s *AmpKVGrpcServer) HashSet(ctx context.Context, req *pb.HashSetRequest) (*pb.HashSetResponse, error) {
+	if err := checkCollectionRequest("HashSetRequest", req); err != nil {
+		return nil, err
+	}
+	if req.Value == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "HashSetRequest: value must be provided")
+	}
+
+	value, err := valueFromTyped("HashSetRequest", req.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.store.HSet(req.Key, req.Field, value)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to set hash field")
+	}
+	return &pb.HashSetResponse{Created: created}, nil
+}
+
+func (s *AmpKVGrpcServer) HashGet(ctx context.Context, req *pb.HashGetRequest) (*pb.HashGetResponse, error) {
+	if err := checkCollectionRequest("HashGetRequest", req); err != nil {
+		return nil, err
+	}
+
+	value, err := s.store.HGet(req.Key, req.Field)
+	if errors.Is(err, storage.ErrNotFound) {
+		return &pb.HashGetResponse{Found: false}, nil
+	}
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to get hash field")
+	}
+	return &pb.HashGetResponse{Found: true, Value: toTypedValue(value)}, nil
+}
+
+func (s *AmpKVGrpcServer) HashDelete(ctx context.Context, req *pb.HashDeleteRequest) (*pb.CountResponse, error) {
+	if err := checkCollectionRequest("HashDeleteRequest", req); err != nil {
+		return nil, err
+	}
+
+	removed, err := s.store.HDel(req.Key, req.Fields...)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to delete hash fields")
+	}
+	return &pb.CountResponse{Count: int64(removed)}, nil
+}
+
+func (s *AmpKVGrpcServer) HashGetAll(ctx context.Context, req *pb.HashGetAllRequest) (*pb.HashGetAllResponse, error) {
+	if err := checkCollectionRequest("HashGetAllRequest", req); err != nil {
+		return nil, err
+	}
+
+	fields, err := s.store.HGetAll(req.Key)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to read hash")
+	}
+
+	resp := &pb.HashGetAllResponse{Fields: make(map[string]*pb.TypedValue, len(fields))}
+	for field, value := range fields {
+		resp.Fields[field] = toTypedValue(value)
+	}
+	return resp, nil
+}
+
+func (s *AmpKVGrpcServer) SortedSetAdd(ctx context.Context, req *pb.SortedSetAddRequest) (*pb.CountResponse, error) {
+	if err := checkCollectionRequest("SortedSetAddRequest", req); err != nil {
+		return nil, err
+	}
+
+	members := make([]embedded.ZMember, 0, len(req.Members))
+	for _, member := range req.Members {
+		members = append(members, embedded.ZMember{Member: member.GetMember(), Score: member.GetScore()})
+	}
+
+	added, err := s.store.ZAdd(req.Key, members...)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to add to sorted set")
+	}
+	return &pb.CountResponse{Count: int64(added)}, nil
+}
+
+func (s *AmpKVGrpcServer) SortedSetRemove(ctx context.Context, req *pb.SortedSetRemoveRequest) (*pb.CountResponse, error) {
+	if err := checkCollectionRequest("SortedSetRemoveRequest", req); err != nil {
+		return nil, err
+	}
+
+	removed, err := s.store.ZRem(req.Key, req.Members...)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to remove from sorted set")
+	}
+	return &pb.CountResponse{Count: int64(removed)}, nil
+}
+
+func (s *AmpKVGrpcServer) SortedSetRangeByScore(ctx context.Context, req *pb.SortedSetRangeRequest) (*pb.SortedSetRangeResponse, error) {
+	if err := checkCollectionRequest("SortedSetRangeRequest", req); err != nil {
+		return nil, err
+	}
+	if req.Limit < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "SortedSetRangeRequest: limit must not be negative")
+	}
+
+	members, err := s.store.ZRangeByScore(req.Key, req.Min, req.Max, int(req.Limit))
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to read sorted set")
+	}
+
+	resp := &pb.SortedSetRangeResponse{Members: make([]*pb.ScoredMember, 0, len(members))}
+	for _, member := range members {
+		resp.Members = append(resp.Members, &pb.ScoredMember{Member: member.Member, Score: member.Score})
+	}
+	return resp, nil
+}
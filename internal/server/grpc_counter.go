@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *AmpKVGrpcServer) Increment(ctx context.Context, req *pb.IncrementRequest) (*pb.IncrementResponse, error) {
+	if req.Key == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "IncrementRequest: key must not be empty")
+	}
+	if err := rejectReservedKeys(req); err != nil {
+		return nil, err
+	}
+
+	switch delta := req.Delta.(type) {
+	case *pb.IncrementRequest_IntDelta:
+		value, err := s.store.IncrBy(req.Key, delta.IntDelta)
+		if err != nil {
+			return nil, storageErrorToStatus(err, "failed to increment key in store")
+		}
+		return &pb.IncrementResponse{Value: &pb.IncrementResponse_IntValue{IntValue: value}}, nil
+	case *pb.IncrementRequest_FloatDelta:
+		value, err := s.store.IncrByFloat(req.Key, delta.FloatDelta)
+		if err != nil {
+			return nil, storageErrorToStatus(err, "failed to increment key in store")
+		}
+		return &pb.IncrementResponse{Value: &pb.IncrementResponse_FloatValue{FloatValue: value}}, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "IncrementRequest: int_delta or float_delta must be provided")
+	}
+}
@@ -2,10 +2,15 @@ package server
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/Unfield/AmpKV/internal/storage"
 	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"github.com/Unfield/AmpKV/pkg/common"
 	"github.com/Unfield/AmpKV/pkg/embedded"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -25,37 +30,44 @@ func (s *AmpKVGrpcServer) Get(ctx context.Context, req *pb.GetRequest) (*pb.GetR
 	if req.Key == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "GetRequest: key must not be empty")
 	}
+	if err := rejectReservedKeys(req); err != nil {
+		return nil, err
+	}
 
-	val, found := s.store.Get(req.Key)
-	if !found {
+	val, err := s.store.Get(req.Key)
+	if errors.Is(err, storage.ErrNotFound) {
 		return &pb.GetResponse{
 			Found: false,
 			Kv:    nil,
 		}, nil
 	}
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to get key from store")
+	}
 
-	return &pb.GetResponse{
-		Found: true,
-		Kv: &pb.KeyValue{
-			Type:  pb.AmpKVDataTypeProto(val.Type),
-			Key:   req.Key,
-			Value: val.Data,
-		},
-	}, nil
+	return toGetResponse(req.Key, val), nil
 }
 
 func (s *AmpKVGrpcServer) Set(ctx context.Context, req *pb.SetRequest) (*pb.OperationResponse, error) {
-	if req.Kv == nil || req.Kv.Key == "" || req.Kv.Value == nil {
+	if !isValidKeyValue(req.Kv) {
 		return nil, status.Errorf(codes.InvalidArgument, "SetRequest: key, value and kv fields must be provided")
 	}
+	if err := rejectReservedKeys(req); err != nil {
+		return nil, err
+	}
 
 	if req.Kv.Cost <= 0 {
 		req.Kv.Cost = 1
 	}
 
-	err := s.store.Set(req.Kv.Key, req.Kv.Value, req.Kv.Cost)
+	value, err := valueFromKeyValue("SetRequest", req.Kv)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to set key in store: %v", err)
+		return nil, err
+	}
+
+	err = s.store.Set(req.Kv.Key, value, req.Kv.Cost)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to set key in store")
 	}
 
 	return &pb.OperationResponse{
@@ -65,12 +77,15 @@ func (s *AmpKVGrpcServer) Set(ctx context.Context, req *pb.SetRequest) (*pb.Oper
 }
 
 func (s *AmpKVGrpcServer) SetWithTTL(ctx context.Context, req *pb.SetWithTTLRequest) (*pb.OperationResponse, error) {
-	if req.Kv == nil || req.Kv.Key == "" || req.Kv.Value == nil {
+	if !isValidKeyValue(req.Kv) {
 		return nil, status.Errorf(codes.InvalidArgument, "SetRequest: key, value and kv fields must be provided")
 	}
 	if req.TtlSeconds <= 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "SetWithTTLRequest: TTL in seconds must be positive")
 	}
+	if err := rejectReservedKeys(req); err != nil {
+		return nil, err
+	}
 
 	ttl := time.Duration(req.TtlSeconds) * time.Second
 
@@ -78,9 +93,14 @@ func (s *AmpKVGrpcServer) SetWithTTL(ctx context.Context, req *pb.SetWithTTLRequ
 		req.Kv.Cost = 1
 	}
 
-	err := s.store.SetWithTTL(req.Kv.Key, req.Kv.Value, req.Kv.Cost, ttl)
+	value, err := valueFromKeyValue("SetWithTTLRequest", req.Kv)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to set key in store: %v", err)
+		return nil, err
+	}
+
+	err = s.store.SetWithTTL(req.Kv.Key, value, req.Kv.Cost, ttl)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to set key in store")
 	}
 
 	return &pb.OperationResponse{
@@ -93,11 +113,195 @@ func (s *AmpKVGrpcServer) Delete(ctx context.Context, req *pb.DeleteRequest) (*p
 	if req.Key == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "DeleteRequest: key must be provided")
 	}
+	if err := rejectReservedKeys(req); err != nil {
+		return nil, err
+	}
 
-	s.store.Delete(req.Key)
+	err := s.store.Delete(req.Key)
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to delete key from store")
+	}
 
 	return &pb.OperationResponse{
 		Success: true,
 		Message: "Key deleted successfully",
 	}, nil
 }
+
+func (s *AmpKVGrpcServer) CompareAndSwap(ctx context.Context, req *pb.CompareAndSwapRequest) (*pb.CompareAndSwapResponse, error) {
+	if !isValidKeyValue(req.Kv) {
+		return nil, status.Errorf(codes.InvalidArgument, "CompareAndSwapRequest: key, value and kv fields must be provided")
+	}
+	if req.TtlSeconds < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "CompareAndSwapRequest: TTL in seconds must not be negative")
+	}
+	if err := rejectReservedKeys(req); err != nil {
+		return nil, err
+	}
+
+	if req.Kv.Cost <= 0 {
+		req.Kv.Cost = 1
+	}
+
+	value, err := valueFromKeyValue("CompareAndSwapRequest", req.Kv)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(req.TtlSeconds) * time.Second
+	version, err := s.store.CompareAndSwapWithTTL(req.Kv.Key, req.ExpectedVersion, value, req.Kv.Cost, ttl)
+	if errors.Is(err, embedded.ErrVersionMismatch) {
+		return &pb.CompareAndSwapResponse{
+			Succeeded: false,
+			Version:   version,
+		}, nil
+	}
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to compare and swap key in store")
+	}
+
+	return &pb.CompareAndSwapResponse{
+		Succeeded: true,
+		Version:   version,
+	}, nil
+}
+
+func (s *AmpKVGrpcServer) Scan(req *pb.ScanRequest, stream grpc.ServerStreamingServer[pb.ScanResponse]) error {
+	if req.Limit < 0 {
+		return status.Errorf(codes.InvalidArgument, "ScanRequest: limit must not be negative")
+	}
+
+	if err := rejectReservedKeys(req); err != nil {
+		return err
+	}
+
+	remaining := int(req.Limit)
+	cursor := visibleScanCursor(req.Start)
+	for {
+		pageLimit := embedded.MaxScanLimit
+		if req.Limit > 0 && remaining < pageLimit {
+			pageLimit = remaining
+		}
+
+		page, err := s.store.Scan(req.Prefix, cursor, pageLimit)
+		if err != nil {
+			return storageErrorToStatus(err, "failed to scan store")
+		}
+
+		items := visibleItems(page.Items)
+		nextCursor := visibleScanCursor(page.NextCursor)
+		for i, item := range items {
+			resp := &pb.ScanResponse{Kv: toKeyValue(item.Key, item.Value)}
+			if req.Limit > 0 && i == len(items)-1 && len(items) == remaining {
+				resp.NextCursor = nextCursor
+			}
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+
+		if req.Limit > 0 {
+			remaining -= len(items)
+		}
+		if nextCursor == "" || (req.Limit > 0 && remaining == 0) {
+			return nil
+		}
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		cursor = nextCursor
+	}
+}
+
+// isValidKeyValue requires a key and, for untyped values, a payload. Typed
+// values may be empty, e.g. an empty string.
+func isValidKeyValue(kv *pb.KeyValue) bool {
+	if kv == nil || kv.Key == "" {
+		return false
+	}
+	return kv.Value != nil || kv.Type != pb.AmpKVDataTypeProto_AMP_KV_DATA_TYPE_UNKNOWN
+}
+
+// valueFromKeyValue keeps the type sent by the client. Untyped values are
+// stored as binary, as they always have been.
+func valueFromKeyValue(name string, kv *pb.KeyValue) (any, error) {
+	if err := checkValueType(name, kv.Type); err != nil {
+		return nil, err
+	}
+	if kv.Type == pb.AmpKVDataTypeProto_AMP_KV_DATA_TYPE_UNKNOWN {
+		return kv.Value, nil
+	}
+	return &common.AmpKVValue{Type: common.AmpKVDataType(kv.Type), Data: kv.Value}, nil
+}
+
+// checkValueType rejects types the server does not know, which clients can
+// send because protobuf enums are open.
+func checkValueType(name string, t pb.AmpKVDataTypeProto) error {
+	if !common.AmpKVDataType(t).IsValid() {
+		return status.Errorf(codes.InvalidArgument, "%s: unknown value type %d", name, t)
+	}
+	return nil
+}
+
+func toKeyValue(key string, val *common.AmpKVValue) *pb.KeyValue {
+	return &pb.KeyValue{
+		Type:    pb.AmpKVDataTypeProto(val.Type),
+		Key:     key,
+		Value:   val.Data,
+		Version: val.Version,
+	}
+}
+
+func toGetResponse(key string, val *common.AmpKVValue) *pb.GetResponse {
+	resp := &pb.GetResponse{
+		Found:     true,
+		Kv:        toKeyValue(key, val),
+		CreatedAt: unixMilli(val.CreatedAt),
+		UpdatedAt: unixMilli(val.UpdatedAt),
+		Size:      int64(val.Size()),
+	}
+	if val.ExpiresAt != nil {
+		resp.ExpiresAt = val.ExpiresAt.UnixMilli()
+	}
+	return resp
+}
+
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func storageErrorToStatus(err error, msg string) error {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, storage.ErrClosed):
+		return status.Errorf(codes.Unavailable, "%s: %v", msg, err)
+	case errors.Is(err, storage.ErrUnsupported):
+		return failedPrecondition(pb.ErrorReasonUnsupported, msg, err)
+	case errors.Is(err, storage.ErrConflict):
+		return status.Errorf(codes.Aborted, "%s: %v", msg, err)
+	case errors.Is(err, embedded.ErrVersionMismatch), errors.Is(err, embedded.ErrKeyExists):
+		return failedPrecondition(pb.ErrorReasonVersionMismatch, msg, err)
+	case errors.Is(err, embedded.ErrTypeMismatch):
+		return failedPrecondition(pb.ErrorReasonTypeMismatch, msg, err)
+	case errors.Is(err, embedded.ErrOverflow):
+		return status.Errorf(codes.OutOfRange, "%s: %v", msg, err)
+	case errors.Is(err, embedded.ErrCollectionValue), errors.Is(err, embedded.ErrInvalidScore):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
+// failedPrecondition tags a FailedPrecondition status with reason so clients
+// can map it back to the embedded error.
+func failedPrecondition(reason, msg string, err error) error {
+	st := status.Newf(codes.FailedPrecondition, "%s: %v", msg, err)
+	if detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: pb.ErrorDomain}); detailErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrpcValueTypes(t *testing.T) {
+	ampkv, _, _ := newTestManager(t)
+	server := NewAmpKVGrpcServer(ampkv)
+	ctx := context.Background()
+
+	unknown := pb.AmpKVDataTypeProto(42)
+	kv := &pb.KeyValue{Key: "typed", Value: []byte("value"), Type: unknown}
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"Set", func() error { _, err := server.Set(ctx, &pb.SetRequest{Kv: kv}); return err }},
+		{"SetWithTTL", func() error {
+			_, err := server.SetWithTTL(ctx, &pb.SetWithTTLRequest{Kv: kv, TtlSeconds: 60})
+			return err
+		}},
+		{"CompareAndSwap", func() error {
+			_, err := server.CompareAndSwap(ctx, &pb.CompareAndSwapRequest{Kv: kv})
+			return err
+		}},
+		{"MultiSet", func() error {
+			_, err := server.MultiSet(ctx, &pb.MultiSetRequest{Items: []*pb.PutOp{{Kv: kv}}})
+			return err
+		}},
+		{"Txn", func() error {
+			_, err := server.Txn(ctx, &pb.TxnRequest{Success: []*pb.TxnOp{{Op: &pb.TxnOp_Put{Put: &pb.PutOp{Kv: kv}}}}})
+			return err
+		}},
+		{"ListPush", func() error {
+			_, err := server.ListPush(ctx, &pb.ListPushRequest{Key: "typed-list", Values: []*pb.TypedValue{{Value: []byte("value"), Type: unknown}}})
+			return err
+		}},
+		{"HashSet", func() error {
+			_, err := server.HashSet(ctx, &pb.HashSetRequest{Key: "typed-hash", Field: "field", Value: &pb.TypedValue{Value: []byte("value"), Type: -1}})
+			return err
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.call(); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument for an unknown type, got %v", err)
+			}
+		})
+	}
+
+	if _, err := ampkv.Get("typed"); err == nil {
+		t.Error("Expected no value to be stored")
+	}
+	kv.Type = pb.AmpKVDataTypeProto_AMP_KV_DATA_TYPE_STRING
+	if _, err := server.Set(ctx, &pb.SetRequest{Kv: kv}); err != nil {
+		t.Errorf("Expected a string to be accepted, got %v", err)
+	}
+}
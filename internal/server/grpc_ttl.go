@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *AmpKVGrpcServer) TTL(ctx context.Context, req *pb.TTLRequest) (*pb.TTLResponse, error) {
+	if req.Key == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "TTLRequest: key must not be empty")
+	}
+	if err := rejectReservedKeys(req); err != nil {
+		return nil, err
+	}
+
+	ttl, err := s.store.TTL(req.Key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return &pb.TTLResponse{Found: false}, nil
+	}
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to get TTL of key from store")
+	}
+
+	resp := &pb.TTLResponse{Found: true}
+	if ttl > 0 {
+		resp.TtlMs = ttl.Milliseconds()
+		resp.ExpiresAt = time.Now().Add(ttl).UnixMilli()
+	}
+	return resp, nil
+}
+
+func (s *AmpKVGrpcServer) Expire(ctx context.Context, req *pb.ExpireRequest) (*pb.OperationResponse, error) {
+	if req.Key == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "ExpireRequest: key must not be empty")
+	}
+	if err := rejectReservedKeys(req); err != nil {
+		return nil, err
+	}
+
+	var err error
+	switch expiry := req.Expiry.(type) {
+	case *pb.ExpireRequest_TtlSeconds:
+		err = s.store.Expire(req.Key, time.Duration(expiry.TtlSeconds)*time.Second)
+	case *pb.ExpireRequest_ExpiresAt:
+		err = s.store.ExpireAt(req.Key, time.UnixMilli(expiry.ExpiresAt))
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "ExpireRequest: ttl_seconds or expires_at must be provided")
+	}
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to set TTL of key in store")
+	}
+
+	return &pb.OperationResponse{
+		Success: true,
+		Message: "TTL set successfully",
+	}, nil
+}
+
+func (s *AmpKVGrpcServer) Persist(ctx context.Context, req *pb.PersistRequest) (*pb.OperationResponse, error) {
+	if req.Key == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "PersistRequest: key must not be empty")
+	}
+	if err := rejectReservedKeys(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Persist(req.Key); err != nil {
+		return nil, storageErrorToStatus(err, "failed to persist key in store")
+	}
+
+	return &pb.OperationResponse{
+		Success: true,
+		Message: "TTL removed successfully",
+	}, nil
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *AmpKVGrpcServer) Txn(ctx context.Context, req *pb.TxnRequest) (*pb.TxnResponse, error) {
+	if err := validateTxnRequest(req); err != nil {
+		return nil, err
+	}
+
+	var succeeded bool
+	err := s.store.Update(func(tx *embedded.Txn) error {
+		ok, err := evaluateCompares(tx, req.Compare)
+		if err != nil {
+			return err
+		}
+		succeeded = ok
+
+		ops := req.Failure
+		if ok {
+			ops = req.Success
+		}
+		return applyTxnOps(tx, ops)
+	})
+	if err != nil {
+		return nil, storageErrorToStatus(err, "failed to execute transaction")
+	}
+
+	return &pb.TxnResponse{
+		Succeeded: succeeded,
+	}, nil
+}
+
+func validateTxnRequest(req *pb.TxnRequest) error {
+	for _, compare := range req.Compare {
+		if compare.Key == "" {
+			return status.Errorf(codes.InvalidArgument, "TxnRequest: compare key must not be empty")
+		}
+	}
+	for _, ops := range [][]*pb.TxnOp{req.Success, req.Failure} {
+		for _, op := range ops {
+			switch o := op.GetOp().(type) {
+			case *pb.TxnOp_Put:
+				if !isValidKeyValue(o.Put.Kv) {
+					return status.Errorf(codes.InvalidArgument, "TxnRequest: put key and value must be provided")
+				}
+				if o.Put.TtlSeconds < 0 {
+					return status.Errorf(codes.InvalidArgument, "TxnRequest: put TTL in seconds must not be negative")
+				}
+				if err := checkValueType("TxnRequest", o.Put.Kv.Type); err != nil {
+					return err
+				}
+			case *pb.TxnOp_Delete:
+				if o.Delete.Key == "" {
+					return status.Errorf(codes.InvalidArgument, "TxnRequest: delete key must not be empty")
+				}
+			default:
+				return status.Errorf(codes.InvalidArgument, "TxnRequest: operation must be put or delete")
+			}
+		}
+	}
+	return rejectReservedKeys(req)
+}
+
+func evaluateCompares(tx *embedded.Txn, compares []*pb.Compare) (bool, error) {
+	for _, compare := range compares {
+		val, err := tx.Get(compare.Key)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return false, err
+		}
+		exists := err == nil
+
+		var outcome int
+		switch compare.Target {
+		case pb.CompareTarget_COMPARE_TARGET_EXISTS:
+			if !exists {
+				outcome = 1
+			}
+		case pb.CompareTarget_COMPARE_TARGET_VALUE:
+			if !exists {
+				return false, nil
+			}
+			outcome = bytes.Compare(val.Data, compare.Value)
+		case pb.CompareTarget_COMPARE_TARGET_VERSION:
+			var version uint64
+			if exists {
+				version = val.Version
+			}
+			outcome = cmp.Compare(version, compare.Version)
+		default:
+			return false, status.Errorf(codes.InvalidArgument, "TxnRequest: unknown compare target %v", compare.Target)
+		}
+
+		if !compareResultHolds(compare.Result, outcome) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func compareResultHolds(result pb.CompareResult, outcome int) bool {
+	switch result {
+	case pb.CompareResult_COMPARE_RESULT_EQUAL:
+		return outcome == 0
+	case pb.CompareResult_COMPARE_RESULT_NOT_EQUAL:
+		return outcome != 0
+	case pb.CompareResult_COMPARE_RESULT_GREATER:
+		return outcome > 0
+	case pb.CompareResult_COMPARE_RESULT_LESS:
+		return outcome < 0
+	default:
+		return false
+	}
+}
+
+func applyTxnOps(tx *embedded.Txn, ops []*pb.TxnOp) error {
+	for _, op := range ops {
+		switch o := op.GetOp().(type) {
+		case *pb.TxnOp_Put:
+			cost := o.Put.Kv.Cost
+			if cost <= 0 {
+				cost = 1
+			}
+			value, err := valueFromKeyValue("TxnRequest", o.Put.Kv)
+			if err != nil {
+				return err
+			}
+			ttl := time.Duration(o.Put.TtlSeconds) * time.Second
+			if err := tx.SetWithTTL(o.Put.Kv.Key, value, cost, ttl); err != nil {
+				return err
+			}
+		case *pb.TxnOp_Delete:
+			if err := tx.Delete(o.Delete.Key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
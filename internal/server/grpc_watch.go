@@ -0,0 +1,76 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/Unfield/AmpKV/internal/auth"
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (s *AmpKVGrpcServer) Watch(req *pb.WatchRequest, stream grpc.ServerStreamingServer[pb.WatchResponse]) error {
+	if req.Key == "" && !req.Prefix {
+		return status.Errorf(codes.InvalidArgument, "WatchRequest: key must not be empty unless watching a prefix")
+	}
+	if err := rejectReservedKeys(req); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	events, err := s.store.Watch(ctx, req.Key, embedded.WatchOptions{
+		Prefix:        req.Prefix,
+		StartRevision: req.StartRevision,
+	})
+	if errors.Is(err, embedded.ErrCompacted) {
+		return status.Errorf(codes.OutOfRange, "failed to start watch: %v", err)
+	}
+	if err != nil {
+		return storageErrorToStatus(err, "failed to start watch")
+	}
+	// Headers signal that the watch is registered; clients waiting for them
+	// will not miss any change made afterwards.
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	nextRevision := req.StartRevision
+	for event := range events {
+		if auth.IsReservedKey(event.Key) {
+			continue
+		}
+		if err := stream.Send(toWatchResponse(event)); err != nil {
+			return err
+		}
+		nextRevision = event.Revision + 1
+	}
+
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	if nextRevision == 0 {
+		return status.Errorf(codes.Aborted, "watch cancelled by server")
+	}
+	return status.Errorf(codes.Aborted, "watch cancelled by server, resume from revision %d", nextRevision)
+}
+
+func toWatchResponse(event embedded.WatchEvent) *pb.WatchResponse {
+	resp := &pb.WatchResponse{
+		Kv:       &pb.KeyValue{Key: event.Key},
+		Revision: event.Revision,
+	}
+
+	switch event.Type {
+	case embedded.EventPut:
+		resp.Type = pb.WatchEventType_WATCH_EVENT_TYPE_PUT
+		resp.Kv = toKeyValue(event.Key, event.Value)
+	case embedded.EventDelete:
+		resp.Type = pb.WatchEventType_WATCH_EVENT_TYPE_DELETE
+	case embedded.EventExpire:
+		resp.Type = pb.WatchEventType_WATCH_EVENT_TYPE_EXPIRE
+	}
+	return resp
+}
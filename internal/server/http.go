@@ -1,10 +1,16 @@
 package server
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/Unfield/AmpKV/internal/audit"
 	"github.com/Unfield/AmpKV/internal/auth"
+	"github.com/Unfield/AmpKV/internal/storage"
 	"github.com/Unfield/AmpKV/pkg/common"
 	"github.com/Unfield/AmpKV/pkg/embedded"
 	"github.com/labstack/echo/v4"
@@ -19,30 +25,86 @@ type AmpKVHttpServer struct {
 	store *embedded.AmpKV
 }
 
-func NewAmpKVHttpServer(store *embedded.AmpKV, manager *auth.ApiKeyManager) *AmpKVHttpServer {
+// NewAmpKVHttpServer creates the Http API. A nil manager disables
+// authentication and the admin routes, a nil auditLog the audit log.
+func NewAmpKVHttpServer(store *embedded.AmpKV, manager *auth.ApiKeyManager, auditLog *audit.Log) *AmpKVHttpServer {
 	server := &AmpKVHttpServer{
 		e:     echo.New(),
 		store: store,
 	}
 
+	// The client address is logged and audited, so it must not come from
+	// headers the client controls.
+	server.e.IPExtractor = echo.ExtractIPDirect()
+
 	server.e.Use(middleware.Recover())
 	server.e.Use(middleware.Logger())
-	server.e.Use(HttpAuthMiddleware(manager))
+	if manager != nil {
+		server.e.Use(HttpAuthMiddleware(manager, auditLog))
+	}
 
+	// _watch, _batch and the admin routes take precedence over data keys
+	// with the same names, which remain reachable over gRPC.
+	server.e.GET("/api/v1/", server.handleScan())
+	server.e.GET("/api/v1/_watch", server.handleWatch())
 	server.e.GET("/api/v1/:key", server.handleGet())
+	server.e.GET("/api/v1/:key/ttl", server.handleGetTTL())
+	server.e.PATCH("/api/v1/:key/ttl", server.handleSetTTL())
+	server.e.POST("/api/v1/:key/incr", server.handleIncrement())
+	server.e.GET("/api/v1/:key/list", server.handleListRange())
+	server.e.POST("/api/v1/:key/list", server.handleListPush())
+	server.e.POST("/api/v1/:key/list/pop", server.handleListPop())
+	server.e.GET("/api/v1/:key/set", server.handleSetMembers())
+	server.e.POST("/api/v1/:key/set", server.handleSetAdd())
+	server.e.DELETE("/api/v1/:key/set/:member", server.handleSetRemove())
+	server.e.GET("/api/v1/:key/hash", server.handleHashGetAll())
+	server.e.GET("/api/v1/:key/hash/:field", server.handleHashGet())
+	server.e.PUT("/api/v1/:key/hash/:field", server.handleHashSet())
+	server.e.DELETE("/api/v1/:key/hash/:field", server.handleHashDelete())
+	server.e.GET("/api/v1/:key/zset", server.handleSortedSetRange())
+	server.e.POST("/api/v1/:key/zset", server.handleSortedSetAdd())
+	server.e.DELETE("/api/v1/:key/zset/:member", server.handleSortedSetRemove())
 	server.e.POST("/api/v1/", server.handleSet())
+	server.e.POST(batchRoute, server.handleBatch())
 	server.e.DELETE("/api/v1/:key", server.handleDelete())
 
+	if manager != nil {
+		server.registerAdminRoutes(manager, auditLog)
+	}
+
 	return server
 }
 
-func (s *AmpKVHttpServer) ListenAutoTLS(address string, port uint16) {
-	s.e.AutoTLSManager.Cache = autocert.DirCache("/var/www/.cache")
-	go s.e.Logger.Fatal(s.e.StartAutoTLS(fmt.Sprintf("%s:%d", address, port)))
+func (s *AmpKVHttpServer) ListenAutoTLS(address string, port uint16, cacheDir string) {
+	s.e.AutoTLSManager.Cache = autocert.DirCache(cacheDir)
+	go func() {
+		if err := s.e.StartAutoTLS(fmt.Sprintf("%s:%d", address, port)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.e.Logger.Fatal(err)
+		}
+	}()
+}
+
+// ListenTLS serves Https using tlsConfig, e.g. from CertReloader.ServerConfig.
+func (s *AmpKVHttpServer) ListenTLS(address string, port uint16, tlsConfig *tls.Config) {
+	s.e.TLSServer.Addr = fmt.Sprintf("%s:%d", address, port)
+	s.e.TLSServer.TLSConfig = tlsConfig
+	go func() {
+		if err := s.e.StartServer(s.e.TLSServer); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.e.Logger.Fatal(err)
+		}
+	}()
 }
 
 func (s *AmpKVHttpServer) Listen(address string, port uint16) {
-	go s.e.Logger.Fatal(s.e.Start(fmt.Sprintf("%s:%d", address, port)))
+	go func() {
+		if err := s.e.Start(fmt.Sprintf("%s:%d", address, port)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.e.Logger.Fatal(err)
+		}
+	}()
+}
+
+func (s *AmpKVHttpServer) Shutdown(ctx context.Context) error {
+	return s.e.Shutdown(ctx)
 }
 
 func (s *AmpKVHttpServer) Use(mw echo.MiddlewareFunc) {
@@ -50,9 +112,14 @@ func (s *AmpKVHttpServer) Use(mw echo.MiddlewareFunc) {
 }
 
 type getSuccessResponse struct {
-	Error bool                 `json:"error"`
-	Type  common.AmpKVDataType `json:"type"`
-	Value []byte               `json:"value"`
+	Error     bool                 `json:"error"`
+	Type      common.AmpKVDataType `json:"type"`
+	Value     []byte               `json:"value"`
+	Version   uint64               `json:"version"`
+	CreatedAt *time.Time           `json:"created_at,omitempty"`
+	UpdatedAt *time.Time           `json:"updated_at,omitempty"`
+	ExpiresAt *time.Time           `json:"expires_at,omitempty"`
+	Size      int                  `json:"size"`
 }
 
 func (s *AmpKVHttpServer) handleGet() echo.HandlerFunc {
@@ -62,13 +129,84 @@ func (s *AmpKVHttpServer) handleGet() echo.HandlerFunc {
 		if len(key) < 1 {
 			return echo.NewHTTPError(http.StatusBadRequest, "key is required")
 		}
+		if err := authorizeKeys(ctx, auth.PermRead, key); err != nil {
+			return err
+		}
+
+		val, err := s.store.Get(key)
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to read data")
+		}
 
-		val, found := s.store.Get(key)
-		if !found || val == nil {
-			return echo.NewHTTPError(http.StatusNotFound, "key not found")
+		header := ctx.Response().Header()
+		header.Set("ETag", formatETag(val.Version))
+		if !val.UpdatedAt.IsZero() {
+			header.Set("Last-Modified", val.UpdatedAt.UTC().Format(http.TimeFormat))
 		}
+		if val.ExpiresAt != nil {
+			header.Set("Expires", val.ExpiresAt.UTC().Format(http.TimeFormat))
+		}
+		return ctx.JSON(http.StatusOK, getSuccessResponse{
+			Error:     false,
+			Type:      val.Type,
+			Value:     val.Data,
+			Version:   val.Version,
+			CreatedAt: optionalTime(val.CreatedAt),
+			UpdatedAt: optionalTime(val.UpdatedAt),
+			ExpiresAt: val.ExpiresAt,
+			Size:      val.Size(),
+		})
+	}
+}
 
-		return ctx.JSON(http.StatusOK, getSuccessResponse{Error: false, Type: val.Type, Value: val.Data})
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+type scanItem struct {
+	Key     string               `json:"key"`
+	Type    common.AmpKVDataType `json:"type"`
+	Value   []byte               `json:"value"`
+	Version uint64               `json:"version"`
+}
+
+type scanSuccessResponse struct {
+	Error      bool       `json:"error"`
+	Items      []scanItem `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+func (s *AmpKVHttpServer) handleScan() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		limit := 0
+		if rawLimit := ctx.QueryParam("limit"); rawLimit != "" {
+			parsedLimit, err := strconv.Atoi(rawLimit)
+			if err != nil || parsedLimit < 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "limit must be a non-negative integer")
+			}
+			limit = parsedLimit
+		}
+
+		prefix := ctx.QueryParam("prefix")
+		if err := authorizeKeys(ctx, auth.PermRead, prefix); err != nil {
+			return err
+		}
+
+		page, err := s.store.Scan(prefix, visibleScanCursor(ctx.QueryParam("cursor")), limit)
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to scan data")
+		}
+
+		visible := visibleItems(page.Items)
+		items := make([]scanItem, 0, len(visible))
+		for _, item := range visible {
+			items = append(items, scanItem{Key: item.Key, Type: item.Value.Type, Value: item.Value.Data, Version: item.Value.Version})
+		}
+
+		return ctx.JSON(http.StatusOK, scanSuccessResponse{Error: false, Items: items, NextCursor: visibleScanCursor(page.NextCursor)})
 	}
 }
 
@@ -79,7 +217,8 @@ type setRequest struct {
 }
 
 type setSuccessResponse struct {
-	Error bool `json:"error"`
+	Error   bool   `json:"error"`
+	Version uint64 `json:"version"`
 }
 
 func (s *AmpKVHttpServer) handleSet() echo.HandlerFunc {
@@ -93,20 +232,27 @@ func (s *AmpKVHttpServer) handleSet() echo.HandlerFunc {
 		if len(request.Key) < 1 {
 			return echo.NewHTTPError(http.StatusBadRequest, "key is required")
 		}
+		if err := authorizeKeys(ctx, auth.PermWrite, request.Key); err != nil {
+			return err
+		}
 
+		cond, err := preconditionFromHeaders(ctx.Request().Header)
+		if err != nil {
+			return err
+		}
+
+		var ttl time.Duration
 		if request.TTL != nil && *request.TTL > 0 {
-			err := s.store.SetWithTTL(request.Key, request.Value, 1, *request.TTL)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, "failed to save data")
-			}
-			return ctx.JSON(http.StatusCreated, setSuccessResponse{Error: false})
-		} else {
-			err := s.store.Set(request.Key, request.Value, 1)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, "failed to save data")
-			}
-			return ctx.JSON(http.StatusCreated, setSuccessResponse{Error: false})
+			ttl = *request.TTL
 		}
+
+		version, err := s.store.SetIf(request.Key, request.Value, 1, ttl, cond)
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to save data")
+		}
+
+		ctx.Response().Header().Set("ETag", formatETag(version))
+		return ctx.JSON(http.StatusCreated, setSuccessResponse{Error: false, Version: version})
 	}
 }
 
@@ -121,9 +267,47 @@ func (s *AmpKVHttpServer) handleDelete() echo.HandlerFunc {
 		if len(key) < 1 {
 			return echo.NewHTTPError(http.StatusBadRequest, "key is required")
 		}
+		if err := authorizeKeys(ctx, auth.PermDelete, key); err != nil {
+			return err
+		}
+
+		cond, err := preconditionFromHeaders(ctx.Request().Header)
+		if err != nil {
+			return err
+		}
 
-		s.store.Delete(key)
+		if cond != nil {
+			_, err = s.store.DeleteIf(key, cond)
+		} else {
+			err = s.store.Delete(key)
+		}
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to delete data")
+		}
 
 		return ctx.JSON(http.StatusOK, deleteSuccessResponse{Error: false})
 	}
 }
+
+func storageErrorToHTTPError(err error, msg string) *echo.HTTPError {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "key not found")
+	case errors.Is(err, storage.ErrClosed):
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "storage unavailable")
+	case errors.Is(err, storage.ErrUnsupported):
+		return echo.NewHTTPError(http.StatusNotImplemented, "operation not supported by storage")
+	case errors.Is(err, storage.ErrConflict):
+		return echo.NewHTTPError(http.StatusConflict, "conflicting concurrent write")
+	case errors.Is(err, embedded.ErrVersionMismatch), errors.Is(err, embedded.ErrKeyExists):
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "precondition failed")
+	case errors.Is(err, embedded.ErrTypeMismatch):
+		return echo.NewHTTPError(http.StatusConflict, "value has a different type")
+	case errors.Is(err, embedded.ErrOverflow):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "increment overflows the value")
+	case errors.Is(err, embedded.ErrCollectionValue), errors.Is(err, embedded.ErrInvalidScore):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, msg)
+	}
+}
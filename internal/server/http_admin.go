@@ -0,0 +1,318 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/audit"
+	"github.com/Unfield/AmpKV/internal/auth"
+	"github.com/labstack/echo/v4"
+)
+
+type apiKeyResponse struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Permissions []string      `json:"permissions"`
+	CreatedAt   time.Time     `json:"created_at"`
+	ExpiresAt   *time.Time    `json:"expires_at,omitempty"`
+	Disabled    bool          `json:"disabled"`
+	Legacy      bool          `json:"legacy,omitempty"`
+	Scopes      []apiKeyScope `json:"scopes,omitempty"`
+	Limits      *apiKeyLimits `json:"limits,omitempty"`
+}
+
+type apiKeyLimits struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+	DailyOps          int64   `json:"daily_ops"`
+	DailyBytes        int64   `json:"daily_bytes"`
+}
+
+type apiKeyScope struct {
+	Prefix      string   `json:"prefix"`
+	Permissions []string `json:"permissions"`
+}
+
+type apiKeySuccessResponse struct {
+	Error bool           `json:"error"`
+	Key   apiKeyResponse `json:"key"`
+	// Token is only set when a key is created or rotated.
+	Token string `json:"token,omitempty"`
+}
+
+type apiKeyListSuccessResponse struct {
+	Error      bool             `json:"error"`
+	Keys       []apiKeyResponse `json:"keys"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+type createApiKeyRequest struct {
+	Name        string         `json:"name"`
+	Permissions []string       `json:"permissions"`
+	Scopes      []apiKeyScope  `json:"scopes"`
+	Disabled    bool           `json:"disabled"`
+	TTL         *time.Duration `json:"ttl"`
+}
+
+type setApiKeyExpirationRequest struct {
+	// ExpiresAt of null removes the expiration.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type auditListSuccessResponse struct {
+	Error      bool          `json:"error"`
+	Entries    []audit.Entry `json:"entries"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+func (s *AmpKVHttpServer) registerAdminRoutes(manager *auth.ApiKeyManager, auditLog *audit.Log) {
+	// The routes take the middleware one by one: a Group with middleware
+	// would also catch /api/v1/admin itself, which is a data key.
+	requireAdmin := RequirePermission(auth.PermAdmin)
+
+	if auditLog != nil {
+		s.e.GET(adminRoutePrefix+"/audit", handleQueryAuditLog(auditLog), requireAdmin)
+	}
+
+	s.e.POST(adminRoutePrefix+"/keys", handleCreateApiKey(manager), requireAdmin)
+	s.e.GET(adminRoutePrefix+"/keys", handleListApiKeys(manager), requireAdmin)
+	s.e.GET(adminRoutePrefix+"/keys/:id", handleGetApiKey(manager), requireAdmin)
+	s.e.POST(adminRoutePrefix+"/keys/:id/disable", handleSetApiKeyDisabled(manager, true), requireAdmin)
+	s.e.POST(adminRoutePrefix+"/keys/:id/enable", handleSetApiKeyDisabled(manager, false), requireAdmin)
+	s.e.PUT(adminRoutePrefix+"/keys/:id/expiration", handleSetApiKeyExpiration(manager), requireAdmin)
+	s.e.PUT(adminRoutePrefix+"/keys/:id/limits", handleSetApiKeyLimits(manager), requireAdmin)
+	s.e.POST(adminRoutePrefix+"/keys/:id/rotate", handleRotateApiKey(manager), requireAdmin)
+	s.e.DELETE(adminRoutePrefix+"/keys/:id", handleDeleteApiKey(manager), requireAdmin)
+}
+
+func handleCreateApiKey(manager *auth.ApiKeyManager) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		var request createApiKeyRequest
+		if err := ctx.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "body malformed")
+		}
+
+		perms, err := parsePermissions(request.Permissions)
+		if err != nil {
+			return keyErrorToHTTPError(err, "invalid permissions")
+		}
+		scopes := make([]auth.Scope, 0, len(request.Scopes))
+		for _, scope := range request.Scopes {
+			scopePerms, err := parsePermissions(scope.Permissions)
+			if err != nil {
+				return keyErrorToHTTPError(err, "invalid permissions")
+			}
+			scopes = append(scopes, auth.Scope{Prefix: scope.Prefix, Permissions: scopePerms})
+		}
+		if request.TTL != nil && *request.TTL < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "ttl must not be negative")
+		}
+
+		apiKey, err := manager.CreateScopedAPIKey(request.Name, perms, scopes, request.Disabled, request.TTL)
+		if err != nil {
+			return keyErrorToHTTPError(err, "failed to create api key")
+		}
+
+		return ctx.JSON(http.StatusCreated, apiKeySuccessResponse{Error: false, Key: toApiKeyResponse(apiKey), Token: apiKey.Key})
+	}
+}
+
+func handleListApiKeys(manager *auth.ApiKeyManager) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		limit := 0
+		if rawLimit := ctx.QueryParam("limit"); rawLimit != "" {
+			parsedLimit, err := strconv.Atoi(rawLimit)
+			if err != nil || parsedLimit < 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "limit must be a non-negative integer")
+			}
+			limit = parsedLimit
+		}
+
+		apiKeys, nextCursor, err := manager.ListApiKeys(ctx.QueryParam("cursor"), limit)
+		if err != nil {
+			return keyErrorToHTTPError(err, "failed to list api keys")
+		}
+
+		keys := make([]apiKeyResponse, 0, len(apiKeys))
+		for _, apiKey := range apiKeys {
+			keys = append(keys, toApiKeyResponse(apiKey))
+		}
+		return ctx.JSON(http.StatusOK, apiKeyListSuccessResponse{Error: false, Keys: keys, NextCursor: nextCursor})
+	}
+}
+
+func handleGetApiKey(manager *auth.ApiKeyManager) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		return respondApiKey(ctx, manager, ctx.Param("id"))
+	}
+}
+
+func handleSetApiKeyDisabled(manager *auth.ApiKeyManager, disabled bool) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		id := ctx.Param("id")
+
+		var err error
+		if disabled {
+			err = manager.DisabledApiKey(id)
+		} else {
+			err = manager.EnableApiKey(id)
+		}
+		if err != nil {
+			return keyErrorToHTTPError(err, "failed to update api key")
+		}
+		return respondApiKey(ctx, manager, id)
+	}
+}
+
+func handleSetApiKeyExpiration(manager *auth.ApiKeyManager) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		id := ctx.Param("id")
+
+		var request setApiKeyExpirationRequest
+		if err := ctx.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "body malformed")
+		}
+
+		var err error
+		if request.ExpiresAt == nil {
+			err = manager.RemoveExpiration(id)
+		} else {
+			err = manager.SetExpiration(id, *request.ExpiresAt)
+		}
+		if err != nil {
+			return keyErrorToHTTPError(err, "failed to update api key")
+		}
+		return respondApiKey(ctx, manager, id)
+	}
+}
+
+func handleSetApiKeyLimits(manager *auth.ApiKeyManager) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		id := ctx.Param("id")
+
+		var request apiKeyLimits
+		if err := ctx.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "body malformed")
+		}
+
+		err := manager.SetLimits(id, auth.Limits{
+			RequestsPerSecond: request.RequestsPerSecond,
+			Burst:             request.Burst,
+			DailyOps:          request.DailyOps,
+			DailyBytes:        request.DailyBytes,
+		})
+		if err != nil {
+			return keyErrorToHTTPError(err, "failed to update api key")
+		}
+		return respondApiKey(ctx, manager, id)
+	}
+}
+
+func handleRotateApiKey(manager *auth.ApiKeyManager) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		apiKey, err := manager.RotateApiKey(ctx.Param("id"))
+		if err != nil {
+			return keyErrorToHTTPError(err, "failed to rotate api key")
+		}
+		return ctx.JSON(http.StatusOK, apiKeySuccessResponse{Error: false, Key: toApiKeyResponse(apiKey), Token: apiKey.Key})
+	}
+}
+
+func handleDeleteApiKey(manager *auth.ApiKeyManager) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if err := manager.DeleteKey(ctx.Param("id")); err != nil {
+			return keyErrorToHTTPError(err, "failed to delete api key")
+		}
+		return ctx.JSON(http.StatusOK, deleteSuccessResponse{Error: false})
+	}
+}
+
+// handleQueryAuditLog filters by key_id and the RFC 3339 times from and to.
+func handleQueryAuditLog(auditLog *audit.Log) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		query := audit.Query{
+			KeyID:  ctx.QueryParam("key_id"),
+			Cursor: ctx.QueryParam("cursor"),
+		}
+		if rawLimit := ctx.QueryParam("limit"); rawLimit != "" {
+			parsedLimit, err := strconv.Atoi(rawLimit)
+			if err != nil || parsedLimit < 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "limit must be a non-negative integer")
+			}
+			query.Limit = parsedLimit
+		}
+		for param, target := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+			if raw := ctx.QueryParam(param); raw != "" {
+				parsed, err := time.Parse(time.RFC3339, raw)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest, param+" must be an RFC 3339 time")
+				}
+				*target = parsed
+			}
+		}
+
+		entries, nextCursor, err := auditLog.Query(query)
+		if errors.Is(err, audit.ErrInvalidCursor) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to query audit log")
+		}
+		return ctx.JSON(http.StatusOK, auditListSuccessResponse{Error: false, Entries: entries, NextCursor: nextCursor})
+	}
+}
+
+func respondApiKey(ctx echo.Context, manager *auth.ApiKeyManager, id string) error {
+	apiKey, err := manager.GetApiKeyByID(id)
+	if err != nil {
+		return keyErrorToHTTPError(err, "failed to read api key")
+	}
+	return ctx.JSON(http.StatusOK, apiKeySuccessResponse{Error: false, Key: toApiKeyResponse(apiKey)})
+}
+
+func toApiKeyResponse(apiKey *auth.ApiKey) apiKeyResponse {
+	response := apiKeyResponse{
+		ID:          apiKey.ID,
+		Name:        apiKey.Name,
+		Permissions: permissionNames(apiKey.Permissions),
+		CreatedAt:   apiKey.CreatedAt,
+		ExpiresAt:   apiKey.ExpiresAt,
+		Disabled:    apiKey.Disabled,
+		Legacy:      apiKey.Legacy,
+	}
+	for _, scope := range apiKey.Scopes {
+		response.Scopes = append(response.Scopes, apiKeyScope{Prefix: scope.Prefix, Permissions: permissionNames(scope.Permissions)})
+	}
+	if apiKey.Limits != (auth.Limits{}) {
+		response.Limits = &apiKeyLimits{
+			RequestsPerSecond: apiKey.Limits.RequestsPerSecond,
+			Burst:             apiKey.Limits.Burst,
+			DailyOps:          apiKey.Limits.DailyOps,
+			DailyBytes:        apiKey.Limits.DailyBytes,
+		}
+	}
+	return response
+}
+
+func keyErrorToHTTPError(err error, msg string) *echo.HTTPError {
+	var keyErr *auth.KeyError
+	if !errors.As(err, &keyErr) || keyErr.Kind == auth.InternalError {
+		if cause := errors.Unwrap(err); cause != nil {
+			return storageErrorToHTTPError(cause, msg)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, msg)
+	}
+
+	switch keyErr.Kind {
+	case auth.KeyNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "api key not found")
+	case auth.KeyMalformed:
+		return echo.NewHTTPError(http.StatusBadRequest, keyErr.Error())
+	case auth.KeyExpired, auth.KeyDisabled:
+		return echo.NewHTTPError(http.StatusConflict, keyErr.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, msg)
+	}
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/auth"
+	"github.com/Unfield/AmpKV/pkg/common"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	batchRoute = "/api/v1/_batch"
+
+	batchOpGet    = "get"
+	batchOpSet    = "set"
+	batchOpDelete = "delete"
+)
+
+type batchOperation struct {
+	Op    string         `json:"op"`
+	Key   string         `json:"key"`
+	Value any            `json:"value"`
+	TTL   *time.Duration `json:"ttl"`
+}
+
+type batchRequest struct {
+	Operations []batchOperation `json:"operations"`
+}
+
+type batchItemResult struct {
+	Op      string                `json:"op"`
+	Key     string                `json:"key"`
+	Status  int                   `json:"status"`
+	Message string                `json:"message,omitempty"`
+	Type    *common.AmpKVDataType `json:"type,omitempty"`
+	Value   []byte                `json:"value,omitempty"`
+	Version uint64                `json:"version,omitempty"`
+}
+
+type batchSuccessResponse struct {
+	Error   bool              `json:"error"`
+	Results []batchItemResult `json:"results"`
+}
+
+// handleBatch executes the operations in request order. Consecutive
+// operations of the same kind are grouped into a single GetMany, SetMany or
+// DeleteMany call, and every operation reports its own status.
+func (s *AmpKVHttpServer) handleBatch() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		var request batchRequest
+		err := ctx.Bind(&request)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "body malformed")
+		}
+
+		if len(request.Operations) == 0 || len(request.Operations) > maxBatchSize {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("between 1 and %d operations are required", maxBatchSize))
+		}
+		for _, op := range request.Operations {
+			if len(op.Key) < 1 {
+				return echo.NewHTTPError(http.StatusBadRequest, "key is required")
+			}
+			if op.Op != batchOpGet && op.Op != batchOpSet && op.Op != batchOpDelete {
+				return echo.NewHTTPError(http.StatusBadRequest, "op must be one of get, set or delete")
+			}
+			if err := authorizeKeys(ctx, batchOpPermission(op.Op), op.Key); err != nil {
+				return err
+			}
+		}
+
+		results := make([]batchItemResult, len(request.Operations))
+		for start := 0; start < len(request.Operations); {
+			end := start + 1
+			for end < len(request.Operations) && request.Operations[end].Op == request.Operations[start].Op {
+				end++
+			}
+
+			ops := request.Operations[start:end]
+			switch ops[0].Op {
+			case batchOpGet:
+				s.runBatchGets(ops, results[start:end])
+			case batchOpSet:
+				s.runBatchSets(ops, results[start:end])
+			case batchOpDelete:
+				s.runBatchDeletes(ops, results[start:end])
+			}
+			start = end
+		}
+
+		return ctx.JSON(http.StatusOK, batchSuccessResponse{Error: false, Results: results})
+	}
+}
+
+func (s *AmpKVHttpServer) runBatchGets(ops []batchOperation, results []batchItemResult) {
+	keys := make([]string, 0, len(ops))
+	for _, op := range ops {
+		keys = append(keys, op.Key)
+	}
+
+	values, err := s.store.GetMany(keys)
+	for i, op := range ops {
+		results[i] = batchItemResult{Op: op.Op, Key: op.Key}
+		if err != nil {
+			setBatchItemError(&results[i], err, "failed to read data")
+			continue
+		}
+
+		val, found := values[op.Key]
+		if !found {
+			results[i].Status = http.StatusNotFound
+			results[i].Message = "key not found"
+			continue
+		}
+		results[i].Status = http.StatusOK
+		results[i].Type = &val.Type
+		results[i].Value = val.Data
+		results[i].Version = val.Version
+	}
+}
+
+func (s *AmpKVHttpServer) runBatchSets(ops []batchOperation, results []batchItemResult) {
+	items := make([]embedded.BatchItem, 0, len(ops))
+	for _, op := range ops {
+		item := embedded.BatchItem{Key: op.Key, Value: op.Value, Cost: 1}
+		if op.TTL != nil && *op.TTL > 0 {
+			item.TTL = *op.TTL
+		}
+		items = append(items, item)
+	}
+
+	versions, err := s.store.SetMany(items)
+	for i, op := range ops {
+		results[i] = batchItemResult{Op: op.Op, Key: op.Key}
+		if err != nil {
+			setBatchItemError(&results[i], err, "failed to save data")
+			continue
+		}
+		results[i].Status = http.StatusCreated
+		results[i].Version = versions[i]
+	}
+}
+
+func (s *AmpKVHttpServer) runBatchDeletes(ops []batchOperation, results []batchItemResult) {
+	keys := make([]string, 0, len(ops))
+	for _, op := range ops {
+		keys = append(keys, op.Key)
+	}
+
+	err := s.store.DeleteMany(keys)
+	for i, op := range ops {
+		results[i] = batchItemResult{Op: op.Op, Key: op.Key, Status: http.StatusOK}
+		if err != nil {
+			setBatchItemError(&results[i], err, "failed to delete data")
+		}
+	}
+}
+
+func batchOpPermission(op string) auth.Permission {
+	switch op {
+	case batchOpSet:
+		return auth.PermWrite
+	case batchOpDelete:
+		return auth.PermDelete
+	default:
+		return auth.PermRead
+	}
+}
+
+func setBatchItemError(result *batchItemResult, err error, msg string) {
+	httpErr := storageErrorToHTTPError(err, msg)
+	result.Status = httpErr.Code
+	result.Message = fmt.Sprint(httpErr.Message)
+}
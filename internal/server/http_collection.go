@@ -0,0 +1,397 @@
+package server
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Unfield/AmpKV/internal/auth"
+	"github.com/Unfield/AmpKV/pkg/common"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"github.com/labstack/echo/v4"
+)
+
+// typedItem is a collection element in the same format as getSuccessResponse.
+type typedItem struct {
+	Type  common.AmpKVDataType `json:"type"`
+	Value []byte               `json:"value"`
+}
+
+func toTypedItem(val *common.AmpKVValue) typedItem {
+	return typedItem{Type: val.Type, Value: val.Data}
+}
+
+type listPushRequest struct {
+	Values []any `json:"values"`
+	Left   bool  `json:"left"`
+}
+
+type setAddRequest struct {
+	Members []string `json:"members"`
+}
+
+type hashSetRequest struct {
+	Value any `json:"value"`
+}
+
+type scoredMember struct {
+	Member string  `json:"member"`
+	Score  float64 `json:"score"`
+}
+
+type sortedSetAddRequest struct {
+	Members []scoredMember `json:"members"`
+}
+
+type countSuccessResponse struct {
+	Error bool  `json:"error"`
+	Count int64 `json:"count"`
+}
+
+type itemSuccessResponse struct {
+	Error bool                 `json:"error"`
+	Type  common.AmpKVDataType `json:"type"`
+	Value []byte               `json:"value"`
+}
+
+type listRangeSuccessResponse struct {
+	Error  bool        `json:"error"`
+	Values []typedItem `json:"values"`
+}
+
+type setMembersSuccessResponse struct {
+	Error   bool     `json:"error"`
+	Members []string `json:"members"`
+}
+
+type hashSetSuccessResponse struct {
+	Error   bool `json:"error"`
+	Created bool `json:"created"`
+}
+
+type hashGetAllSuccessResponse struct {
+	Error  bool                 `json:"error"`
+	Fields map[string]typedItem `json:"fields"`
+}
+
+type sortedSetRangeSuccessResponse struct {
+	Error   bool           `json:"error"`
+	Members []scoredMember `json:"members"`
+}
+
+// collectionKey returns the key of a collection route after checking perm.
+func collectionKey(ctx echo.Context, perm auth.Permission) (string, error) {
+	key := ctx.Param("key")
+
+	if len(key) < 1 {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "key is required")
+	}
+	if err := authorizeKeys(ctx, perm, key); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func (s *AmpKVHttpServer) handleListPush() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		var request listPushRequest
+		if err := ctx.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "body malformed")
+		}
+		if len(request.Values) == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "values are required")
+		}
+		for _, value := range request.Values {
+			if value == nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "values must not be null")
+			}
+		}
+
+		key, err := collectionKey(ctx, auth.PermWrite)
+		if err != nil {
+			return err
+		}
+
+		var length int64
+		if request.Left {
+			length, err = s.store.LPush(key, request.Values...)
+		} else {
+			length, err = s.store.RPush(key, request.Values...)
+		}
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to push to list")
+		}
+
+		return ctx.JSON(http.StatusOK, countSuccessResponse{Error: false, Count: length})
+	}
+}
+
+func (s *AmpKVHttpServer) handleListPop() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key, err := collectionKey(ctx, auth.PermWrite)
+		if err != nil {
+			return err
+		}
+
+		var value *common.AmpKVValue
+		switch ctx.QueryParam("end") {
+		case "left":
+			value, err = s.store.LPop(key)
+		case "", "right":
+			value, err = s.store.RPop(key)
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, "end must be left or right")
+		}
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to pop from list")
+		}
+
+		return ctx.JSON(http.StatusOK, itemSuccessResponse{Error: false, Type: value.Type, Value: value.Data})
+	}
+}
+
+func (s *AmpKVHttpServer) handleListRange() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key, err := collectionKey(ctx, auth.PermRead)
+		if err != nil {
+			return err
+		}
+
+		start, stop := int64(0), int64(-1)
+		if raw := ctx.QueryParam("start"); raw != "" {
+			if start, err = strconv.ParseInt(raw, 10, 64); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "start must be an integer")
+			}
+		}
+		if raw := ctx.QueryParam("stop"); raw != "" {
+			if stop, err = strconv.ParseInt(raw, 10, 64); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "stop must be an integer")
+			}
+		}
+
+		values, err := s.store.LRange(key, start, stop)
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to read list")
+		}
+
+		items := make([]typedItem, 0, len(values))
+		for _, value := range values {
+			items = append(items, toTypedItem(value))
+		}
+		return ctx.JSON(http.StatusOK, listRangeSuccessResponse{Error: false, Values: items})
+	}
+}
+
+func (s *AmpKVHttpServer) handleSetAdd() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		var request setAddRequest
+		if err := ctx.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "body malformed")
+		}
+
+		key, err := collectionKey(ctx, auth.PermWrite)
+		if err != nil {
+			return err
+		}
+
+		added, err := s.store.SAdd(key, request.Members...)
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to add to set")
+		}
+
+		return ctx.JSON(http.StatusOK, countSuccessResponse{Error: false, Count: int64(added)})
+	}
+}
+
+func (s *AmpKVHttpServer) handleSetRemove() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key, err := collectionKey(ctx, auth.PermDelete)
+		if err != nil {
+			return err
+		}
+
+		removed, err := s.store.SRem(key, ctx.Param("member"))
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to remove from set")
+		}
+
+		return ctx.JSON(http.StatusOK, countSuccessResponse{Error: false, Count: int64(removed)})
+	}
+}
+
+func (s *AmpKVHttpServer) handleSetMembers() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key, err := collectionKey(ctx, auth.PermRead)
+		if err != nil {
+			return err
+		}
+
+		members, err := s.store.SMembers(key)
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to read set")
+		}
+		if members == nil {
+			members = []string{}
+		}
+
+		return ctx.JSON(http.StatusOK, setMembersSuccessResponse{Error: false, Members: members})
+	}
+}
+
+func (s *AmpKVHttpServer) handleHashSet() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		var request hashSetRequest
+		if err := ctx.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "body malformed")
+		}
+		if request.Value == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "value is required")
+		}
+
+		key, err := collectionKey(ctx, auth.PermWrite)
+		if err != nil {
+			return err
+		}
+
+		created, err := s.store.HSet(key, ctx.Param("field"), request.Value)
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to set hash field")
+		}
+
+		return ctx.JSON(http.StatusOK, hashSetSuccessResponse{Error: false, Created: created})
+	}
+}
+
+func (s *AmpKVHttpServer) handleHashGet() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key, err := collectionKey(ctx, auth.PermRead)
+		if err != nil {
+			return err
+		}
+
+		value, err := s.store.HGet(key, ctx.Param("field"))
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to get hash field")
+		}
+
+		return ctx.JSON(http.StatusOK, itemSuccessResponse{Error: false, Type: value.Type, Value: value.Data})
+	}
+}
+
+func (s *AmpKVHttpServer) handleHashGetAll() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key, err := collectionKey(ctx, auth.PermRead)
+		if err != nil {
+			return err
+		}
+
+		values, err := s.store.HGetAll(key)
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to read hash")
+		}
+
+		fields := make(map[string]typedItem, len(values))
+		for field, value := range values {
+			fields[field] = toTypedItem(value)
+		}
+		return ctx.JSON(http.StatusOK, hashGetAllSuccessResponse{Error: false, Fields: fields})
+	}
+}
+
+func (s *AmpKVHttpServer) handleHashDelete() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key, err := collectionKey(ctx, auth.PermDelete)
+		if err != nil {
+			return err
+		}
+
+		removed, err := s.store.HDel(key, ctx.Param("field"))
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to delete hash field")
+		}
+
+		return ctx.JSON(http.StatusOK, countSuccessResponse{Error: false, Count: int64(removed)})
+	}
+}
+
+func (s *AmpKVHttpServer) handleSortedSetAdd() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		var request sortedSetAddRequest
+		if err := ctx.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "body malformed")
+		}
+
+		key, err := collectionKey(ctx, auth.PermWrite)
+		if err != nil {
+			return err
+		}
+
+		members := make([]embedded.ZMember, 0, len(request.Members))
+		for _, member := range request.Members {
+			members = append(members, embedded.ZMember{Member: member.Member, Score: member.Score})
+		}
+
+		added, err := s.store.ZAdd(key, members...)
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to add to sorted set")
+		}
+
+		return ctx.JSON(http.StatusOK, countSuccessResponse{Error: false, Count: int64(added)})
+	}
+}
+
+func (s *AmpKVHttpServer) handleSortedSetRemove() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key, err := collectionKey(ctx, auth.PermDelete)
+		if err != nil {
+			return err
+		}
+
+		removed, err := s.store.ZRem(key, ctx.Param("member"))
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to remove from sorted set")
+		}
+
+		return ctx.JSON(http.StatusOK, countSuccessResponse{Error: false, Count: int64(removed)})
+	}
+}
+
+// handleSortedSetRange lists members by score; min and max default to the
+// whole range and limit to every member.
+func (s *AmpKVHttpServer) handleSortedSetRange() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key, err := collectionKey(ctx, auth.PermRead)
+		if err != nil {
+			return err
+		}
+
+		minScore, maxScore := math.Inf(-1), math.Inf(1)
+		if raw := ctx.QueryParam("min"); raw != "" {
+			if minScore, err = strconv.ParseFloat(raw, 64); err != nil || math.IsNaN(minScore) {
+				return echo.NewHTTPError(http.StatusBadRequest, "min must be a number")
+			}
+		}
+		if raw := ctx.QueryParam("max"); raw != "" {
+			if maxScore, err = strconv.ParseFloat(raw, 64); err != nil || math.IsNaN(maxScore) {
+				return echo.NewHTTPError(http.StatusBadRequest, "max must be a number")
+			}
+		}
+		limit := 0
+		if raw := ctx.QueryParam("limit"); raw != "" {
+			if limit, err = strconv.Atoi(raw); err != nil || limit < 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "limit must be a non-negative integer")
+			}
+		}
+
+		members, err := s.store.ZRangeByScore(key, minScore, maxScore, limit)
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to read sorted set")
+		}
+
+		scored := make([]scoredMember, 0, len(members))
+		for _, member := range members {
+			scored = append(scored, scoredMember{Member: member.Member, Score: member.Score})
+		}
+		return ctx.JSON(http.StatusOK, sortedSetRangeSuccessResponse{Error: false, Members: scored})
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Unfield/AmpKV/internal/auth"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"github.com/labstack/echo/v4"
+)
+
+// incrementRequest adds Delta, 1 if it is absent, to a counter. Integer
+// literals increment TypeInt values, other numbers TypeFloat values.
+type incrementRequest struct {
+	Delta *json.Number `json:"delta"`
+}
+
+type incrementSuccessResponse struct {
+	Error bool `json:"error"`
+	Value any  `json:"value"`
+}
+
+func (s *AmpKVHttpServer) handleIncrement() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key := ctx.Param("key")
+
+		if len(key) < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "key is required")
+		}
+
+		var request incrementRequest
+		if err := ctx.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "body malformed")
+		}
+		if err := authorizeKeys(ctx, auth.PermWrite, key); err != nil {
+			return err
+		}
+
+		delta := json.Number("1")
+		if request.Delta != nil {
+			delta = *request.Delta
+		}
+
+		var (
+			value any
+			err   error
+		)
+		if intDelta, parseErr := delta.Int64(); parseErr == nil {
+			value, err = s.store.IncrBy(key, intDelta)
+			// Numbers set through this API are stored as floats, so an
+			// integer delta increments those as well.
+			if errors.Is(err, embedded.ErrTypeMismatch) {
+				value, err = s.store.IncrByFloat(key, float64(intDelta))
+			}
+		} else if floatDelta, parseErr := delta.Float64(); parseErr == nil {
+			value, err = s.store.IncrByFloat(key, floatDelta)
+		} else {
+			return echo.NewHTTPError(http.StatusBadRequest, "delta must be a number")
+		}
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to increment data")
+		}
+
+		return ctx.JSON(http.StatusOK, incrementSuccessResponse{Error: false, Value: value})
+	}
+}
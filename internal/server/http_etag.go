@@ -0,0 +1,80 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Unfield/AmpKV/pkg/common"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"github.com/labstack/echo/v4"
+)
+
+const etagWildcard = "*"
+
+func formatETag(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// preconditionFromHeaders turns If-Match and If-None-Match into a write
+// condition. It returns a nil condition when neither header is present.
+func preconditionFromHeaders(header http.Header) (embedded.Condition, error) {
+	ifMatch, err := parseETags(header.Get("If-Match"))
+	if err != nil {
+		return nil, err
+	}
+	ifNoneMatch, err := parseETags(header.Get("If-None-Match"))
+	if err != nil {
+		return nil, err
+	}
+
+	if ifMatch == nil && ifNoneMatch == nil {
+		return nil, nil
+	}
+
+	return func(current *common.AmpKVValue) error {
+		if ifMatch != nil && !etagsMatch(ifMatch, current) {
+			return embedded.ErrVersionMismatch
+		}
+		if ifNoneMatch != nil && etagsMatch(ifNoneMatch, current) {
+			return embedded.ErrKeyExists
+		}
+		return nil
+	}, nil
+}
+
+func parseETags(header string) ([]string, error) {
+	if strings.TrimSpace(header) == "" {
+		return nil, nil
+	}
+
+	var etags []string
+	for _, raw := range strings.Split(header, ",") {
+		etag := strings.TrimPrefix(strings.TrimSpace(raw), "W/")
+		if etag == etagWildcard {
+			etags = append(etags, etag)
+			continue
+		}
+
+		unquoted, err := strconv.Unquote(etag)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "malformed entity tag")
+		}
+		if _, err := strconv.ParseUint(unquoted, 10, 64); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "malformed entity tag")
+		}
+		etags = append(etags, unquoted)
+	}
+	return etags, nil
+}
+
+func etagsMatch(etags []string, current *common.AmpKVValue) bool {
+	if current == nil {
+		return false
+	}
+	if slices.Contains(etags, etagWildcard) {
+		return true
+	}
+	return slices.Contains(etags, strconv.FormatUint(current.Version, 10))
+}
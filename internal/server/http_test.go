@@ -0,0 +1,210 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+func TestHttpValueMetadata(t *testing.T) {
+	ampkv, _, _ := newTestManager(t)
+	handler := NewAmpKVHttpServer(ampkv, nil, nil).e
+
+	if err := ampkv.SetWithTTL("meta-key", "hello", 1, time.Hour); err != nil {
+		t.Fatalf("SetWithTTL failed: %v", err)
+	}
+	val, err := ampkv.Get("meta-key")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/meta-key", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	if got, want := rec.Header().Get("Last-Modified"), val.UpdatedAt.UTC().Format(http.TimeFormat); got != want {
+		t.Errorf("Expected Last-Modified %q, got %q", want, got)
+	}
+	if got, want := rec.Header().Get("Expires"), val.ExpiresAt.UTC().Format(http.TimeFormat); got != want {
+		t.Errorf("Expected Expires %q, got %q", want, got)
+	}
+
+	var resp getSuccessResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Size != 5 || resp.CreatedAt == nil || !resp.CreatedAt.Equal(val.CreatedAt) || resp.ExpiresAt == nil || !resp.ExpiresAt.Equal(*val.ExpiresAt) {
+		t.Errorf("Unexpected metadata in response: %+v", resp)
+	}
+}
+
+func TestHttpTTL(t *testing.T) {
+	ampkv, _, _ := newTestManager(t)
+	handler := NewAmpKVHttpServer(ampkv, nil, nil).e
+
+	if err := ampkv.Set("ttl-key", "hello", 1); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	etag := func() string {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ttl-key", nil))
+		return rec.Header().Get("ETag")
+	}
+	written := etag()
+
+	var resp ttlResponse
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/ttl-key/ttl", "", "", &resp); code != http.StatusOK || resp.TTL != 0 || resp.ExpiresAt != nil {
+		t.Errorf("Expected a key without TTL, got %d %+v", code, resp)
+	}
+
+	resp = ttlResponse{}
+	if code := doRequest(t, handler, http.MethodPatch, "/api/v1/ttl-key/ttl", "", `{"ttl":3600000000000}`, &resp); code != http.StatusOK || resp.TTL <= 59*time.Minute || resp.ExpiresAt == nil {
+		t.Errorf("Expected a TTL of about an hour, got %d %+v", code, resp)
+	}
+
+	expiresAt := time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339)
+	resp = ttlResponse{}
+	if code := doRequest(t, handler, http.MethodPatch, "/api/v1/ttl-key/ttl", "", `{"expires_at":"`+expiresAt+`"}`, &resp); code != http.StatusOK || resp.TTL <= time.Hour {
+		t.Errorf("Expected a TTL of about two hours, got %d %+v", code, resp)
+	}
+
+	resp = ttlResponse{}
+	if code := doRequest(t, handler, http.MethodPatch, "/api/v1/ttl-key/ttl", "", `{"persist":true}`, &resp); code != http.StatusOK || resp.TTL != 0 {
+		t.Errorf("Expected the TTL to be removed, got %d %+v", code, resp)
+	}
+	if got := etag(); written == "" || got != written {
+		t.Errorf("Expected changing the TTL to keep the ETag %q, got %q", written, got)
+	}
+
+	for name, body := range map[string]string{"Empty": `{}`, "Ambiguous": `{"ttl":1000000000,"persist":true}`} {
+		if code := doRequest(t, handler, http.MethodPatch, "/api/v1/ttl-key/ttl", "", body, nil); code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", name, code)
+		}
+	}
+	if code := doRequest(t, handler, http.MethodPatch, "/api/v1/missing/ttl", "", `{"persist":true}`, nil); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing key, got %d", code)
+	}
+
+	if code := doRequest(t, handler, http.MethodPatch, "/api/v1/ttl-key/ttl", "", `{"ttl":0}`, nil); code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", code)
+	}
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/ttl-key", "", "", nil); code != http.StatusNotFound {
+		t.Errorf("Expected a TTL of 0 to delete the key, got %d", code)
+	}
+}
+
+func TestHttpIncrement(t *testing.T) {
+	ampkv, _, _ := newTestManager(t)
+	handler := NewAmpKVHttpServer(ampkv, nil, nil).e
+
+	var resp incrementSuccessResponse
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/hits/incr", "", "", &resp); code != http.StatusOK || resp.Value != float64(1) {
+		t.Errorf("Expected a new counter of 1, got %d %+v", code, resp)
+	}
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/hits/incr", "", `{"delta":-3}`, &resp); code != http.StatusOK || resp.Value != float64(-2) {
+		t.Errorf("Expected -2, got %d %+v", code, resp)
+	}
+	if val, err := ampkv.Get("hits"); err != nil || val.Type != common.TypeInt {
+		t.Errorf("Expected an int counter, got %+v (%v)", val, err)
+	}
+
+	// Json numbers are stored as floats and incremented by integer deltas too.
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/", "", `{"key":"score","value":1.5}`, nil); code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", code)
+	}
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/score/incr", "", `{"delta":2}`, &resp); code != http.StatusOK || resp.Value != 3.5 {
+		t.Errorf("Expected 3.5, got %d %+v", code, resp)
+	}
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/score/incr", "", `{"delta":0.25}`, &resp); code != http.StatusOK || resp.Value != 3.75 {
+		t.Errorf("Expected 3.75, got %d %+v", code, resp)
+	}
+
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/", "", `{"key":"name","value":"text"}`, nil); code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", code)
+	}
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/name/incr", "", `{"delta":1}`, nil); code != http.StatusConflict {
+		t.Errorf("Expected 409 for a string value, got %d", code)
+	}
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/hits/incr", "", `{"delta":"many"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a non-numeric delta, got %d", code)
+	}
+}
+
+func TestHttpCollections(t *testing.T) {
+	ampkv, _, _ := newTestManager(t)
+	handler := NewAmpKVHttpServer(ampkv, nil, nil).e
+
+	var count countSuccessResponse
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/queue/list", "", `{"values":["a","b","c"]}`, &count); code != http.StatusOK || count.Count != 3 {
+		t.Errorf("Expected a list of 3, got %d %+v", code, count)
+	}
+	var item itemSuccessResponse
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/queue/list/pop?end=left", "", "", &item); code != http.StatusOK || string(item.Value) != "a" {
+		t.Errorf("Expected to pop a, got %d %+v", code, item)
+	}
+	var values listRangeSuccessResponse
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/queue/list?start=-1", "", "", &values); code != http.StatusOK || len(values.Values) != 1 || string(values.Values[0].Value) != "c" {
+		t.Errorf("Expected [c], got %d %+v", code, values)
+	}
+	// A plain write replaces the list as a whole.
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/", "", `{"key":"queue","value":"text"}`, nil); code != http.StatusCreated {
+		t.Errorf("Expected 201 for overwriting a list, got %d", code)
+	}
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/queue/list", "", "", nil); code != http.StatusConflict {
+		t.Errorf("Expected 409 for reading a string as a list, got %d", code)
+	}
+
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/tags/set", "", `{"members":["x","y","x"]}`, &count); code != http.StatusOK || count.Count != 2 {
+		t.Errorf("Expected 2 new members, got %d %+v", code, count)
+	}
+	if code := doRequest(t, handler, http.MethodDelete, "/api/v1/tags/set/x", "", "", &count); code != http.StatusOK || count.Count != 1 {
+		t.Errorf("Expected to remove 1 member, got %d %+v", code, count)
+	}
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/tags", "", "", nil); code != http.StatusConflict {
+		t.Errorf("Expected 409 for reading a set as a scalar, got %d", code)
+	}
+	var members setMembersSuccessResponse
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/tags/set", "", "", &members); code != http.StatusOK || !slices.Equal(members.Members, []string{"y"}) {
+		t.Errorf("Expected [y], got %d %+v", code, members)
+	}
+
+	var created hashSetSuccessResponse
+	if code := doRequest(t, handler, http.MethodPut, "/api/v1/user/hash/name", "", `{"value":"ada"}`, &created); code != http.StatusOK || !created.Created {
+		t.Errorf("Expected a new field, got %d %+v", code, created)
+	}
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/user/hash/name", "", "", &item); code != http.StatusOK || string(item.Value) != "ada" {
+		t.Errorf("Expected ada, got %d %+v", code, item)
+	}
+	var fields hashGetAllSuccessResponse
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/user/hash", "", "", &fields); code != http.StatusOK || len(fields.Fields) != 1 {
+		t.Errorf("Expected 1 field, got %d %+v", code, fields)
+	}
+	if code := doRequest(t, handler, http.MethodDelete, "/api/v1/user/hash/name", "", "", &count); code != http.StatusOK || count.Count != 1 {
+		t.Errorf("Expected to delete 1 field, got %d %+v", code, count)
+	}
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/user/hash/name", "", "", nil); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a deleted field, got %d", code)
+	}
+
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/board/zset", "", `{"members":[{"member":"a","score":3},{"member":"b","score":1},{"member":"c","score":2}]}`, &count); code != http.StatusOK || count.Count != 3 {
+		t.Errorf("Expected 3 new members, got %d %+v", code, count)
+	}
+	var scored sortedSetRangeSuccessResponse
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/board/zset?min=2&limit=1", "", "", &scored); code != http.StatusOK || len(scored.Members) != 1 || scored.Members[0].Member != "c" {
+		t.Errorf("Expected [c], got %d %+v", code, scored)
+	}
+	if code := doRequest(t, handler, http.MethodDelete, "/api/v1/board/zset/c", "", "", &count); code != http.StatusOK || count.Count != 1 {
+		t.Errorf("Expected to remove 1 member, got %d %+v", code, count)
+	}
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/board/zset?min=oops", "", "", nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a non-numeric min, got %d", code)
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/auth"
+	"github.com/labstack/echo/v4"
+)
+
+// ttlRequest changes the expiry of a key. Exactly one field must be set; a
+// TTL of zero or less, or an ExpiresAt in the past, deletes the key.
+type ttlRequest struct {
+	TTL       *time.Duration `json:"ttl"`
+	ExpiresAt *time.Time     `json:"expires_at"`
+	Persist   bool           `json:"persist"`
+}
+
+// ttlResponse reports the remaining TTL of a key, which is absent for keys
+// that do not expire.
+type ttlResponse struct {
+	Error     bool          `json:"error"`
+	TTL       time.Duration `json:"ttl,omitempty"`
+	ExpiresAt *time.Time    `json:"expires_at,omitempty"`
+}
+
+func (s *AmpKVHttpServer) handleGetTTL() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key := ctx.Param("key")
+
+		if len(key) < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "key is required")
+		}
+		if err := authorizeKeys(ctx, auth.PermRead, key); err != nil {
+			return err
+		}
+
+		return s.respondTTL(ctx, key)
+	}
+}
+
+func (s *AmpKVHttpServer) handleSetTTL() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key := ctx.Param("key")
+
+		if len(key) < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "key is required")
+		}
+
+		var request ttlRequest
+		if err := ctx.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "body malformed")
+		}
+
+		set := 0
+		for _, isSet := range []bool{request.TTL != nil, request.ExpiresAt != nil, request.Persist} {
+			if isSet {
+				set++
+			}
+		}
+		if set != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "exactly one of ttl, expires_at and persist is required")
+		}
+
+		var ttl time.Duration
+		switch {
+		case request.TTL != nil:
+			ttl = *request.TTL
+		case request.ExpiresAt != nil:
+			ttl = time.Until(*request.ExpiresAt)
+		}
+
+		perms := []auth.Permission{auth.PermWrite}
+		if !request.Persist && ttl <= 0 {
+			perms = append(perms, auth.PermDelete)
+		}
+		for _, perm := range perms {
+			if err := authorizeKeys(ctx, perm, key); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		switch {
+		case request.Persist:
+			err = s.store.Persist(key)
+		case request.ExpiresAt != nil:
+			err = s.store.ExpireAt(key, *request.ExpiresAt)
+		default:
+			err = s.store.Expire(key, ttl)
+		}
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to set TTL")
+		}
+		if !request.Persist && ttl <= 0 {
+			return ctx.JSON(http.StatusOK, ttlResponse{Error: false})
+		}
+
+		return s.respondTTL(ctx, key)
+	}
+}
+
+func (s *AmpKVHttpServer) respondTTL(ctx echo.Context, key string) error {
+	ttl, err := s.store.TTL(key)
+	if err != nil {
+		return storageErrorToHTTPError(err, "failed to read TTL")
+	}
+
+	resp := ttlResponse{Error: false}
+	if ttl > 0 {
+		resp.TTL = ttl
+		expiresAt := time.Now().Add(ttl)
+		resp.ExpiresAt = &expiresAt
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
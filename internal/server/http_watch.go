@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/auth"
+	"github.com/Unfield/AmpKV/pkg/common"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"github.com/labstack/echo/v4"
+)
+
+const sseHeartbeatInterval = 15 * time.Second
+
+type watchEventPayload struct {
+	Key      string                `json:"key"`
+	Revision uint64                `json:"revision"`
+	Type     *common.AmpKVDataType `json:"type,omitempty"`
+	Value    []byte                `json:"value,omitempty"`
+}
+
+// handleWatch streams changes as Server-Sent Events. Every event carries its
+// revision as the event ID, so reconnecting clients resume via Last-Event-ID.
+func (s *AmpKVHttpServer) handleWatch() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key := ctx.QueryParam("key")
+		prefix := ctx.QueryParam("prefix") == "true"
+		if len(key) < 1 && !prefix {
+			return echo.NewHTTPError(http.StatusBadRequest, "key is required unless watching a prefix")
+		}
+		if err := authorizeKeys(ctx, auth.PermRead, key); err != nil {
+			return err
+		}
+
+		var startRevision uint64
+		if rawRevision := ctx.QueryParam("start_revision"); rawRevision != "" {
+			parsedRevision, err := strconv.ParseUint(rawRevision, 10, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "start_revision must be a non-negative integer")
+			}
+			startRevision = parsedRevision
+		}
+		if lastEventID := ctx.Request().Header.Get("Last-Event-ID"); lastEventID != "" {
+			lastRevision, err := strconv.ParseUint(lastEventID, 10, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Last-Event-ID must be a revision")
+			}
+			startRevision = lastRevision + 1
+		}
+
+		events, err := s.store.Watch(ctx.Request().Context(), key, embedded.WatchOptions{
+			Prefix:        prefix,
+			StartRevision: startRevision,
+		})
+		if errors.Is(err, embedded.ErrCompacted) {
+			return echo.NewHTTPError(http.StatusGone, "requested revision is no longer available")
+		}
+		if err != nil {
+			return storageErrorToHTTPError(err, "failed to start watch")
+		}
+
+		res := ctx.Response()
+		res.Header().Set(echo.HeaderContentType, "text/event-stream")
+		res.Header().Set(echo.HeaderCacheControl, "no-cache")
+		res.Header().Set(echo.HeaderConnection, "keep-alive")
+		res.WriteHeader(http.StatusOK)
+		res.Flush()
+
+		heartbeat := time.NewTicker(sseHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return nil
+				}
+				if auth.IsReservedKey(event.Key) {
+					continue
+				}
+				if err := writeWatchEvent(res, event); err != nil {
+					return nil
+				}
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+					return nil
+				}
+			}
+			res.Flush()
+		}
+	}
+}
+
+func writeWatchEvent(res *echo.Response, event embedded.WatchEvent) error {
+	payload := watchEventPayload{Key: event.Key, Revision: event.Revision}
+	if event.Value != nil {
+		payload.Type = &event.Value.Type
+		payload.Value = event.Value.Data
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", event.Revision, strings.ToLower(event.Type.String()), data)
+	return err
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/auth"
+)
+
+type staticKeySet struct {
+	key ed25519.PublicKey
+}
+
+func (s staticKeySet) Key(kid, alg string) (crypto.PublicKey, error) {
+	if kid != "test" {
+		return nil, auth.ErrKeyNotFound
+	}
+	return s.key, nil
+}
+
+func signEd25519JWT(t *testing.T, key ed25519.PrivateKey, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "EdDSA", "kid": "test"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("Failed to encode claims: %v", err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(signed)))
+}
+
+func TestHttpJWTAuth(t *testing.T) {
+	ampkv, manager, adminToken := newTestManager(t)
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	manager.AddAuthenticator(auth.NewJWTAuthenticator(staticKeySet{key: public}, auth.JWTOptions{Audience: "ampkv"}))
+	handler := NewAmpKVHttpServer(ampkv, manager, nil).e
+
+	token := signEd25519JWT(t, private, map[string]any{
+		"sub":    "workload-1",
+		"aud":    "ampkv",
+		"exp":    time.Now().Add(time.Minute).Unix(),
+		"scopes": []map[string]any{{"prefix": "tenant-a:", "permissions": []string{"read"}}},
+	})
+	expired := signEd25519JWT(t, private, map[string]any{
+		"sub":         "workload-1",
+		"aud":         "ampkv",
+		"exp":         time.Now().Add(-time.Hour).Unix(),
+		"permissions": []string{"admin"},
+	})
+
+	for _, tc := range []struct {
+		name, path, token string
+		code              int
+	}{
+		{"InScope", "/api/v1/tenant-a:one", token, http.StatusNotFound},
+		{"OutOfScope", "/api/v1/tenant-b:one", token, http.StatusForbidden},
+		{"Admin", "/api/v1/admin/keys", token, http.StatusForbidden},
+		{"Expired", "/api/v1/tenant-a:one", expired, http.StatusUnauthorized},
+		{"ApiKey", "/api/v1/admin/keys", adminToken, http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if code := doRequest(t, handler, http.MethodGet, tc.path, tc.token, "", nil); code != tc.code {
+				t.Errorf("Expected %d, got %d", tc.code, code)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name, header string
+		code         int
+	}{
+		{"Standard", "Bearer " + token, http.StatusNotFound},
+		{"LowerCase", "bearer " + token, http.StatusNotFound},
+		{"LegacyColon", "Bearer: " + token, http.StatusNotFound},
+		{"OtherScheme", "Basic " + token, http.StatusUnauthorized},
+		{"MissingToken", "Bearer", http.StatusUnauthorized},
+	} {
+		t.Run("Header"+tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tenant-a:one", nil)
+			req.Header.Set("Authorization", tc.header)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.code {
+				t.Errorf("Expected %d, got %d", tc.code, rec.Code)
+			}
+		})
+	}
+}
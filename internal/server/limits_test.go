@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/auth"
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestHttpRateLimit(t *testing.T) {
+	ampkv, manager, adminToken := newTestManager(t)
+	handler := NewAmpKVHttpServer(ampkv, manager, nil).e
+
+	var created apiKeySuccessResponse
+	doRequest(t, handler, http.MethodPost, "/api/v1/admin/keys", adminToken, `{"name":"limited-key","permissions":["read"]}`, &created)
+	var updated apiKeySuccessResponse
+	code := doRequest(t, handler, http.MethodPut, "/api/v1/admin/keys/"+created.Key.ID+"/limits", adminToken, `{"requests_per_second":0.5,"burst":1}`, &updated)
+	if code != http.StatusOK || updated.Key.Limits == nil || updated.Key.Limits.Burst != 1 {
+		t.Fatalf("Expected limits to be set, got %d %+v", code, updated.Key)
+	}
+
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/some-key", created.Token, "", nil); code != http.StatusNotFound {
+		t.Fatalf("Expected the first request to pass, got %d", code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/some-key", nil)
+	req.Header.Set("Authorization", "Bearer "+created.Token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429, got %d", rec.Code)
+	}
+	if retryAfter := rec.Header().Get("Retry-After"); retryAfter != "2" {
+		t.Errorf("Expected Retry-After of 2 seconds, got %q", retryAfter)
+	}
+}
+
+func TestGrpcQuota(t *testing.T) {
+	ampkv, manager, _ := newTestManager(t)
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(grpc.UnaryInterceptor(AuthUnaryServerInterceptor(manager, nil)))
+	pb.RegisterAmpKVServiceServer(s, NewAmpKVGrpcServer(ampkv))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewAmpKVServiceClient(conn)
+
+	apiKey, err := manager.CreateAPIKey("quota-key", []auth.Permission{auth.PermRead}, false, nil)
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	if err := manager.SetLimits(apiKey.ID, auth.Limits{DailyOps: 1}); err != nil {
+		t.Fatalf("SetLimits failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, apiKeyMetadataKey, apiKey.Key)
+
+	if _, err := client.Get(ctx, &pb.GetRequest{Key: "some-key"}); err != nil {
+		t.Fatalf("Expected the first request to pass, got %v", err)
+	}
+
+	_, err = client.Get(ctx, &pb.GetRequest{Key: "some-key"})
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted, got %v", err)
+	}
+	var retryInfo *errdetails.RetryInfo
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retryInfo = info
+		}
+	}
+	if retryInfo == nil || retryInfo.RetryDelay.AsDuration() <= 0 {
+		t.Errorf("Expected a retry delay, got %v", st.Details())
+	}
+}
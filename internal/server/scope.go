@@ -0,0 +1,149 @@
+package server
+
+import (
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/auth"
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reservedScanEnd is the first key after every reserved key. Scans covering
+// the reserved range continue from here.
+const reservedScanEnd = "internal:;"
+
+// keyAccess is a key, or for Scan and Watch a prefix, a request touches and
+// the permission it needs on it.
+type keyAccess struct {
+	key  string
+	perm auth.Permission
+}
+
+// requestKeyAccess lists the keys touched by a data request. It returns false
+// for requests it does not know, which are not data requests.
+func requestKeyAccess(req any) ([]keyAccess, bool) {
+	switch r := req.(type) {
+	case *pb.GetRequest:
+		return []keyAccess{{r.Key, auth.PermRead}}, true
+	case *pb.SetRequest:
+		return []keyAccess{{r.GetKv().GetKey(), auth.PermWrite}}, true
+	case *pb.SetWithTTLRequest:
+		return []keyAccess{{r.GetKv().GetKey(), auth.PermWrite}}, true
+	case *pb.DeleteRequest:
+		return []keyAccess{{r.Key, auth.PermDelete}}, true
+	case *pb.CompareAndSwapRequest:
+		return []keyAccess{{r.GetKv().GetKey(), auth.PermWrite}}, true
+	case *pb.ScanRequest:
+		return []keyAccess{{r.Prefix, auth.PermRead}}, true
+	case *pb.WatchRequest:
+		return []keyAccess{{r.Key, auth.PermRead}}, true
+	case *pb.TTLRequest:
+		return []keyAccess{{r.Key, auth.PermRead}}, true
+	case *pb.ExpireRequest:
+		// An expiry that has already passed deletes the key.
+		if r.GetTtlSeconds() <= 0 && r.GetExpiresAt() <= time.Now().UnixMilli() {
+			return []keyAccess{{r.Key, auth.PermWrite}, {r.Key, auth.PermDelete}}, true
+		}
+		return []keyAccess{{r.Key, auth.PermWrite}}, true
+	case *pb.PersistRequest:
+		return []keyAccess{{r.Key, auth.PermWrite}}, true
+	case *pb.IncrementRequest:
+		return []keyAccess{{r.Key, auth.PermWrite}}, true
+	case *pb.ListPushRequest:
+		return []keyAccess{{r.Key, auth.PermWrite}}, true
+	case *pb.ListPopRequest:
+		return []keyAccess{{r.Key, auth.PermWrite}}, true
+	case *pb.ListRangeRequest:
+		return []keyAccess{{r.Key, auth.PermRead}}, true
+	case *pb.SetAddRequest:
+		return []keyAccess{{r.Key, auth.PermWrite}}, true
+	case *pb.SetRemoveRequest:
+		return []keyAccess{{r.Key, auth.PermDelete}}, true
+	case *pb.SetMembersRequest:
+		return []keyAccess{{r.Key, auth.PermRead}}, true
+	case *pb.HashSetRequest:
+		return []keyAccess{{r.Key, auth.PermWrite}}, true
+	case *pb.HashGetRequest:
+		return []keyAccess{{r.Key, auth.PermRead}}, true
+	case *pb.HashDeleteRequest:
+		return []keyAccess{{r.Key, auth.PermDelete}}, true
+	case *pb.HashGetAllRequest:
+		return []keyAccess{{r.Key, auth.PermRead}}, true
+	case *pb.SortedSetAddRequest:
+		return []keyAccess{{r.Key, auth.PermWrite}}, true
+	case *pb.SortedSetRemoveRequest:
+		return []keyAccess{{r.Key, auth.PermDelete}}, true
+	case *pb.SortedSetRangeRequest:
+		return []keyAccess{{r.Key, auth.PermRead}}, true
+	case *pb.MultiGetRequest:
+		return keysAccess(r.Keys, auth.PermRead), true
+	case *pb.MultiDeleteRequest:
+		return keysAccess(r.Keys, auth.PermDelete), true
+	case *pb.MultiSetRequest:
+		access := make([]keyAccess, 0, len(r.Items))
+		for _, item := range r.Items {
+			access = append(access, keyAccess{item.GetKv().GetKey(), auth.PermWrite})
+		}
+		return access, true
+	case *pb.TxnRequest:
+		access := make([]keyAccess, 0, len(r.Compare)+len(r.Success)+len(r.Failure))
+		for _, compare := range r.Compare {
+			access = append(access, keyAccess{compare.Key, auth.PermRead})
+		}
+		for _, ops := range [][]*pb.TxnOp{r.Success, r.Failure} {
+			for _, op := range ops {
+				switch o := op.GetOp().(type) {
+				case *pb.TxnOp_Put:
+					access = append(access, keyAccess{o.Put.GetKv().GetKey(), auth.PermWrite})
+				case *pb.TxnOp_Delete:
+					access = append(access, keyAccess{o.Delete.Key, auth.PermDelete})
+				}
+			}
+		}
+		return access, true
+	default:
+		return nil, false
+	}
+}
+
+func keysAccess(keys []string, perm auth.Permission) []keyAccess {
+	access := make([]keyAccess, 0, len(keys))
+	for _, key := range keys {
+		access = append(access, keyAccess{key, perm})
+	}
+	return access
+}
+
+// rejectReservedKeys keeps data requests away from the reserved prefix,
+// whether or not authentication is enabled.
+func rejectReservedKeys(req any) error {
+	access, _ := requestKeyAccess(req)
+	for _, a := range access {
+		if auth.IsReservedKey(a.key) {
+			return status.Errorf(codes.PermissionDenied, "key prefix %q is reserved", auth.ReservedPrefix)
+		}
+	}
+	return nil
+}
+
+// visibleScanCursor moves a scan cursor that points into the reserved range
+// past it.
+func visibleScanCursor(cursor string) string {
+	if auth.IsReservedKey(cursor) {
+		return reservedScanEnd
+	}
+	return cursor
+}
+
+// visibleItems drops reserved keys from a scan page.
+func visibleItems(items []embedded.ScanItem) []embedded.ScanItem {
+	visible := items[:0:0]
+	for _, item := range items {
+		if !auth.IsReservedKey(item.Key) {
+			visible = append(visible, item)
+		}
+	}
+	return visible
+}
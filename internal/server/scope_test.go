@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/auth"
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestHttpScopes(t *testing.T) {
+	ampkv, manager, adminToken := newTestManager(t)
+	handler := NewAmpKVHttpServer(ampkv, manager, nil).e
+
+	var created apiKeySuccessResponse
+	code := doRequest(t, handler, http.MethodPost, "/api/v1/admin/keys", adminToken,
+		`{"name":"tenant-key","permissions":["read"],"scopes":[{"prefix":"tenant-a:","permissions":["write","delete"]}]}`, &created)
+	if code != http.StatusCreated || len(created.Key.Scopes) != 1 {
+		t.Fatalf("Expected scoped key to be created, got %d %+v", code, created)
+	}
+	token := created.Token
+
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/admin/keys", adminToken,
+		`{"name":"broken-key","scopes":[{"prefix":"internal::api::","permissions":["read"]}]}`, nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a reserved scope, got %d", code)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"SetInScope", http.MethodPost, "/api/v1/", `{"key":"tenant-a:one","value":"1"}`, http.StatusCreated},
+		{"SetOutOfScope", http.MethodPost, "/api/v1/", `{"key":"tenant-b:one","value":"1"}`, http.StatusForbidden},
+		{"GetGlobalRead", http.MethodGet, "/api/v1/tenant-b:one", "", http.StatusNotFound},
+		{"DeleteOutOfScope", http.MethodDelete, "/api/v1/tenant-b:one", "", http.StatusForbidden},
+		{"ExpireInScope", http.MethodPatch, "/api/v1/tenant-a:one/ttl", `{"ttl":60000000000}`, http.StatusOK},
+		{"ExpireOutOfScope", http.MethodPatch, "/api/v1/tenant-b:one/ttl", `{"persist":true}`, http.StatusForbidden},
+		{"IncrementOutOfScope", http.MethodPost, "/api/v1/tenant-b:hits/incr", "", http.StatusForbidden},
+		{"DeleteInScope", http.MethodDelete, "/api/v1/tenant-a:one", "", http.StatusOK},
+		{"BatchOutOfScope", http.MethodPost, "/api/v1/_batch", `{"operations":[{"op":"set","key":"tenant-a:two","value":"2"},{"op":"set","key":"tenant-b:two","value":"2"}]}`, http.StatusForbidden},
+		{"GetReserved", http.MethodGet, "/api/v1/internal::api::bootstrap", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := doRequest(t, handler, tt.method, tt.path, token, tt.body, nil); code != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, code)
+			}
+		})
+	}
+
+	t.Run("ReservedHiddenFromAdmin", func(t *testing.T) {
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/internal::api::bootstrap", adminToken, "", nil); code != http.StatusForbidden {
+			t.Errorf("Expected 403 for a reserved key, got %d", code)
+		}
+
+		var page scanSuccessResponse
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/?prefix=inter", adminToken, "", &page); code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", code)
+		}
+		if len(page.Items) != 0 || page.NextCursor != "" {
+			t.Errorf("Expected reserved keys to be hidden from scans, got %+v", page)
+		}
+	})
+}
+
+func TestHttpBatchPermissions(t *testing.T) {
+	ampkv, manager, adminToken := newTestManager(t)
+	handler := NewAmpKVHttpServer(ampkv, manager, nil).e
+
+	if err := ampkv.Set("tenant-a:one", "1", 1); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	for _, body := range []string{
+		`{"name":"reader-key","permissions":["read"]}`,
+		`{"name":"scoped-reader-key","scopes":[{"prefix":"tenant-a:","permissions":["read"]}]}`,
+	} {
+		var created apiKeySuccessResponse
+		if code := doRequest(t, handler, http.MethodPost, "/api/v1/admin/keys", adminToken, body, &created); code != http.StatusCreated {
+			t.Fatalf("Expected key to be created, got %d", code)
+		}
+
+		t.Run(created.Key.Name, func(t *testing.T) {
+			var response batchSuccessResponse
+			code := doRequest(t, handler, http.MethodPost, "/api/v1/_batch", created.Token,
+				`{"operations":[{"op":"get","key":"tenant-a:one"},{"op":"get","key":"tenant-a:two"}]}`, &response)
+			if code != http.StatusOK || len(response.Results) != 2 || response.Results[0].Status != http.StatusOK {
+				t.Errorf("Expected a get-only batch to succeed, got %d %+v", code, response)
+			}
+
+			code = doRequest(t, handler, http.MethodPost, "/api/v1/_batch", created.Token,
+				`{"operations":[{"op":"get","key":"tenant-a:one"},{"op":"set","key":"tenant-a:two","value":"2"}]}`, nil)
+			if code != http.StatusForbidden {
+				t.Errorf("Expected 403 for a batch with a write, got %d", code)
+			}
+			if _, err := ampkv.Get("tenant-a:two"); err == nil {
+				t.Error("Expected the forbidden batch not to write")
+			}
+		})
+	}
+}
+
+func TestGrpcScopes(t *testing.T) {
+	ampkv, manager, _ := newTestManager(t)
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(grpc.UnaryInterceptor(AuthUnaryServerInterceptor(manager, nil)))
+	pb.RegisterAmpKVServiceServer(s, NewAmpKVGrpcServer(ampkv))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewAmpKVServiceClient(conn)
+
+	apiKey, err := manager.CreateScopedAPIKey("tenant-key", nil, []auth.Scope{{Prefix: "tenant-a:", Permissions: []auth.Permission{auth.PermRead, auth.PermWrite}}}, false, nil)
+	if err != nil {
+		t.Fatalf("CreateScopedAPIKey failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, apiKeyMetadataKey, apiKey.Key)
+
+	kv := func(key string) *pb.KeyValue { return &pb.KeyValue{Key: key, Value: []byte("value")} }
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{"SetInScope", func() error { _, err := client.Set(ctx, &pb.SetRequest{Kv: kv("tenant-a:one")}); return err }, codes.OK},
+		{"SetOutOfScope", func() error { _, err := client.Set(ctx, &pb.SetRequest{Kv: kv("tenant-b:one")}); return err }, codes.PermissionDenied},
+		{"GetOutOfScope", func() error { _, err := client.Get(ctx, &pb.GetRequest{Key: "tenant-b:one"}); return err }, codes.PermissionDenied},
+		{"DeleteWithoutPermission", func() error { _, err := client.Delete(ctx, &pb.DeleteRequest{Key: "tenant-a:one"}); return err }, codes.PermissionDenied},
+		{"MultiGetMixed", func() error {
+			_, err := client.MultiGet(ctx, &pb.MultiGetRequest{Keys: []string{"tenant-a:one", "tenant-b:one"}})
+			return err
+		}, codes.PermissionDenied},
+		{"TxnOutOfScope", func() error {
+			_, err := client.Txn(ctx, &pb.TxnRequest{Success: []*pb.TxnOp{{Op: &pb.TxnOp_Put{Put: &pb.PutOp{Kv: kv("tenant-b:one")}}}}})
+			return err
+		}, codes.PermissionDenied},
+		{"ListPushInScope", func() error {
+			_, err := client.ListPush(ctx, &pb.ListPushRequest{Key: "tenant-a:list", Values: []*pb.TypedValue{{Value: []byte("value")}}})
+			return err
+		}, codes.OK},
+		{"SetRemoveWithoutPermission", func() error {
+			_, err := client.SetRemove(ctx, &pb.SetRemoveRequest{Key: "tenant-a:set", Members: []string{"one"}})
+			return err
+		}, codes.PermissionDenied},
+		{"HashGetAllOutOfScope", func() error {
+			_, err := client.HashGetAll(ctx, &pb.HashGetAllRequest{Key: "tenant-b:hash"})
+			return err
+		}, codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.call()); code != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, code)
+			}
+		})
+	}
+
+	t.Run("ReservedWithoutAuth", func(t *testing.T) {
+		server := NewAmpKVGrpcServer(ampkv)
+		if _, err := server.Get(context.Background(), &pb.GetRequest{Key: "internal::api::bootstrap"}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied for a reserved key, got %v", err)
+		}
+		if _, err := server.Set(context.Background(), &pb.SetRequest{Kv: kv("internal::api::key::X")}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied for a reserved key, got %v", err)
+		}
+		if _, err := server.SetMembers(context.Background(), &pb.SetMembersRequest{Key: "internal::col::00"}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied for a reserved key, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type TLSOptions struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile enables mutual TLS. Client certificates are verified
+	// against the CAs in this file.
+	ClientCAFile string
+	// RequireClientCert rejects clients without a certificate. Otherwise a
+	// certificate is only verified when the client presents one.
+	RequireClientCert bool
+}
+
+// CertReloader serves certificates loaded from files and picks up new ones
+// on Reload. Every handshake uses the latest certificates, so established
+// connections are not affected by a reload.
+type CertReloader struct {
+	options TLSOptions
+
+	reloadMu sync.Mutex
+	current  atomic.Pointer[tls.Config]
+	modTimes map[string]time.Time
+}
+
+func NewCertReloader(options TLSOptions) (*CertReloader, error) {
+	if options.CertFile == "" || options.KeyFile == "" {
+		return nil, errors.New("tls: cert and key file are required")
+	}
+
+	reloader := &CertReloader{options: options}
+	if err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// Reload reads the certificate, key and client CA files again. On failure the
+// previously loaded certificates stay in use.
+func (r *CertReloader) Reload() error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	modTimes, err := r.stat()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.options.CertFile, r.options.KeyFile)
+	if err != nil {
+		return fmt.Errorf("tls: failed to load key pair: %w", err)
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if r.options.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.options.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("tls: failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("tls: no certificates found in %s", r.options.ClientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if r.options.RequireClientCert {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	r.current.Store(config)
+	r.modTimes = modTimes
+	return nil
+}
+
+// ServerConfig returns a config for a listener that always uses the latest
+// certificates. nextProtos is advertised via ALPN.
+func (r *CertReloader) ServerConfig(nextProtos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			config := r.current.Load().Clone()
+			config.NextProtos = nextProtos
+			return config, nil
+		},
+	}
+}
+
+// Watch checks the files every interval and reloads them when one of them
+// changed. onReload is called with the result of every reload.
+func (r *CertReloader) Watch(ctx context.Context, interval time.Duration, onReload func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !r.changed() {
+			continue
+		}
+		err := r.Reload()
+		if onReload != nil {
+			onReload(err)
+		}
+	}
+}
+
+func (r *CertReloader) changed() bool {
+	modTimes, err := r.stat()
+	if err != nil {
+		// Files are often replaced in several steps, try again next time.
+		return false
+	}
+
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+	for path, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[path]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *CertReloader) stat() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time, 3)
+	for _, path := range []string{r.options.CertFile, r.options.KeyFile, r.options.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+		modTimes[path] = info.ModTime()
+	}
+	return modTimes, nil
+}
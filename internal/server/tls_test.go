@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM encoded certificate and key signed by the CA.
+func (ca *testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serve accepts connections on a TLS listener and completes their handshake.
+func serve(t *testing.T, config *tls.Config) string {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				conn.(*tls.Conn).Handshake()
+				conn.Write([]byte{1})
+				conn.Close()
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+// peerSerial connects to addr and returns the serial of the server certificate.
+func peerSerial(addr string, config *tls.Config) (int64, error) {
+	conn, err := tls.Dial("tcp", addr, config)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	// With TLS 1.3 a rejected client certificate only surfaces on read.
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		return 0, err
+	}
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestCertReloader(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	cert, key := ca.issue(t, 100, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, cert)
+	writeFile(t, keyFile, key)
+	writeFile(t, caFile, ca.pem)
+
+	reloader, err := NewCertReloader(TLSOptions{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, RequireClientCert: true})
+	if err != nil {
+		t.Fatalf("NewCertReloader failed: %v", err)
+	}
+	addr := serve(t, reloader.ServerConfig())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientCertPEM, clientKeyPEM := ca.issue(t, 200, x509.ExtKeyUsageClientAuth)
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientConfig := &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{clientCert}}
+
+	t.Run("MutualTLS", func(t *testing.T) {
+		serial, err := peerSerial(addr, clientConfig)
+		if err != nil {
+			t.Fatalf("handshake failed: %v", err)
+		}
+		if serial != 100 {
+			t.Errorf("Expected serial 100, got %d", serial)
+		}
+
+		if _, err := peerSerial(addr, &tls.Config{RootCAs: roots, ServerName: "localhost"}); err == nil {
+			t.Error("Expected handshake without client certificate to fail")
+		}
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		cert, key := ca.issue(t, 101, x509.ExtKeyUsageServerAuth)
+		writeFile(t, certFile, cert)
+		writeFile(t, keyFile, key)
+		if err := reloader.Reload(); err != nil {
+			t.Fatalf("Reload failed: %v", err)
+		}
+
+		serial, err := peerSerial(addr, clientConfig)
+		if err != nil {
+			t.Fatalf("handshake failed: %v", err)
+		}
+		if serial != 101 {
+			t.Errorf("Expected serial 101 after reload, got %d", serial)
+		}
+	})
+
+	t.Run("ReloadKeepsPreviousOnError", func(t *testing.T) {
+		writeFile(t, keyFile, []byte("garbage"))
+		if err := reloader.Reload(); err == nil {
+			t.Fatal("Expected Reload to fail with a broken key")
+		}
+
+		serial, err := peerSerial(addr, clientConfig)
+		if err != nil {
+			t.Fatalf("handshake failed: %v", err)
+		}
+		if serial != 101 {
+			t.Errorf("Expected previous serial 101, got %d", serial)
+		}
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		reloaded := make(chan error, 1)
+		go reloader.Watch(ctx, 10*time.Millisecond, func(err error) {
+			if err == nil {
+				select {
+				case reloaded <- err:
+				default:
+				}
+			}
+		})
+
+		cert, key := ca.issue(t, 102, x509.ExtKeyUsageServerAuth)
+		writeFile(t, certFile, cert)
+		writeFile(t, keyFile, key)
+
+		select {
+		case <-reloaded:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected Watch to reload the changed files")
+		}
+
+		serial, err := peerSerial(addr, clientConfig)
+		if err != nil {
+			t.Fatalf("handshake failed: %v", err)
+		}
+		if serial != 102 {
+			t.Errorf("Expected serial 102 after watch reload, got %d", serial)
+		}
+	})
+}
@@ -3,19 +3,66 @@ package storage
 import "time"
 
 type ICache interface {
-	Get(key string) ([]byte, bool)
+	Get(key string) ([]byte, error)
 	Set(key string, value []byte, cost int64) error
 	SetWithTTL(key string, value []byte, cost int64, ttl time.Duration) error
-	Delete(key string)
+	SetMany(entries []Entry) error
+	Delete(key string) error
 	Close() error
 	IsNil() bool
 }
 
 type KVStore interface {
-	Get(key string) ([]byte, bool)
+	Get(key string) ([]byte, error)
+	GetMany(keys []string) (map[string][]byte, error)
 	Set(key string, value []byte, cost int64) error
 	SetWithTTL(key string, value []byte, cost int64, ttl time.Duration) error
-	Delete(key string)
+	SetMany(entries []Entry) error
+	Delete(key string) error
+	DeleteMany(keys []string) error
+	Iterate(options IteratorOptions) (Iterator, error)
+	Update(fn func(txn Txn) error) error
+	View(fn func(txn Txn) error) error
 	Close() error
 	IsNil() bool
 }
+
+// ExpiryReader is implemented by stores that can report when a key expires
+// without decoding its value. The zero time means the key does not expire.
+type ExpiryReader interface {
+	ExpiresAt(key string) (time.Time, error)
+}
+
+// Txn is a store transaction. Writes become visible to other readers only
+// once the surrounding Update returns without error; a commit that loses
+// against a concurrent writer fails with ErrConflict.
+type Txn interface {
+	Get(key string) ([]byte, error)
+	Set(key string, value []byte) error
+	SetWithTTL(key string, value []byte, ttl time.Duration) error
+	Delete(key string) error
+}
+
+// Entry is a single write of a batch. A TTL of zero means the entry does not
+// expire.
+type Entry struct {
+	Key   string
+	Value []byte
+	Cost  int64
+	TTL   time.Duration
+}
+
+// IteratorOptions selects the keys visited by an Iterator. Keys are visited in
+// ascending byte order, starting at Start (inclusive) and restricted to Prefix.
+type IteratorOptions struct {
+	Prefix string
+	Start  string
+}
+
+type Iterator interface {
+	Next() bool
+	Key() string
+	Value() []byte
+	Err() error
+	Close() error
+}
@@ -0,0 +1,66 @@
+package storage
+
+type ErrorKind uint8
+
+var (
+	ErrNotFound       = &StorageError{Kind: NotFound, Message: "key not found"}
+	ErrBackendFailure = &StorageError{Kind: BackendFailure, Message: "storage backend failure"}
+	ErrClosed         = &StorageError{Kind: Closed, Message: "storage is closed"}
+	ErrUnsupported    = &StorageError{Kind: Unsupported, Message: "operation not supported by storage driver"}
+	ErrConflict       = &StorageError{Kind: Conflict, Message: "transaction conflict"}
+)
+
+const (
+	NotFound ErrorKind = iota
+	BackendFailure
+	Closed
+	Unsupported
+	Conflict
+)
+
+type StorageError struct {
+	Kind    ErrorKind
+	Message string
+	cause   error
+}
+
+func (e *StorageError) Error() string {
+	message := e.Message
+	if message == "" {
+		switch e.Kind {
+		case NotFound:
+			message = "key not found"
+		case BackendFailure:
+			message = "storage backend failure"
+		case Closed:
+			message = "storage is closed"
+		case Unsupported:
+			message = "operation not supported by storage driver"
+		case Conflict:
+			message = "transaction conflict"
+		default:
+			message = "unknown storage error"
+		}
+	}
+	if e.cause != nil {
+		return message + ": " + e.cause.Error()
+	}
+	return message
+}
+
+func (e *StorageError) Is(target error) bool {
+	t, ok := target.(*StorageError)
+	return ok && e.Kind == t.Kind
+}
+
+func (e *StorageError) Unwrap() error {
+	return e.cause
+}
+
+func NewStorageError(kind ErrorKind, msg string) *StorageError {
+	return &StorageError{Kind: kind, Message: msg}
+}
+
+func NewStorageErrorWithCause(kind ErrorKind, msg string, cause error) *StorageError {
+	return &StorageError{Kind: kind, Message: msg, cause: cause}
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DriverOption describes a single setting understood by a driver.
+type DriverOption struct {
+	Name    string
+	Default string
+	Usage   string
+}
+
+// DriverOptions holds the settings passed to a driver constructor, keyed by
+// DriverOption.Name. Every declared option is present, using its default when
+// it was not set.
+type DriverOptions map[string]string
+
+type CacheDriver struct {
+	Name    string
+	Options []DriverOption
+	New     func(options DriverOptions) (ICache, error)
+}
+
+type StoreDriver struct {
+	Name    string
+	Options []DriverOption
+	New     func(options DriverOptions) (KVStore, error)
+}
+
+var (
+	registryMu   sync.RWMutex
+	cacheDrivers = make(map[string]CacheDriver)
+	storeDrivers = make(map[string]StoreDriver)
+)
+
+// RegisterCache makes a cache driver available by name. It is meant to be
+// called from the driver's init function and panics if the name is taken.
+func RegisterCache(driver CacheDriver) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if driver.Name == "" || driver.New == nil {
+		panic("storage: RegisterCache called with an incomplete driver")
+	}
+	if _, exists := cacheDrivers[driver.Name]; exists {
+		panic("storage: RegisterCache called twice for driver " + driver.Name)
+	}
+	cacheDrivers[driver.Name] = driver
+}
+
+// RegisterStore makes a store driver available by name. It is meant to be
+// called from the driver's init function and panics if the name is taken.
+func RegisterStore(driver StoreDriver) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if driver.Name == "" || driver.New == nil {
+		panic("storage: RegisterStore called with an incomplete driver")
+	}
+	if _, exists := storeDrivers[driver.Name]; exists {
+		panic("storage: RegisterStore called twice for driver " + driver.Name)
+	}
+	storeDrivers[driver.Name] = driver
+}
+
+// CacheDrivers returns the registered cache drivers sorted by name.
+func CacheDrivers() []CacheDriver {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	drivers := make([]CacheDriver, 0, len(cacheDrivers))
+	for _, driver := range cacheDrivers {
+		drivers = append(drivers, driver)
+	}
+	slices.SortFunc(drivers, func(a, b CacheDriver) int { return cmp.Compare(a.Name, b.Name) })
+	return drivers
+}
+
+// StoreDrivers returns the registered store drivers sorted by name.
+func StoreDrivers() []StoreDriver {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	drivers := make([]StoreDriver, 0, len(storeDrivers))
+	for _, driver := range storeDrivers {
+		drivers = append(drivers, driver)
+	}
+	slices.SortFunc(drivers, func(a, b StoreDriver) int { return cmp.Compare(a.Name, b.Name) })
+	return drivers
+}
+
+// LookupCacheDriver returns the cache driver registered under name.
+func LookupCacheDriver(name string) (CacheDriver, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	driver, ok := cacheDrivers[name]
+	return driver, ok
+}
+
+// LookupStoreDriver returns the store driver registered under name.
+func LookupStoreDriver(name string) (StoreDriver, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	driver, ok := storeDrivers[name]
+	return driver, ok
+}
+
+// OpenCache creates a cache using the driver registered under name.
+func OpenCache(name string, options map[string]string) (ICache, error) {
+	driver, ok := LookupCacheDriver(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown cache driver %q", name)
+	}
+
+	resolved, err := resolveOptions(name, driver.Options, options)
+	if err != nil {
+		return nil, err
+	}
+	return driver.New(resolved)
+}
+
+// OpenStore creates a store using the driver registered under name.
+func OpenStore(name string, options map[string]string) (KVStore, error) {
+	driver, ok := LookupStoreDriver(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown store driver %q", name)
+	}
+
+	resolved, err := resolveOptions(name, driver.Options, options)
+	if err != nil {
+		return nil, err
+	}
+	return driver.New(resolved)
+}
+
+func resolveOptions(driver string, declared []DriverOption, options map[string]string) (DriverOptions, error) {
+	resolved := make(DriverOptions, len(declared))
+	for _, option := range declared {
+		resolved[option.Name] = option.Default
+	}
+	for name, value := range options {
+		if _, ok := resolved[name]; !ok {
+			return nil, fmt.Errorf("driver %q has no option %q", driver, name)
+		}
+		resolved[name] = value
+	}
+	return resolved, nil
+}
+
+func (o DriverOptions) String(name string) string {
+	return o[name]
+}
+
+func (o DriverOptions) Int64(name string) (int64, error) {
+	value, err := strconv.ParseInt(o[name], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("option %q: %w", name, err)
+	}
+	return value, nil
+}
+
+func (o DriverOptions) Duration(name string) (time.Duration, error) {
+	value, err := time.ParseDuration(o[name])
+	if err != nil {
+		return 0, fmt.Errorf("option %q: %w", name, err)
+	}
+	return value, nil
+}
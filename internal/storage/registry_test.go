@@ -0,0 +1,48 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+)
+
+type testStore struct {
+	storage.KVStore
+	path string
+}
+
+func TestRegistry(t *testing.T) {
+	storage.RegisterStore(storage.StoreDriver{
+		Name:    "registry-test",
+		Options: []storage.DriverOption{{Name: "path", Default: "default.db"}},
+		New: func(options storage.DriverOptions) (storage.KVStore, error) {
+			return &testStore{path: options.String("path")}, nil
+		},
+	})
+
+	store, err := storage.OpenStore("registry-test", nil)
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	if path := store.(*testStore).path; path != "default.db" {
+		t.Errorf("Expected default path, got '%s'", path)
+	}
+
+	store, err = storage.OpenStore("registry-test", map[string]string{"path": "custom.db"})
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	if path := store.(*testStore).path; path != "custom.db" {
+		t.Errorf("Expected custom path, got '%s'", path)
+	}
+
+	if _, err := storage.OpenStore("registry-test", map[string]string{"size": "1"}); err == nil {
+		t.Error("Expected an error for an unknown option")
+	}
+	if _, err := storage.OpenStore("does-not-exist", nil); err == nil {
+		t.Error("Expected an error for an unknown driver")
+	}
+	if _, err := storage.OpenCache("registry-test", nil); err == nil {
+		t.Error("Expected store drivers not to be usable as caches")
+	}
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+)
+
+// SetMany writes every item in one call and returns the new version of each
+// item in order, like embedded.AmpKV.SetMany. TTLs are rounded up to whole
+// seconds.
+func (c *Client) SetMany(items []embedded.BatchItem) ([]uint64, error) {
+	return c.SetManyContext(context.Background(), items)
+}
+
+func (c *Client) SetManyContext(ctx context.Context, items []embedded.BatchItem) ([]uint64, error) {
+	puts := make([]*pb.PutOp, 0, len(items))
+	for _, item := range items {
+		kv, err := toKeyValue(item.Key, item.Value, item.Cost)
+		if err != nil {
+			return nil, err
+		}
+		puts = append(puts, &pb.PutOp{Kv: kv, TtlSeconds: ttlSeconds(max(item.TTL, 0))})
+	}
+
+	res, err := c.rpc.MultiSet(ctx, &pb.MultiSetRequest{Items: puts})
+	if err != nil {
+		return nil, err
+	}
+	return res.Versions, nil
+}
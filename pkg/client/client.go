@@ -0,0 +1,452 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"github.com/Unfield/AmpKV/pkg/common"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	apiKeyMetadataKey = "api-key"
+
+	DefaultTimeout         = 5 * time.Second
+	DefaultMaxRetries      = 3
+	DefaultRetryBackoff    = 100 * time.Millisecond
+	DefaultMaxRetryBackoff = 2 * time.Second
+)
+
+var (
+	_ common.Store = (*Client)(nil)
+	_ common.Store = (*embedded.AmpKV)(nil)
+)
+
+type ClientOptions struct {
+	// ApiKey is sent with every request as api-key metadata.
+	ApiKey string
+	// TLSConfig enables TLS. A nil config connects without transport security.
+	TLSConfig *tls.Config
+	// Timeout is applied to every call whose context has no deadline.
+	Timeout time.Duration
+	// MaxRetries is the number of times an idempotent call failing with
+	// Unavailable is retried. Negative values disable retries.
+	MaxRetries      int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// KeepaliveTime enables client keepalive pings at the given interval.
+	KeepaliveTime time.Duration
+	// DialOptions are appended to the options built by NewClient.
+	DialOptions []grpc.DialOption
+}
+
+// Client is a remote AmpKV. It offers the same basic API as embedded.AmpKV so
+// both can be used through common.Store.
+type Client struct {
+	conn    *grpc.ClientConn
+	rpc     pb.AmpKVServiceClient
+	admin   pb.AdminServiceClient
+	options ClientOptions
+}
+
+func NewClient(target string, options ClientOptions) (*Client, error) {
+	if target == "" {
+		return nil, fmt.Errorf("Failed to create AmpKV client: target must not be empty")
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.MaxRetries == 0 {
+		options.MaxRetries = DefaultMaxRetries
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = DefaultRetryBackoff
+	}
+	if options.MaxRetryBackoff <= 0 {
+		options.MaxRetryBackoff = DefaultMaxRetryBackoff
+	}
+
+	transportCreds := insecure.NewCredentials()
+	if options.TLSConfig != nil {
+		transportCreds = credentials.NewTLS(options.TLSConfig)
+	}
+
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(transportCreds),
+		grpc.WithChainUnaryInterceptor(errorUnaryInterceptor, retryUnaryInterceptor(options)),
+	}
+	if options.ApiKey != "" {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(apiKeyCredentials{
+			apiKey: options.ApiKey,
+			secure: options.TLSConfig != nil,
+		}))
+	}
+	if options.KeepaliveTime > 0 {
+		dialOptions = append(dialOptions, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                options.KeepaliveTime,
+			PermitWithoutStream: true,
+		}))
+	}
+	dialOptions = append(dialOptions, options.DialOptions...)
+
+	conn, err := grpc.NewClient(target, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create AmpKV client: %w", err)
+	}
+
+	return &Client{
+		conn:    conn,
+		rpc:     pb.NewAmpKVServiceClient(conn),
+		admin:   pb.NewAdminServiceClient(conn),
+		options: options,
+	}, nil
+}
+
+// RPC exposes the generated client for calls the Client does not wrap. The
+// api key, retries and deadlines configured for the Client still apply.
+func (c *Client) RPC() pb.AmpKVServiceClient {
+	return c.rpc
+}
+
+// Admin returns the api key management service. It requires an api key with
+// the admin permission.
+func (c *Client) Admin() pb.AdminServiceClient {
+	return c.admin
+}
+
+func (c *Client) Get(key string) (*common.AmpKVValue, error) {
+	return c.GetContext(context.Background(), key)
+}
+
+func (c *Client) GetContext(ctx context.Context, key string) (*common.AmpKVValue, error) {
+	res, err := c.rpc.Get(ctx, &pb.GetRequest{Key: key})
+	if err != nil {
+		return nil, err
+	}
+	if !res.Found || res.Kv == nil {
+		return nil, embedded.ErrNotFound
+	}
+	return fromGetResponse(res), nil
+}
+
+func (c *Client) Set(key string, value any, cost int64) error {
+	return c.SetContext(context.Background(), key, value, cost)
+}
+
+func (c *Client) SetContext(ctx context.Context, key string, value any, cost int64) error {
+	kv, err := toKeyValue(key, value, cost)
+	if err != nil {
+		return err
+	}
+	_, err = c.rpc.Set(ctx, &pb.SetRequest{Kv: kv})
+	return err
+}
+
+// SetWithTTL sets key to expire after ttl, rounded up to whole seconds. A ttl
+// of zero or less stores key without expiry like the embedded store.
+func (c *Client) SetWithTTL(key string, value any, cost int64, ttl time.Duration) error {
+	return c.SetWithTTLContext(context.Background(), key, value, cost, ttl)
+}
+
+func (c *Client) SetWithTTLContext(ctx context.Context, key string, value any, cost int64, ttl time.Duration) error {
+	if ttl <= 0 {
+		return c.SetContext(ctx, key, value, cost)
+	}
+	kv, err := toKeyValue(key, value, cost)
+	if err != nil {
+		return err
+	}
+	_, err = c.rpc.SetWithTTL(ctx, &pb.SetWithTTLRequest{Kv: kv, TtlSeconds: ttlSeconds(ttl)})
+	return err
+}
+
+func (c *Client) Delete(key string) error {
+	return c.DeleteContext(context.Background(), key)
+}
+
+func (c *Client) DeleteContext(ctx context.Context, key string) error {
+	_, err := c.rpc.Delete(ctx, &pb.DeleteRequest{Key: key})
+	return err
+}
+
+// TTL returns how long key has left to live, or 0 if it does not expire.
+func (c *Client) TTL(key string) (time.Duration, error) {
+	return c.TTLContext(context.Background(), key)
+}
+
+func (c *Client) TTLContext(ctx context.Context, key string) (time.Duration, error) {
+	res, err := c.rpc.TTL(ctx, &pb.TTLRequest{Key: key})
+	if err != nil {
+		return 0, err
+	}
+	if !res.Found {
+		return 0, embedded.ErrNotFound
+	}
+	return time.Duration(res.TtlMs) * time.Millisecond, nil
+}
+
+// Expire sets the TTL of an existing key, rounded up to whole seconds. A ttl
+// of zero or less deletes the key.
+func (c *Client) Expire(key string, ttl time.Duration) error {
+	return c.ExpireContext(context.Background(), key, ttl)
+}
+
+func (c *Client) ExpireContext(ctx context.Context, key string, ttl time.Duration) error {
+	_, err := c.rpc.Expire(ctx, &pb.ExpireRequest{Key: key, Expiry: &pb.ExpireRequest_TtlSeconds{TtlSeconds: ttlSeconds(ttl)}})
+	return err
+}
+
+// ExpireAt lets an existing key expire at t. A t in the past deletes the key.
+func (c *Client) ExpireAt(key string, t time.Time) error {
+	return c.ExpireAtContext(context.Background(), key, t)
+}
+
+func (c *Client) ExpireAtContext(ctx context.Context, key string, t time.Time) error {
+	_, err := c.rpc.Expire(ctx, &pb.ExpireRequest{Key: key, Expiry: &pb.ExpireRequest_ExpiresAt{ExpiresAt: t.UnixMilli()}})
+	return err
+}
+
+// Persist removes the TTL of an existing key.
+func (c *Client) Persist(key string) error {
+	return c.PersistContext(context.Background(), key)
+}
+
+func (c *Client) PersistContext(ctx context.Context, key string) error {
+	_, err := c.rpc.Persist(ctx, &pb.PersistRequest{Key: key})
+	return err
+}
+
+// IncrBy atomically adds delta to the TypeInt value of key and returns the
+// result. A missing key is created with a value of delta.
+func (c *Client) IncrBy(key string, delta int64) (int64, error) {
+	return c.IncrByContext(context.Background(), key, delta)
+}
+
+func (c *Client) IncrByContext(ctx context.Context, key string, delta int64) (int64, error) {
+	res, err := c.rpc.Increment(ctx, &pb.IncrementRequest{Key: key, Delta: &pb.IncrementRequest_IntDelta{IntDelta: delta}})
+	if err != nil {
+		return 0, err
+	}
+	return res.GetIntValue(), nil
+}
+
+// IncrByFloat is IncrBy for TypeFloat values.
+func (c *Client) IncrByFloat(key string, delta float64) (float64, error) {
+	return c.IncrByFloatContext(context.Background(), key, delta)
+}
+
+func (c *Client) IncrByFloatContext(ctx context.Context, key string, delta float64) (float64, error) {
+	res, err := c.rpc.Increment(ctx, &pb.IncrementRequest{Key: key, Delta: &pb.IncrementRequest_FloatDelta{FloatDelta: delta}})
+	if err != nil {
+		return 0, err
+	}
+	return res.GetFloatValue(), nil
+}
+
+// State reports the current state of the underlying connection.
+func (c *Client) State() connectivity.State {
+	return c.conn.GetState()
+}
+
+// WaitForReady connects and blocks until the connection is ready, ctx is done
+// or the client is closed.
+func (c *Client) WaitForReady(ctx context.Context) error {
+	c.conn.Connect()
+	for {
+		state := c.conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Shutdown:
+			return embedded.ErrClosed
+		}
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// ttlSeconds converts ttl to the whole seconds the protocol uses, rounding
+// positive durations up so they never become zero.
+func ttlSeconds(ttl time.Duration) int64 {
+	seconds := int64(ttl / time.Second)
+	if ttl > 0 && ttl%time.Second != 0 {
+		seconds++
+	}
+	return seconds
+}
+
+func toKeyValue(key string, value any, cost int64) (*pb.KeyValue, error) {
+	ampKVValue, err := common.NewAmpKVValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to encode value for key '%s': %w", key, err)
+	}
+	return &pb.KeyValue{
+		Key:   key,
+		Value: ampKVValue.Data,
+		Type:  pb.AmpKVDataTypeProto(ampKVValue.Type),
+		Cost:  cost,
+	}, nil
+}
+
+func fromKeyValue(kv *pb.KeyValue) *common.AmpKVValue {
+	return &common.AmpKVValue{
+		Type:    common.AmpKVDataType(kv.Type),
+		Data:    kv.Value,
+		Version: kv.Version,
+	}
+}
+
+func fromGetResponse(res *pb.GetResponse) *common.AmpKVValue {
+	val := fromKeyValue(res.Kv)
+	if res.CreatedAt != 0 {
+		val.CreatedAt = time.UnixMilli(res.CreatedAt)
+	}
+	if res.UpdatedAt != 0 {
+		val.UpdatedAt = time.UnixMilli(res.UpdatedAt)
+	}
+	if res.ExpiresAt != 0 {
+		expiresAt := time.UnixMilli(res.ExpiresAt)
+		val.ExpiresAt = &expiresAt
+	}
+	return val
+}
+
+type apiKeyCredentials struct {
+	apiKey string
+	secure bool
+}
+
+func (c apiKeyCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{apiKeyMetadataKey: c.apiKey}, nil
+}
+
+func (c apiKeyCredentials) RequireTransportSecurity() bool {
+	return c.secure
+}
+
+// idempotentMethods are the calls that leave the store in the same state
+// when they are applied twice. A call failing with Unavailable may still have
+// been applied, so only these are retried.
+var idempotentMethods = map[string]bool{
+	pb.AmpKVService_Get_FullMethodName:                   true,
+	pb.AmpKVService_MultiGet_FullMethodName:              true,
+	pb.AmpKVService_TTL_FullMethodName:                   true,
+	pb.AmpKVService_ListRange_FullMethodName:             true,
+	pb.AmpKVService_SetMembers_FullMethodName:            true,
+	pb.AmpKVService_HashGet_FullMethodName:               true,
+	pb.AmpKVService_HashGetAll_FullMethodName:            true,
+	pb.AmpKVService_SortedSetRangeByScore_FullMethodName: true,
+	pb.AmpKVService_Set_FullMethodName:                   true,
+	pb.AmpKVService_SetWithTTL_FullMethodName:            true,
+	pb.AmpKVService_MultiSet_FullMethodName:              true,
+	pb.AmpKVService_Delete_FullMethodName:                true,
+	pb.AmpKVService_MultiDelete_FullMethodName:           true,
+}
+
+// retryUnaryInterceptor applies the default deadline and retries idempotent
+// calls that fail with Unavailable using exponential backoff with jitter.
+func retryUnaryInterceptor(options ClientOptions) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+			defer cancel()
+		}
+		if !idempotentMethods[method] {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		backoff := options.RetryBackoff
+		for attempt := 0; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if status.Code(err) != codes.Unavailable || attempt >= options.MaxRetries {
+				return err
+			}
+
+			wait := backoff/2 + rand.N(backoff/2+1)
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(wait):
+			}
+			backoff = min(backoff*2, options.MaxRetryBackoff)
+		}
+	}
+}
+
+// errorUnaryInterceptor maps the status errors of failed calls to the
+// embedded errors for the same failure, so callers can use errors.Is with
+// either store.
+func errorUnaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return fromStatusError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// storeError is a gRPC status error that also matches an embedded error.
+type storeError struct {
+	status *status.Status
+	target error
+}
+
+func (e *storeError) Error() string {
+	return e.status.Err().Error()
+}
+
+func (e *storeError) GRPCStatus() *status.Status {
+	return e.status
+}
+
+func (e *storeError) Unwrap() error {
+	return e.target
+}
+
+func fromStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	var target error
+	switch st.Code() {
+	case codes.NotFound:
+		target = embedded.ErrNotFound
+	case codes.Unavailable:
+		target = embedded.ErrClosed
+	case codes.FailedPrecondition:
+		target = embedded.ErrVersionMismatch
+		for _, detail := range st.Details() {
+			info, ok := detail.(*errdetails.ErrorInfo)
+			if !ok || info.Domain != pb.ErrorDomain {
+				continue
+			}
+			switch info.Reason {
+			case pb.ErrorReasonTypeMismatch:
+				target = embedded.ErrTypeMismatch
+			case pb.ErrorReasonUnsupported:
+				target = embedded.ErrUnsupported
+			}
+		}
+	default:
+		return err
+	}
+	return &storeError{status: st, target: target}
+}
@@ -0,0 +1,276 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Unfield/AmpKV/drivers/cache/ristretto"
+	"github.com/Unfield/AmpKV/internal/auth"
+	"github.com/Unfield/AmpKV/internal/server"
+	"github.com/Unfield/AmpKV/pkg/client"
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"github.com/Unfield/AmpKV/pkg/common"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startTestServer serves srv over an in-memory listener and returns client
+// options that dial it.
+func startTestServer(t *testing.T, srv pb.AmpKVServiceServer, opts ...grpc.ServerOption) client.ClientOptions {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(opts...)
+	pb.RegisterAmpKVServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	return client.ClientOptions{
+		DialOptions: []grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			}),
+		},
+	}
+}
+
+func newTestClient(t *testing.T, options client.ClientOptions) *client.Client {
+	c, err := client.NewClient("passthrough:///bufnet", options)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func setupTestServer(t *testing.T) (client.ClientOptions, string) {
+	cache, err := ristretto.NewRistrettoCache(1e4, 1<<20, 64)
+	if err != nil {
+		t.Fatalf("Failed to initialize Cache: %v", err)
+	}
+	ampkv, err := embedded.NewAmpKV(cache, nil, embedded.AmpKVOptions{Mode: embedded.AmpKVStorageModeCacheOnly})
+	if err != nil {
+		t.Fatalf("Failed to initialize AmpKV: %v", err)
+	}
+	t.Cleanup(func() { ampkv.Close() })
+
+	manager, err := auth.NewApiKeyManager(ampkv, []byte("client-test-secret-0123456789"))
+	if err != nil {
+		t.Fatalf("Failed to initialize api key manager: %v", err)
+	}
+	apiKey, err := manager.CreateAPIKey("client-test", []auth.Permission{auth.PermAdmin}, false, nil)
+	if err != nil {
+		t.Fatalf("Failed to create api key: %v", err)
+	}
+
+	options := startTestServer(t, server.NewAmpKVGrpcServer(ampkv), grpc.UnaryInterceptor(server.AuthUnaryServerInterceptor(manager, nil)))
+	return options, apiKey.Key
+}
+
+func TestClientOperations(t *testing.T) {
+	options, apiKey := setupTestServer(t)
+	options.ApiKey = apiKey
+	c := newTestClient(t, options)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := c.WaitForReady(ctx); err != nil {
+		t.Fatalf("WaitForReady failed: %v", err)
+	}
+
+	var store common.Store = c
+
+	if err := store.Set("greeting", "hello", 1); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	val, err := store.Get("greeting")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if val.Type != common.TypeString || string(val.Data) != "hello" || val.Version == 0 {
+		t.Errorf("Expected typed string 'hello' with a version, got %+v", val)
+	}
+
+	if err := store.SetWithTTL("counter", 42, 1, time.Minute); err != nil {
+		t.Fatalf("SetWithTTL failed: %v", err)
+	}
+	val, err = store.Get("counter")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if val.Type != common.TypeInt {
+		t.Errorf("Expected TypeInt, got %s", val.Type)
+	}
+	if ttl, err := c.TTL("counter"); err != nil || ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Expected a TTL of up to a minute, got %v (%v)", ttl, err)
+	}
+	if err := c.Persist("counter"); err != nil {
+		t.Fatalf("Persist failed: %v", err)
+	}
+	if ttl, err := c.TTL("counter"); err != nil || ttl != 0 {
+		t.Errorf("Expected the TTL to be removed, got %v (%v)", ttl, err)
+	}
+	if err := c.Expire("missing", time.Minute); status.Code(err) != codes.NotFound || !errors.Is(err, embedded.ErrNotFound) {
+		t.Errorf("Expected NotFound from Expire, got %v", err)
+	}
+
+	// Like the embedded store, a ttl of zero or less means no expiry and
+	// shorter ones are not truncated to zero.
+	if err := store.SetWithTTL("forever", "value", 1, 0); err != nil {
+		t.Errorf("SetWithTTL without a ttl failed: %v", err)
+	}
+	if ttl, err := c.TTL("forever"); err != nil || ttl != 0 {
+		t.Errorf("Expected no TTL, got %v (%v)", ttl, err)
+	}
+	if err := store.SetWithTTL("brief", "value", 1, 300*time.Millisecond); err != nil {
+		t.Errorf("SetWithTTL with a sub-second ttl failed: %v", err)
+	}
+	if ttl, err := c.TTL("brief"); err != nil || ttl <= 0 || ttl > time.Second {
+		t.Errorf("Expected a TTL of up to a second, got %v (%v)", ttl, err)
+	}
+
+	versions, err := c.SetMany([]embedded.BatchItem{{Key: "first", Value: "a"}, {Key: "second", Value: "b", TTL: time.Minute}})
+	if err != nil || len(versions) != 2 || versions[1] <= versions[0] {
+		t.Fatalf("Expected two increasing versions from SetMany, got %v (%v)", versions, err)
+	}
+	if val, err := store.Get("second"); err != nil || val.Version != versions[1] {
+		t.Errorf("Expected version %d, got %+v (%v)", versions[1], val, err)
+	}
+
+	if value, err := c.IncrBy("counter", 8); err != nil || value != 50 {
+		t.Errorf("Expected 50, got %d (%v)", value, err)
+	}
+	if _, err := c.IncrByFloat("counter", 1); status.Code(err) != codes.FailedPrecondition || !errors.Is(err, embedded.ErrTypeMismatch) {
+		t.Errorf("Expected FailedPrecondition for a type mismatch, got %v", err)
+	}
+	// The test server runs without a store, which collections require.
+	if _, err := c.RPush("queue", "job"); status.Code(err) != codes.FailedPrecondition || !errors.Is(err, embedded.ErrUnsupported) {
+		t.Errorf("Expected FailedPrecondition without a store, got %v", err)
+	}
+
+	if err := store.Delete("greeting"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Get("greeting"); !errors.Is(err, embedded.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestClientMissingApiKey(t *testing.T) {
+	options, _ := setupTestServer(t)
+	c := newTestClient(t, options)
+
+	_, err := c.Get("greeting")
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated, got %v", err)
+	}
+}
+
+type flakyServer struct {
+	pb.UnimplementedAmpKVServiceServer
+	failures atomic.Int32
+	calls    atomic.Int32
+}
+
+func (s *flakyServer) Get(ctx context.Context, req *pb.GetRequest) (*pb.GetResponse, error) {
+	if s.calls.Add(1) <= s.failures.Load() {
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+	return &pb.GetResponse{Found: true, Kv: &pb.KeyValue{Key: req.Key, Value: []byte("ok"), Type: pb.AmpKVDataTypeProto_AMP_KV_DATA_TYPE_STRING}}, nil
+}
+
+func (s *flakyServer) Increment(ctx context.Context, req *pb.IncrementRequest) (*pb.IncrementResponse, error) {
+	s.calls.Add(1)
+	return nil, status.Error(codes.Unavailable, "try again")
+}
+
+func TestClientRetries(t *testing.T) {
+	t.Run("Retries Unavailable", func(t *testing.T) {
+		srv := &flakyServer{}
+		srv.failures.Store(2)
+		options := startTestServer(t, srv)
+		options.RetryBackoff = time.Millisecond
+		c := newTestClient(t, options)
+
+		val, err := c.Get("key")
+		if err != nil {
+			t.Fatalf("Expected Get to succeed after retries, got %v", err)
+		}
+		if string(val.Data) != "ok" || srv.calls.Load() != 3 {
+			t.Errorf("Expected 3 calls ending in 'ok', got %d calls and %q", srv.calls.Load(), val.Data)
+		}
+	})
+
+	t.Run("Gives up after MaxRetries", func(t *testing.T) {
+		srv := &flakyServer{}
+		srv.failures.Store(10)
+		options := startTestServer(t, srv)
+		options.RetryBackoff = time.Millisecond
+		options.MaxRetries = 2
+		c := newTestClient(t, options)
+
+		_, err := c.Get("key")
+		if status.Code(err) != codes.Unavailable || !errors.Is(err, embedded.ErrClosed) {
+			t.Errorf("Expected Unavailable, got %v", err)
+		}
+		if srv.calls.Load() != 3 {
+			t.Errorf("Expected 3 calls, got %d", srv.calls.Load())
+		}
+	})
+
+	t.Run("Does not retry Increment", func(t *testing.T) {
+		srv := &flakyServer{}
+		options := startTestServer(t, srv)
+		options.RetryBackoff = time.Millisecond
+		c := newTestClient(t, options)
+
+		// The increment may have been applied before the connection failed.
+		if _, err := c.IncrBy("counter", 1); status.Code(err) != codes.Unavailable {
+			t.Errorf("Expected Unavailable, got %v", err)
+		}
+		if srv.calls.Load() != 1 {
+			t.Errorf("Expected a single call, got %d", srv.calls.Load())
+		}
+	})
+}
+
+type failingServer struct {
+	pb.UnimplementedAmpKVServiceServer
+}
+
+func (failingServer) Get(ctx context.Context, req *pb.GetRequest) (*pb.GetResponse, error) {
+	code, _ := strconv.Atoi(req.Key)
+	return nil, status.Error(codes.Code(code), "failed")
+}
+
+func TestClientErrors(t *testing.T) {
+	options := startTestServer(t, failingServer{})
+	options.MaxRetries = -1
+	c := newTestClient(t, options)
+
+	for _, tc := range []struct {
+		code   codes.Code
+		target error
+	}{
+		{codes.NotFound, embedded.ErrNotFound},
+		{codes.Unavailable, embedded.ErrClosed},
+		{codes.FailedPrecondition, embedded.ErrVersionMismatch},
+	} {
+		t.Run(tc.code.String(), func(t *testing.T) {
+			_, err := c.Get(strconv.Itoa(int(tc.code)))
+			if !errors.Is(err, tc.target) || status.Code(err) != tc.code {
+				t.Errorf("Expected %v with code %v, got %v", tc.target, tc.code, err)
+			}
+		})
+	}
+
+	if _, err := c.Get(strconv.Itoa(int(codes.Internal))); errors.Is(err, embedded.ErrNotFound) || status.Code(err) != codes.Internal {
+		t.Errorf("Expected other codes to pass through, got %v", err)
+	}
+}
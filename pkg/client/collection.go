@@ -0,0 +1,270 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"github.com/Unfield/AmpKV/pkg/common"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+)
+
+// LPush prepends values to the list stored under key and returns its new
+// length. The last value ends up first.
+func (c *Client) LPush(key string, values ...any) (int64, error) {
+	return c.LPushContext(context.Background(), key, values...)
+}
+
+func (c *Client) LPushContext(ctx context.Context, key string, values ...any) (int64, error) {
+	return c.push(ctx, key, true, values)
+}
+
+// RPush appends values to the list stored under key and returns its new
+// length.
+func (c *Client) RPush(key string, values ...any) (int64, error) {
+	return c.RPushContext(context.Background(), key, values...)
+}
+
+func (c *Client) RPushContext(ctx context.Context, key string, values ...any) (int64, error) {
+	return c.push(ctx, key, false, values)
+}
+
+func (c *Client) push(ctx context.Context, key string, left bool, values []any) (int64, error) {
+	typed := make([]*pb.TypedValue, 0, len(values))
+	for _, value := range values {
+		typedValue, err := toTypedValue(key, value)
+		if err != nil {
+			return 0, err
+		}
+		typed = append(typed, typedValue)
+	}
+
+	res, err := c.rpc.ListPush(ctx, &pb.ListPushRequest{Key: key, Values: typed, Left: left})
+	if err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+// LPop removes and returns the first value of the list stored under key, or
+// ErrNotFound if it is empty.
+func (c *Client) LPop(key string) (*common.AmpKVValue, error) {
+	return c.LPopContext(context.Background(), key)
+}
+
+func (c *Client) LPopContext(ctx context.Context, key string) (*common.AmpKVValue, error) {
+	return c.pop(ctx, key, true)
+}
+
+// RPop removes and returns the last value of the list stored under key.
+func (c *Client) RPop(key string) (*common.AmpKVValue, error) {
+	return c.RPopContext(context.Background(), key)
+}
+
+func (c *Client) RPopContext(ctx context.Context, key string) (*common.AmpKVValue, error) {
+	return c.pop(ctx, key, false)
+}
+
+func (c *Client) pop(ctx context.Context, key string, left bool) (*common.AmpKVValue, error) {
+	res, err := c.rpc.ListPop(ctx, &pb.ListPopRequest{Key: key, Left: left})
+	if err != nil {
+		return nil, err
+	}
+	if !res.Found || res.Value == nil {
+		return nil, embedded.ErrNotFound
+	}
+	return fromTypedValue(res.Value), nil
+}
+
+// LRange returns the values of the list stored under key from start to stop,
+// both inclusive. Negative positions count from the end of the list.
+func (c *Client) LRange(key string, start, stop int64) ([]*common.AmpKVValue, error) {
+	return c.LRangeContext(context.Background(), key, start, stop)
+}
+
+func (c *Client) LRangeContext(ctx context.Context, key string, start, stop int64) ([]*common.AmpKVValue, error) {
+	res, err := c.rpc.ListRange(ctx, &pb.ListRangeRequest{Key: key, Start: start, Stop: stop})
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]*common.AmpKVValue, 0, len(res.Values))
+	for _, value := range res.Values {
+		values = append(values, fromTypedValue(value))
+	}
+	return values, nil
+}
+
+// SAdd adds members to the set stored under key and returns how many of them
+// were not members yet.
+func (c *Client) SAdd(key string, members ...string) (int, error) {
+	return c.SAddContext(context.Background(), key, members...)
+}
+
+func (c *Client) SAddContext(ctx context.Context, key string, members ...string) (int, error) {
+	res, err := c.rpc.SetAdd(ctx, &pb.SetAddRequest{Key: key, Members: members})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.Count), nil
+}
+
+// SRem removes members from the set stored under key and returns how many of
+// them were members.
+func (c *Client) SRem(key string, members ...string) (int, error) {
+	return c.SRemContext(context.Background(), key, members...)
+}
+
+func (c *Client) SRemContext(ctx context.Context, key string, members ...string) (int, error) {
+	res, err := c.rpc.SetRemove(ctx, &pb.SetRemoveRequest{Key: key, Members: members})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.Count), nil
+}
+
+// SMembers returns the members of the set stored under key in byte order.
+func (c *Client) SMembers(key string) ([]string, error) {
+	return c.SMembersContext(context.Background(), key)
+}
+
+func (c *Client) SMembersContext(ctx context.Context, key string) ([]string, error) {
+	res, err := c.rpc.SetMembers(ctx, &pb.SetMembersRequest{Key: key})
+	if err != nil {
+		return nil, err
+	}
+	return res.Members, nil
+}
+
+// HSet sets field of the hash stored under key and reports whether the field
+// is new.
+func (c *Client) HSet(key, field string, value any) (bool, error) {
+	return c.HSetContext(context.Background(), key, field, value)
+}
+
+func (c *Client) HSetContext(ctx context.Context, key, field string, value any) (bool, error) {
+	typedValue, err := toTypedValue(key, value)
+	if err != nil {
+		return false, err
+	}
+	res, err := c.rpc.HashSet(ctx, &pb.HashSetRequest{Key: key, Field: field, Value: typedValue})
+	if err != nil {
+		return false, err
+	}
+	return res.Created, nil
+}
+
+// HGet returns field of the hash stored under key, or ErrNotFound if either
+// does not exist.
+func (c *Client) HGet(key, field string) (*common.AmpKVValue, error) {
+	return c.HGetContext(context.Background(), key, field)
+}
+
+func (c *Client) HGetContext(ctx context.Context, key, field string) (*common.AmpKVValue, error) {
+	res, err := c.rpc.HashGet(ctx, &pb.HashGetRequest{Key: key, Field: field})
+	if err != nil {
+		return nil, err
+	}
+	if !res.Found || res.Value == nil {
+		return nil, embedded.ErrNotFound
+	}
+	return fromTypedValue(res.Value), nil
+}
+
+// HDel removes fields from the hash stored under key and returns how many of
+// them existed.
+func (c *Client) HDel(key string, fields ...string) (int, error) {
+	return c.HDelContext(context.Background(), key, fields...)
+}
+
+func (c *Client) HDelContext(ctx context.Context, key string, fields ...string) (int, error) {
+	res, err := c.rpc.HashDelete(ctx, &pb.HashDeleteRequest{Key: key, Fields: fields})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.Count), nil
+}
+
+// HGetAll returns every field of the hash stored under key.
+func (c *Client) HGetAll(key string) (map[string]*common.AmpKVValue, error) {
+	return c.HGetAllContext(context.Background(), key)
+}
+
+func (c *Client) HGetAllContext(ctx context.Context, key string) (map[string]*common.AmpKVValue, error) {
+	res, err := c.rpc.HashGetAll(ctx, &pb.HashGetAllRequest{Key: key})
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]*common.AmpKVValue, len(res.Fields))
+	for field, value := range res.Fields {
+		fields[field] = fromTypedValue(value)
+	}
+	return fields, nil
+}
+
+// ZAdd adds members to the sorted set stored under key, or updates their
+// score, and returns how many were added.
+func (c *Client) ZAdd(key string, members ...embedded.ZMember) (int, error) {
+	return c.ZAddContext(context.Background(), key, members...)
+}
+
+func (c *Client) ZAddContext(ctx context.Context, key string, members ...embedded.ZMember) (int, error) {
+	scored := make([]*pb.ScoredMember, 0, len(members))
+	for _, member := range members {
+		scored = append(scored, &pb.ScoredMember{Member: member.Member, Score: member.Score})
+	}
+
+	res, err := c.rpc.SortedSetAdd(ctx, &pb.SortedSetAddRequest{Key: key, Members: scored})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.Count), nil
+}
+
+// ZRem removes members from the sorted set stored under key and returns how
+// many of them were in it.
+func (c *Client) ZRem(key string, members ...string) (int, error) {
+	return c.ZRemContext(context.Background(), key, members...)
+}
+
+func (c *Client) ZRemContext(ctx context.Context, key string, members ...string) (int, error) {
+	res, err := c.rpc.SortedSetRemove(ctx, &pb.SortedSetRemoveRequest{Key: key, Members: members})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.Count), nil
+}
+
+// ZRangeByScore returns up to limit members of the sorted set stored under
+// key with a score between minScore and maxScore, both inclusive. A limit of
+// zero or less returns every such member.
+func (c *Client) ZRangeByScore(key string, minScore, maxScore float64, limit int) ([]embedded.ZMember, error) {
+	return c.ZRangeByScoreContext(context.Background(), key, minScore, maxScore, limit)
+}
+
+func (c *Client) ZRangeByScoreContext(ctx context.Context, key string, minScore, maxScore float64, limit int) ([]embedded.ZMember, error) {
+	res, err := c.rpc.SortedSetRangeByScore(ctx, &pb.SortedSetRangeRequest{Key: key, Min: minScore, Max: maxScore, Limit: int32(min(max(limit, 0), math.MaxInt32))})
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]embedded.ZMember, 0, len(res.Members))
+	for _, member := range res.Members {
+		members = append(members, embedded.ZMember{Member: member.Member, Score: member.Score})
+	}
+	return members, nil
+}
+
+func toTypedValue(key string, value any) (*pb.TypedValue, error) {
+	ampKVValue, err := common.NewAmpKVValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to encode value for key '%s': %w", key, err)
+	}
+	return &pb.TypedValue{Value: ampKVValue.Data, Type: pb.AmpKVDataTypeProto(ampKVValue.Type)}, nil
+}
+
+func fromTypedValue(value *pb.TypedValue) *common.AmpKVValue {
+	return &common.AmpKVValue{Type: common.AmpKVDataType(value.Type), Data: value.Value}
+}
@@ -24,34 +24,46 @@ const (
 type AmpKVDataTypeProto int32
 
 const (
-	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_UNKNOWN AmpKVDataTypeProto = 0
-	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_STRING  AmpKVDataTypeProto = 1
-	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_INT     AmpKVDataTypeProto = 2
-	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_FLOAT   AmpKVDataTypeProto = 3
-	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_BOOL    AmpKVDataTypeProto = 4
-	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_JSON    AmpKVDataTypeProto = 5
-	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_BINARY  AmpKVDataTypeProto = 6
+	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_UNKNOWN    AmpKVDataTypeProto = 0
+	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_STRING     AmpKVDataTypeProto = 1
+	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_INT        AmpKVDataTypeProto = 2
+	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_FLOAT      AmpKVDataTypeProto = 3
+	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_BOOL       AmpKVDataTypeProto = 4
+	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_JSON       AmpKVDataTypeProto = 5
+	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_BINARY     AmpKVDataTypeProto = 6
+	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_LIST       AmpKVDataTypeProto = 7
+	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_SET        AmpKVDataTypeProto = 8
+	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_HASH       AmpKVDataTypeProto = 9
+	AmpKVDataTypeProto_AMP_KV_DATA_TYPE_SORTED_SET AmpKVDataTypeProto = 10
 )
 
 // Enum value maps for AmpKVDataTypeProto.
 var (
 	AmpKVDataTypeProto_name = map[int32]string{
-		0: "AMP_KV_DATA_TYPE_UNKNOWN",
-		1: "AMP_KV_DATA_TYPE_STRING",
-		2: "AMP_KV_DATA_TYPE_INT",
-		3: "AMP_KV_DATA_TYPE_FLOAT",
-		4: "AMP_KV_DATA_TYPE_BOOL",
-		5: "AMP_KV_DATA_TYPE_JSON",
-		6: "AMP_KV_DATA_TYPE_BINARY",
+		0:  "AMP_KV_DATA_TYPE_UNKNOWN",
+		1:  "AMP_KV_DATA_TYPE_STRING",
+		2:  "AMP_KV_DATA_TYPE_INT",
+		3:  "AMP_KV_DATA_TYPE_FLOAT",
+		4:  "AMP_KV_DATA_TYPE_BOOL",
+		5:  "AMP_KV_DATA_TYPE_JSON",
+		6:  "AMP_KV_DATA_TYPE_BINARY",
+		7:  "AMP_KV_DATA_TYPE_LIST",
+		8:  "AMP_KV_DATA_TYPE_SET",
+		9:  "AMP_KV_DATA_TYPE_HASH",
+		10: "AMP_KV_DATA_TYPE_SORTED_SET",
 	}
 	AmpKVDataTypeProto_value = map[string]int32{
-		"AMP_KV_DATA_TYPE_UNKNOWN": 0,
-		"AMP_KV_DATA_TYPE_STRING":  1,
-		"AMP_KV_DATA_TYPE_INT":     2,
-		"AMP_KV_DATA_TYPE_FLOAT":   3,
-		"AMP_KV_DATA_TYPE_BOOL":    4,
-		"AMP_KV_DATA_TYPE_JSON":    5,
-		"AMP_KV_DATA_TYPE_BINARY":  6,
+		"AMP_KV_DATA_TYPE_UNKNOWN":    0,
+		"AMP_KV_DATA_TYPE_STRING":     1,
+		"AMP_KV_DATA_TYPE_INT":        2,
+		"AMP_KV_DATA_TYPE_FLOAT":      3,
+		"AMP_KV_DATA_TYPE_BOOL":       4,
+		"AMP_KV_DATA_TYPE_JSON":       5,
+		"AMP_KV_DATA_TYPE_BINARY":     6,
+		"AMP_KV_DATA_TYPE_LIST":       7,
+		"AMP_KV_DATA_TYPE_SET":        8,
+		"AMP_KV_DATA_TYPE_HASH":       9,
+		"AMP_KV_DATA_TYPE_SORTED_SET": 10,
 	}
 )
 
@@ -82,12 +94,170 @@ func (AmpKVDataTypeProto) EnumDescriptor() ([]byte, []int) {
 	return file_ampkv_proto_rawDescGZIP(), []int{0}
 }
 
+type CompareTarget int32
+
+const (
+	// EXISTS checks for the presence of the key; EQUAL holds if it exists,
+	// NOT_EQUAL if it does not. The compare value is ignored.
+	CompareTarget_COMPARE_TARGET_EXISTS CompareTarget = 0
+	CompareTarget_COMPARE_TARGET_VALUE  CompareTarget = 1
+	// VERSION compares against the key's current version, which is 0 for
+	// keys that do not exist.
+	CompareTarget_COMPARE_TARGET_VERSION CompareTarget = 2
+)
+
+// Enum value maps for CompareTarget.
+var (
+	CompareTarget_name = map[int32]string{
+		0: "COMPARE_TARGET_EXISTS",
+		1: "COMPARE_TARGET_VALUE",
+		2: "COMPARE_TARGET_VERSION",
+	}
+	CompareTarget_value = map[string]int32{
+		"COMPARE_TARGET_EXISTS":  0,
+		"COMPARE_TARGET_VALUE":   1,
+		"COMPARE_TARGET_VERSION": 2,
+	}
+)
+
+func (x CompareTarget) Enum() *CompareTarget {
+	p := new(CompareTarget)
+	*p = x
+	return p
+}
+
+func (x CompareTarget) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CompareTarget) Descriptor() protoreflect.EnumDescriptor {
+	return file_ampkv_proto_enumTypes[1].Descriptor()
+}
+
+func (CompareTarget) Type() protoreflect.EnumType {
+	return &file_ampkv_proto_enumTypes[1]
+}
+
+func (x CompareTarget) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CompareTarget.Descriptor instead.
+func (CompareTarget) EnumDescriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{1}
+}
+
+type CompareResult int32
+
+const (
+	CompareResult_COMPARE_RESULT_EQUAL     CompareResult = 0
+	CompareResult_COMPARE_RESULT_NOT_EQUAL CompareResult = 1
+	CompareResult_COMPARE_RESULT_GREATER   CompareResult = 2
+	CompareResult_COMPARE_RESULT_LESS      CompareResult = 3
+)
+
+// Enum value maps for CompareResult.
+var (
+	CompareResult_name = map[int32]string{
+		0: "COMPARE_RESULT_EQUAL",
+		1: "COMPARE_RESULT_NOT_EQUAL",
+		2: "COMPARE_RESULT_GREATER",
+		3: "COMPARE_RESULT_LESS",
+	}
+	CompareResult_value = map[string]int32{
+		"COMPARE_RESULT_EQUAL":     0,
+		"COMPARE_RESULT_NOT_EQUAL": 1,
+		"COMPARE_RESULT_GREATER":   2,
+		"COMPARE_RESULT_LESS":      3,
+	}
+)
+
+func (x CompareResult) Enum() *CompareResult {
+	p := new(CompareResult)
+	*p = x
+	return p
+}
+
+func (x CompareResult) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CompareResult) Descriptor() protoreflect.EnumDescriptor {
+	return file_ampkv_proto_enumTypes[2].Descriptor()
+}
+
+func (CompareResult) Type() protoreflect.EnumType {
+	return &file_ampkv_proto_enumTypes[2]
+}
+
+func (x CompareResult) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CompareResult.Descriptor instead.
+func (CompareResult) EnumDescriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{2}
+}
+
+type WatchEventType int32
+
+const (
+	WatchEventType_WATCH_EVENT_TYPE_UNKNOWN WatchEventType = 0
+	WatchEventType_WATCH_EVENT_TYPE_PUT     WatchEventType = 1
+	WatchEventType_WATCH_EVENT_TYPE_DELETE  WatchEventType = 2
+	WatchEventType_WATCH_EVENT_TYPE_EXPIRE  WatchEventType = 3
+)
+
+// Enum value maps for WatchEventType.
+var (
+	WatchEventType_name = map[int32]string{
+		0: "WATCH_EVENT_TYPE_UNKNOWN",
+		1: "WATCH_EVENT_TYPE_PUT",
+		2: "WATCH_EVENT_TYPE_DELETE",
+		3: "WATCH_EVENT_TYPE_EXPIRE",
+	}
+	WatchEventType_value = map[string]int32{
+		"WATCH_EVENT_TYPE_UNKNOWN": 0,
+		"WATCH_EVENT_TYPE_PUT":     1,
+		"WATCH_EVENT_TYPE_DELETE":  2,
+		"WATCH_EVENT_TYPE_EXPIRE":  3,
+	}
+)
+
+func (x WatchEventType) Enum() *WatchEventType {
+	p := new(WatchEventType)
+	*p = x
+	return p
+}
+
+func (x WatchEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_ampkv_proto_enumTypes[3].Descriptor()
+}
+
+func (WatchEventType) Type() protoreflect.EnumType {
+	return &file_ampkv_proto_enumTypes[3]
+}
+
+func (x WatchEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchEventType.Descriptor instead.
+func (WatchEventType) EnumDescriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{3}
+}
+
 type KeyValue struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Value         []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	Type          AmpKVDataTypeProto     `protobuf:"varint,3,opt,name=type,proto3,enum=ampkv.AmpKVDataTypeProto" json:"type,omitempty"`
 	Cost          int64                  `protobuf:"varint,4,opt,name=cost,proto3" json:"cost,omitempty"`
+	Version       uint64                 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -150,6 +320,13 @@ func (x *KeyValue) GetCost() int64 {
 	return 0
 }
 
+func (x *KeyValue) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type GetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -195,9 +372,16 @@ func (x *GetRequest) GetKey() string {
 }
 
 type GetResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Kv            *KeyValue              `protobuf:"bytes,1,opt,name=kv,proto3" json:"kv,omitempty"`
-	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Kv    *KeyValue              `protobuf:"bytes,1,opt,name=kv,proto3" json:"kv,omitempty"`
+	Found bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	// Unix timestamps in milliseconds. created_at and updated_at are 0 for
+	// values written before they were recorded, expires_at for values
+	// without a TTL.
+	CreatedAt     int64 `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64 `protobuf:"varint,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ExpiresAt     int64 `protobuf:"varint,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Size          int64 `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -246,6 +430,34 @@ func (x *GetResponse) GetFound() bool {
 	return false
 }
 
+func (x *GetResponse) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *GetResponse) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *GetResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *GetResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
 type SetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Kv            *KeyValue              `protobuf:"bytes,1,opt,name=kv,proto3" json:"kv,omitempty"`
@@ -386,28 +598,29 @@ func (x *DeleteRequest) GetKey() string {
 	return ""
 }
 
-type OperationResponse struct {
+type ScanRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Start         string                 `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OperationResponse) Reset() {
-	*x = OperationResponse{}
+func (x *ScanRequest) Reset() {
+	*x = ScanRequest{}
 	mi := &file_ampkv_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OperationResponse) String() string {
+func (x *ScanRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OperationResponse) ProtoMessage() {}
+func (*ScanRequest) ProtoMessage() {}
 
-func (x *OperationResponse) ProtoReflect() protoreflect.Message {
+func (x *ScanRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_ampkv_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -419,126 +632,4008 @@ func (x *OperationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OperationResponse.ProtoReflect.Descriptor instead.
-func (*OperationResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ScanRequest.ProtoReflect.Descriptor instead.
+func (*ScanRequest) Descriptor() ([]byte, []int) {
 	return file_ampkv_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *OperationResponse) GetSuccess() bool {
+func (x *ScanRequest) GetPrefix() string {
 	if x != nil {
-		return x.Success
+		return x.Prefix
 	}
-	return false
+	return ""
 }
 
-func (x *OperationResponse) GetMessage() string {
+func (x *ScanRequest) GetStart() string {
 	if x != nil {
-		return x.Message
+		return x.Start
 	}
 	return ""
 }
 
-var File_ampkv_proto protoreflect.FileDescriptor
+func (x *ScanRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
 
-const file_ampkv_proto_rawDesc = "" +
-	"\n" +
-	"\vampkv.proto\x12\x05ampkv\"u\n" +
-	"\bKeyValue\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\fR\x05value\x12-\n" +
-	"\x04type\x18\x03 \x01(\x0e2\x19.ampkv.AmpKVDataTypeProtoR\x04type\x12\x12\n" +
-	"\x04cost\x18\x04 \x01(\x03R\x04cost\"\x1e\n" +
-	"\n" +
-	"GetRequest\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\"D\n" +
-	"\vGetResponse\x12\x1f\n" +
-	"\x02kv\x18\x01 \x01(\v2\x0f.ampkv.KeyValueR\x02kv\x12\x14\n" +
-	"\x05found\x18\x02 \x01(\bR\x05found\"-\n" +
-	"\n" +
-	"SetRequest\x12\x1f\n" +
-	"\x02kv\x18\x01 \x01(\v2\x0f.ampkv.KeyValueR\x02kv\"U\n" +
-	"\x11SetWithTTLRequest\x12\x1f\n" +
-	"\x02kv\x18\x01 \x01(\v2\x0f.ampkv.KeyValueR\x02kv\x12\x1f\n" +
-	"\vttl_seconds\x18\x02 \x01(\x03R\n" +
-	"ttlSeconds\"!\n" +
-	"\rDeleteRequest\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\"G\n" +
-	"\x11OperationResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage*\xd8\x01\n" +
-	"\x12AmpKVDataTypeProto\x12\x1c\n" +
-	"\x18AMP_KV_DATA_TYPE_UNKNOWN\x10\x00\x12\x1b\n" +
-	"\x17AMP_KV_DATA_TYPE_STRING\x10\x01\x12\x18\n" +
-	"\x14AMP_KV_DATA_TYPE_INT\x10\x02\x12\x1a\n" +
-	"\x16AMP_KV_DATA_TYPE_FLOAT\x10\x03\x12\x19\n" +
-	"\x15AMP_KV_DATA_TYPE_BOOL\x10\x04\x12\x19\n" +
-	"\x15AMP_KV_DATA_TYPE_JSON\x10\x05\x12\x1b\n" +
-	"\x17AMP_KV_DATA_TYPE_BINARY\x10\x062\xec\x01\n" +
-	"\fAmpKVService\x12,\n" +
-	"\x03Get\x12\x11.ampkv.GetRequest\x1a\x12.ampkv.GetResponse\x122\n" +
-	"\x03Set\x12\x11.ampkv.SetRequest\x1a\x18.ampkv.OperationResponse\x12@\n" +
-	"\n" +
-	"SetWithTTL\x12\x18.ampkv.SetWithTTLRequest\x1a\x18.ampkv.OperationResponse\x128\n" +
-	"\x06Delete\x12\x14.ampkv.DeleteRequest\x1a\x18.ampkv.OperationResponseB)Z'github.com/Unfield/AmpKV/pkg/client/rpcb\x06proto3"
+type ScanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kv            *KeyValue              `protobuf:"bytes,1,opt,name=kv,proto3" json:"kv,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-var (
-	file_ampkv_proto_rawDescOnce sync.Once
-	file_ampkv_proto_rawDescData []byte
-)
+func (x *ScanResponse) Reset() {
+	*x = ScanResponse{}
+	mi := &file_ampkv_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-func file_ampkv_proto_rawDescGZIP() []byte {
-	file_ampkv_proto_rawDescOnce.Do(func() {
-		file_ampkv_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ampkv_proto_rawDesc), len(file_ampkv_proto_rawDesc)))
-	})
-	return file_ampkv_proto_rawDescData
+func (x *ScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_ampkv_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_ampkv_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
-var file_ampkv_proto_goTypes = []any{
-	(AmpKVDataTypeProto)(0),   // 0: ampkv.AmpKVDataTypeProto
-	(*KeyValue)(nil),          // 1: ampkv.KeyValue
-	(*GetRequest)(nil),        // 2: ampkv.GetRequest
-	(*GetResponse)(nil),       // 3: ampkv.GetResponse
-	(*SetRequest)(nil),        // 4: ampkv.SetRequest
-	(*SetWithTTLRequest)(nil), // 5: ampkv.SetWithTTLRequest
-	(*DeleteRequest)(nil),     // 6: ampkv.DeleteRequest
-	(*OperationResponse)(nil), // 7: ampkv.OperationResponse
+func (*ScanResponse) ProtoMessage() {}
+
+func (x *ScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_ampkv_proto_depIdxs = []int32{
-	0, // 0: ampkv.KeyValue.type:type_name -> ampkv.AmpKVDataTypeProto
-	1, // 1: ampkv.GetResponse.kv:type_name -> ampkv.KeyValue
-	1, // 2: ampkv.SetRequest.kv:type_name -> ampkv.KeyValue
-	1, // 3: ampkv.SetWithTTLRequest.kv:type_name -> ampkv.KeyValue
-	2, // 4: ampkv.AmpKVService.Get:input_type -> ampkv.GetRequest
-	4, // 5: ampkv.AmpKVService.Set:input_type -> ampkv.SetRequest
-	5, // 6: ampkv.AmpKVService.SetWithTTL:input_type -> ampkv.SetWithTTLRequest
-	6, // 7: ampkv.AmpKVService.Delete:input_type -> ampkv.DeleteRequest
-	3, // 8: ampkv.AmpKVService.Get:output_type -> ampkv.GetResponse
-	7, // 9: ampkv.AmpKVService.Set:output_type -> ampkv.OperationResponse
-	7, // 10: ampkv.AmpKVService.SetWithTTL:output_type -> ampkv.OperationResponse
-	7, // 11: ampkv.AmpKVService.Delete:output_type -> ampkv.OperationResponse
-	8, // [8:12] is the sub-list for method output_type
-	4, // [4:8] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+
+// Deprecated: Use ScanResponse.ProtoReflect.Descriptor instead.
+func (*ScanResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{7}
 }
 
-func init() { file_ampkv_proto_init() }
-func file_ampkv_proto_init() {
-	if File_ampkv_proto != nil {
-		return
+func (x *ScanResponse) GetKv() *KeyValue {
+	if x != nil {
+		return x.Kv
+	}
+	return nil
+}
+
+func (x *ScanResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type Compare struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Target        CompareTarget          `protobuf:"varint,2,opt,name=target,proto3,enum=ampkv.CompareTarget" json:"target,omitempty"`
+	Result        CompareResult          `protobuf:"varint,3,opt,name=result,proto3,enum=ampkv.CompareResult" json:"result,omitempty"`
+	Value         []byte                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	Version       uint64                 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Compare) Reset() {
+	*x = Compare{}
+	mi := &file_ampkv_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Compare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Compare) ProtoMessage() {}
+
+func (x *Compare) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Compare.ProtoReflect.Descriptor instead.
+func (*Compare) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Compare) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Compare) GetTarget() CompareTarget {
+	if x != nil {
+		return x.Target
+	}
+	return CompareTarget_COMPARE_TARGET_EXISTS
+}
+
+func (x *Compare) GetResult() CompareResult {
+	if x != nil {
+		return x.Result
+	}
+	return CompareResult_COMPARE_RESULT_EQUAL
+}
+
+func (x *Compare) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Compare) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type PutOp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kv            *KeyValue              `protobuf:"bytes,1,opt,name=kv,proto3" json:"kv,omitempty"`
+	TtlSeconds    int64                  `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutOp) Reset() {
+	*x = PutOp{}
+	mi := &file_ampkv_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutOp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutOp) ProtoMessage() {}
+
+func (x *PutOp) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutOp.ProtoReflect.Descriptor instead.
+func (*PutOp) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PutOp) GetKv() *KeyValue {
+	if x != nil {
+		return x.Kv
+	}
+	return nil
+}
+
+func (x *PutOp) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type DeleteOp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteOp) Reset() {
+	*x = DeleteOp{}
+	mi := &file_ampkv_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteOp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteOp) ProtoMessage() {}
+
+func (x *DeleteOp) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteOp.ProtoReflect.Descriptor instead.
+func (*DeleteOp) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteOp) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type TxnOp struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Op:
+	//
+	//	*TxnOp_Put
+	//	*TxnOp_Delete
+	Op            isTxnOp_Op `protobuf_oneof:"op"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TxnOp) Reset() {
+	*x = TxnOp{}
+	mi := &file_ampkv_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxnOp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxnOp) ProtoMessage() {}
+
+func (x *TxnOp) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxnOp.ProtoReflect.Descriptor instead.
+func (*TxnOp) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TxnOp) GetOp() isTxnOp_Op {
+	if x != nil {
+		return x.Op
+	}
+	return nil
+}
+
+func (x *TxnOp) GetPut() *PutOp {
+	if x != nil {
+		if x, ok := x.Op.(*TxnOp_Put); ok {
+			return x.Put
+		}
+	}
+	return nil
+}
+
+func (x *TxnOp) GetDelete() *DeleteOp {
+	if x != nil {
+		if x, ok := x.Op.(*TxnOp_Delete); ok {
+			return x.Delete
+		}
+	}
+	return nil
+}
+
+type isTxnOp_Op interface {
+	isTxnOp_Op()
+}
+
+type TxnOp_Put struct {
+	Put *PutOp `protobuf:"bytes,1,opt,name=put,proto3,oneof"`
+}
+
+type TxnOp_Delete struct {
+	Delete *DeleteOp `protobuf:"bytes,2,opt,name=delete,proto3,oneof"`
+}
+
+func (*TxnOp_Put) isTxnOp_Op() {}
+
+func (*TxnOp_Delete) isTxnOp_Op() {}
+
+type TxnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Compare       []*Compare             `protobuf:"bytes,1,rep,name=compare,proto3" json:"compare,omitempty"`
+	Success       []*TxnOp               `protobuf:"bytes,2,rep,name=success,proto3" json:"success,omitempty"`
+	Failure       []*TxnOp               `protobuf:"bytes,3,rep,name=failure,proto3" json:"failure,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TxnRequest) Reset() {
+	*x = TxnRequest{}
+	mi := &file_ampkv_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxnRequest) ProtoMessage() {}
+
+func (x *TxnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxnRequest.ProtoReflect.Descriptor instead.
+func (*TxnRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TxnRequest) GetCompare() []*Compare {
+	if x != nil {
+		return x.Compare
+	}
+	return nil
+}
+
+func (x *TxnRequest) GetSuccess() []*TxnOp {
+	if x != nil {
+		return x.Success
+	}
+	return nil
+}
+
+func (x *TxnRequest) GetFailure() []*TxnOp {
+	if x != nil {
+		return x.Failure
+	}
+	return nil
+}
+
+type TxnResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Succeeded     bool                   `protobuf:"varint,1,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TxnResponse) Reset() {
+	*x = TxnResponse{}
+	mi := &file_ampkv_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxnResponse) ProtoMessage() {}
+
+func (x *TxnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxnResponse.ProtoReflect.Descriptor instead.
+func (*TxnResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TxnResponse) GetSucceeded() bool {
+	if x != nil {
+		return x.Succeeded
+	}
+	return false
+}
+
+type CompareAndSwapRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Kv    *KeyValue              `protobuf:"bytes,1,opt,name=kv,proto3" json:"kv,omitempty"`
+	// expected_version of 0 requires the key to be absent.
+	ExpectedVersion uint64 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	TtlSeconds      int64  `protobuf:"varint,3,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CompareAndSwapRequest) Reset() {
+	*x = CompareAndSwapRequest{}
+	mi := &file_ampkv_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareAndSwapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareAndSwapRequest) ProtoMessage() {}
+
+func (x *CompareAndSwapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareAndSwapRequest.ProtoReflect.Descriptor instead.
+func (*CompareAndSwapRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CompareAndSwapRequest) GetKv() *KeyValue {
+	if x != nil {
+		return x.Kv
+	}
+	return nil
+}
+
+func (x *CompareAndSwapRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *CompareAndSwapRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type CompareAndSwapResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Succeeded bool                   `protobuf:"varint,1,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	// The new version on success, otherwise the key's current version.
+	Version       uint64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompareAndSwapResponse) Reset() {
+	*x = CompareAndSwapResponse{}
+	mi := &file_ampkv_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareAndSwapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareAndSwapResponse) ProtoMessage() {}
+
+func (x *CompareAndSwapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareAndSwapResponse.ProtoReflect.Descriptor instead.
+func (*CompareAndSwapResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CompareAndSwapResponse) GetSucceeded() bool {
+	if x != nil {
+		return x.Succeeded
+	}
+	return false
+}
+
+func (x *CompareAndSwapResponse) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type MultiGetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiGetRequest) Reset() {
+	*x = MultiGetRequest{}
+	mi := &file_ampkv_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiGetRequest) ProtoMessage() {}
+
+func (x *MultiGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiGetRequest.ProtoReflect.Descriptor instead.
+func (*MultiGetRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *MultiGetRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type MultiGetResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per requested key, in request order.
+	Results       []*GetResponse `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiGetResponse) Reset() {
+	*x = MultiGetResponse{}
+	mi := &file_ampkv_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiGetResponse) ProtoMessage() {}
+
+func (x *MultiGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiGetResponse.ProtoReflect.Descriptor instead.
+func (*MultiGetResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *MultiGetResponse) GetResults() []*GetResponse {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type MultiSetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*PutOp               `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiSetRequest) Reset() {
+	*x = MultiSetRequest{}
+	mi := &file_ampkv_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiSetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiSetRequest) ProtoMessage() {}
+
+func (x *MultiSetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiSetRequest.ProtoReflect.Descriptor instead.
+func (*MultiSetRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *MultiSetRequest) GetItems() []*PutOp {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type MultiSetResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The new version of every item, in request order.
+	Versions      []uint64 `protobuf:"varint,1,rep,packed,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiSetResponse) Reset() {
+	*x = MultiSetResponse{}
+	mi := &file_ampkv_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiSetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiSetResponse) ProtoMessage() {}
+
+func (x *MultiSetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiSetResponse.ProtoReflect.Descriptor instead.
+func (*MultiSetResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *MultiSetResponse) GetVersions() []uint64 {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+type MultiDeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiDeleteRequest) Reset() {
+	*x = MultiDeleteRequest{}
+	mi := &file_ampkv_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiDeleteRequest) ProtoMessage() {}
+
+func (x *MultiDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiDeleteRequest.ProtoReflect.Descriptor instead.
+func (*MultiDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MultiDeleteRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type TTLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TTLRequest) Reset() {
+	*x = TTLRequest{}
+	mi := &file_ampkv_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TTLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TTLRequest) ProtoMessage() {}
+
+func (x *TTLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TTLRequest.ProtoReflect.Descriptor instead.
+func (*TTLRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TTLRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type TTLResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Found bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	// ttl_ms is the remaining time to live in milliseconds and expires_at a
+	// Unix timestamp in milliseconds, both 0 for keys that do not expire.
+	TtlMs         int64 `protobuf:"varint,2,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`
+	ExpiresAt     int64 `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TTLResponse) Reset() {
+	*x = TTLResponse{}
+	mi := &file_ampkv_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TTLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TTLResponse) ProtoMessage() {}
+
+func (x *TTLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TTLResponse.ProtoReflect.Descriptor instead.
+func (*TTLResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TTLResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *TTLResponse) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+func (x *TTLResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type ExpireRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// A ttl of 0 or less, or an expires_at in the past, deletes the key.
+	//
+	// Types that are valid to be assigned to Expiry:
+	//
+	//	*ExpireRequest_TtlSeconds
+	//	*ExpireRequest_ExpiresAt
+	Expiry        isExpireRequest_Expiry `protobuf_oneof:"expiry"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpireRequest) Reset() {
+	*x = ExpireRequest{}
+	mi := &file_ampkv_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpireRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpireRequest) ProtoMessage() {}
+
+func (x *ExpireRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpireRequest.ProtoReflect.Descriptor instead.
+func (*ExpireRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ExpireRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ExpireRequest) GetExpiry() isExpireRequest_Expiry {
+	if x != nil {
+		return x.Expiry
+	}
+	return nil
+}
+
+func (x *ExpireRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		if x, ok := x.Expiry.(*ExpireRequest_TtlSeconds); ok {
+			return x.TtlSeconds
+		}
+	}
+	return 0
+}
+
+func (x *ExpireRequest) GetExpiresAt() int64 {
+	if x != nil {
+		if x, ok := x.Expiry.(*ExpireRequest_ExpiresAt); ok {
+			return x.ExpiresAt
+		}
+	}
+	return 0
+}
+
+type isExpireRequest_Expiry interface {
+	isExpireRequest_Expiry()
+}
+
+type ExpireRequest_TtlSeconds struct {
+	TtlSeconds int64 `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3,oneof"`
+}
+
+type ExpireRequest_ExpiresAt struct {
+	// Unix timestamp in milliseconds.
+	ExpiresAt int64 `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3,oneof"`
+}
+
+func (*ExpireRequest_TtlSeconds) isExpireRequest_Expiry() {}
+
+func (*ExpireRequest_ExpiresAt) isExpireRequest_Expiry() {}
+
+type PersistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PersistRequest) Reset() {
+	*x = PersistRequest{}
+	mi := &file_ampkv_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PersistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PersistRequest) ProtoMessage() {}
+
+func (x *PersistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PersistRequest.ProtoReflect.Descriptor instead.
+func (*PersistRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PersistRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type IncrementRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// int_delta increments TypeInt values, float_delta TypeFloat values.
+	// Missing keys are created with the delta.
+	//
+	// Types that are valid to be assigned to Delta:
+	//
+	//	*IncrementRequest_IntDelta
+	//	*IncrementRequest_FloatDelta
+	Delta         isIncrementRequest_Delta `protobuf_oneof:"delta"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncrementRequest) Reset() {
+	*x = IncrementRequest{}
+	mi := &file_ampkv_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncrementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncrementRequest) ProtoMessage() {}
+
+func (x *IncrementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncrementRequest.ProtoReflect.Descriptor instead.
+func (*IncrementRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *IncrementRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *IncrementRequest) GetDelta() isIncrementRequest_Delta {
+	if x != nil {
+		return x.Delta
+	}
+	return nil
+}
+
+func (x *IncrementRequest) GetIntDelta() int64 {
+	if x != nil {
+		if x, ok := x.Delta.(*IncrementRequest_IntDelta); ok {
+			return x.IntDelta
+		}
+	}
+	return 0
+}
+
+func (x *IncrementRequest) GetFloatDelta() float64 {
+	if x != nil {
+		if x, ok := x.Delta.(*IncrementRequest_FloatDelta); ok {
+			return x.FloatDelta
+		}
+	}
+	return 0
+}
+
+type isIncrementRequest_Delta interface {
+	isIncrementRequest_Delta()
+}
+
+type IncrementRequest_IntDelta struct {
+	IntDelta int64 `protobuf:"varint,2,opt,name=int_delta,json=intDelta,proto3,oneof"`
+}
+
+type IncrementRequest_FloatDelta struct {
+	FloatDelta float64 `protobuf:"fixed64,3,opt,name=float_delta,json=floatDelta,proto3,oneof"`
+}
+
+func (*IncrementRequest_IntDelta) isIncrementRequest_Delta() {}
+
+func (*IncrementRequest_FloatDelta) isIncrementRequest_Delta() {}
+
+type IncrementResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*IncrementResponse_IntValue
+	//	*IncrementResponse_FloatValue
+	Value         isIncrementResponse_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncrementResponse) Reset() {
+	*x = IncrementResponse{}
+	mi := &file_ampkv_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncrementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncrementResponse) ProtoMessage() {}
+
+func (x *IncrementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncrementResponse.ProtoReflect.Descriptor instead.
+func (*IncrementResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *IncrementResponse) GetValue() isIncrementResponse_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *IncrementResponse) GetIntValue() int64 {
+	if x != nil {
+		if x, ok := x.Value.(*IncrementResponse_IntValue); ok {
+			return x.IntValue
+		}
+	}
+	return 0
+}
+
+func (x *IncrementResponse) GetFloatValue() float64 {
+	if x != nil {
+		if x, ok := x.Value.(*IncrementResponse_FloatValue); ok {
+			return x.FloatValue
+		}
+	}
+	return 0
+}
+
+type isIncrementResponse_Value interface {
+	isIncrementResponse_Value()
+}
+
+type IncrementResponse_IntValue struct {
+	IntValue int64 `protobuf:"varint,1,opt,name=int_value,json=intValue,proto3,oneof"`
+}
+
+type IncrementResponse_FloatValue struct {
+	FloatValue float64 `protobuf:"fixed64,2,opt,name=float_value,json=floatValue,proto3,oneof"`
+}
+
+func (*IncrementResponse_IntValue) isIncrementResponse_Value() {}
+
+func (*IncrementResponse_FloatValue) isIncrementResponse_Value() {}
+
+// TypedValue is an element of a list or the value of a hash field.
+type TypedValue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         []byte                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Type          AmpKVDataTypeProto     `protobuf:"varint,2,opt,name=type,proto3,enum=ampkv.AmpKVDataTypeProto" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TypedValue) Reset() {
+	*x = TypedValue{}
+	mi := &file_ampkv_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TypedValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypedValue) ProtoMessage() {}
+
+func (x *TypedValue) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypedValue.ProtoReflect.Descriptor instead.
+func (*TypedValue) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *TypedValue) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *TypedValue) GetType() AmpKVDataTypeProto {
+	if x != nil {
+		return x.Type
+	}
+	return AmpKVDataTypeProto_AMP_KV_DATA_TYPE_UNKNOWN
+}
+
+type CountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountResponse) Reset() {
+	*x = CountResponse{}
+	mi := &file_ampkv_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountResponse) ProtoMessage() {}
+
+func (x *CountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountResponse.ProtoReflect.Descriptor instead.
+func (*CountResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CountResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type ListPushRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Key    string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Values []*TypedValue          `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	// left prepends the values, otherwise they are appended.
+	Left          bool `protobuf:"varint,3,opt,name=left,proto3" json:"left,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPushRequest) Reset() {
+	*x = ListPushRequest{}
+	mi := &file_ampkv_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPushRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPushRequest) ProtoMessage() {}
+
+func (x *ListPushRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPushRequest.ProtoReflect.Descriptor instead.
+func (*ListPushRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListPushRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ListPushRequest) GetValues() []*TypedValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *ListPushRequest) GetLeft() bool {
+	if x != nil {
+		return x.Left
+	}
+	return false
+}
+
+type ListPopRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Left          bool                   `protobuf:"varint,2,opt,name=left,proto3" json:"left,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPopRequest) Reset() {
+	*x = ListPopRequest{}
+	mi := &file_ampkv_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPopRequest) ProtoMessage() {}
+
+func (x *ListPopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPopRequest.ProtoReflect.Descriptor instead.
+func (*ListPopRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListPopRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ListPopRequest) GetLeft() bool {
+	if x != nil {
+		return x.Left
+	}
+	return false
+}
+
+type ListPopResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Value         *TypedValue            `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPopResponse) Reset() {
+	*x = ListPopResponse{}
+	mi := &file_ampkv_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPopResponse) ProtoMessage() {}
+
+func (x *ListPopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPopResponse.ProtoReflect.Descriptor instead.
+func (*ListPopResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListPopResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *ListPopResponse) GetValue() *TypedValue {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type ListRangeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Inclusive positions, negative ones count from the end of the list.
+	Start         int64 `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	Stop          int64 `protobuf:"varint,3,opt,name=stop,proto3" json:"stop,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRangeRequest) Reset() {
+	*x = ListRangeRequest{}
+	mi := &file_ampkv_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRangeRequest) ProtoMessage() {}
+
+func (x *ListRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRangeRequest.ProtoReflect.Descriptor instead.
+func (*ListRangeRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListRangeRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ListRangeRequest) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *ListRangeRequest) GetStop() int64 {
+	if x != nil {
+		return x.Stop
+	}
+	return 0
+}
+
+type ListRangeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []*TypedValue          `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRangeResponse) Reset() {
+	*x = ListRangeResponse{}
+	mi := &file_ampkv_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRangeResponse) ProtoMessage() {}
+
+func (x *ListRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRangeResponse.ProtoReflect.Descriptor instead.
+func (*ListRangeResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListRangeResponse) GetValues() []*TypedValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type SetAddRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Members       []string               `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAddRequest) Reset() {
+	*x = SetAddRequest{}
+	mi := &file_ampkv_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAddRequest) ProtoMessage() {}
+
+func (x *SetAddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAddRequest.ProtoReflect.Descriptor instead.
+func (*SetAddRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *SetAddRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SetAddRequest) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type SetRemoveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Members       []string               `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRemoveRequest) Reset() {
+	*x = SetRemoveRequest{}
+	mi := &file_ampkv_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRemoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRemoveRequest) ProtoMessage() {}
+
+func (x *SetRemoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRemoveRequest.ProtoReflect.Descriptor instead.
+func (*SetRemoveRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SetRemoveRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SetRemoveRequest) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type SetMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMembersRequest) Reset() {
+	*x = SetMembersRequest{}
+	mi := &file_ampkv_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMembersRequest) ProtoMessage() {}
+
+func (x *SetMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMembersRequest.ProtoReflect.Descriptor instead.
+func (*SetMembersRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SetMembersRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type SetMembersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []string               `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMembersResponse) Reset() {
+	*x = SetMembersResponse{}
+	mi := &file_ampkv_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMembersResponse) ProtoMessage() {}
+
+func (x *SetMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMembersResponse.ProtoReflect.Descriptor instead.
+func (*SetMembersResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SetMembersResponse) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type HashSetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Field         string                 `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	Value         *TypedValue            `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HashSetRequest) Reset() {
+	*x = HashSetRequest{}
+	mi := &file_ampkv_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HashSetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HashSetRequest) ProtoMessage() {}
+
+func (x *HashSetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HashSetRequest.ProtoReflect.Descriptor instead.
+func (*HashSetRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *HashSetRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HashSetRequest) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *HashSetRequest) GetValue() *TypedValue {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type HashSetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Created       bool                   `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HashSetResponse) Reset() {
+	*x = HashSetResponse{}
+	mi := &file_ampkv_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HashSetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HashSetResponse) ProtoMessage() {}
+
+func (x *HashSetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HashSetResponse.ProtoReflect.Descriptor instead.
+func (*HashSetResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *HashSetResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+type HashGetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Field         string                 `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HashGetRequest) Reset() {
+	*x = HashGetRequest{}
+	mi := &file_ampkv_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HashGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HashGetRequest) ProtoMessage() {}
+
+func (x *HashGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HashGetRequest.ProtoReflect.Descriptor instead.
+func (*HashGetRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *HashGetRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HashGetRequest) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+type HashGetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Value         *TypedValue            `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HashGetResponse) Reset() {
+	*x = HashGetResponse{}
+	mi := &file_ampkv_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HashGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HashGetResponse) ProtoMessage() {}
+
+func (x *HashGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HashGetResponse.ProtoReflect.Descriptor instead.
+func (*HashGetResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *HashGetResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *HashGetResponse) GetValue() *TypedValue {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type HashDeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Fields        []string               `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HashDeleteRequest) Reset() {
+	*x = HashDeleteRequest{}
+	mi := &file_ampkv_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HashDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HashDeleteRequest) ProtoMessage() {}
+
+func (x *HashDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HashDeleteRequest.ProtoReflect.Descriptor instead.
+func (*HashDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *HashDeleteRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HashDeleteRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type HashGetAllRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HashGetAllRequest) Reset() {
+	*x = HashGetAllRequest{}
+	mi := &file_ampkv_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HashGetAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HashGetAllRequest) ProtoMessage() {}
+
+func (x *HashGetAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HashGetAllRequest.ProtoReflect.Descriptor instead.
+func (*HashGetAllRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *HashGetAllRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type HashGetAllResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fields        map[string]*TypedValue `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HashGetAllResponse) Reset() {
+	*x = HashGetAllResponse{}
+	mi := &file_ampkv_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HashGetAllResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HashGetAllResponse) ProtoMessage() {}
+
+func (x *HashGetAllResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HashGetAllResponse.ProtoReflect.Descriptor instead.
+func (*HashGetAllResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *HashGetAllResponse) GetFields() map[string]*TypedValue {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type ScoredMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Member        string                 `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScoredMember) Reset() {
+	*x = ScoredMember{}
+	mi := &file_ampkv_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoredMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoredMember) ProtoMessage() {}
+
+func (x *ScoredMember) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoredMember.ProtoReflect.Descriptor instead.
+func (*ScoredMember) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ScoredMember) GetMember() string {
+	if x != nil {
+		return x.Member
+	}
+	return ""
+}
+
+func (x *ScoredMember) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type SortedSetAddRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Members       []*ScoredMember        `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SortedSetAddRequest) Reset() {
+	*x = SortedSetAddRequest{}
+	mi := &file_ampkv_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SortedSetAddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SortedSetAddRequest) ProtoMessage() {}
+
+func (x *SortedSetAddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SortedSetAddRequest.ProtoReflect.Descriptor instead.
+func (*SortedSetAddRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *SortedSetAddRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SortedSetAddRequest) GetMembers() []*ScoredMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type SortedSetRemoveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Members       []string               `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SortedSetRemoveRequest) Reset() {
+	*x = SortedSetRemoveRequest{}
+	mi := &file_ampkv_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SortedSetRemoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SortedSetRemoveRequest) ProtoMessage() {}
+
+func (x *SortedSetRemoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SortedSetRemoveRequest.ProtoReflect.Descriptor instead.
+func (*SortedSetRemoveRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SortedSetRemoveRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SortedSetRemoveRequest) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type SortedSetRangeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Inclusive bounds; limit 0 returns every member in range.
+	Min           float64 `protobuf:"fixed64,2,opt,name=min,proto3" json:"min,omitempty"`
+	Max           float64 `protobuf:"fixed64,3,opt,name=max,proto3" json:"max,omitempty"`
+	Limit         int32   `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SortedSetRangeRequest) Reset() {
+	*x = SortedSetRangeRequest{}
+	mi := &file_ampkv_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SortedSetRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SortedSetRangeRequest) ProtoMessage() {}
+
+func (x *SortedSetRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SortedSetRangeRequest.ProtoReflect.Descriptor instead.
+func (*SortedSetRangeRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *SortedSetRangeRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SortedSetRangeRequest) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *SortedSetRangeRequest) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *SortedSetRangeRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SortedSetRangeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []*ScoredMember        `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SortedSetRangeResponse) Reset() {
+	*x = SortedSetRangeResponse{}
+	mi := &file_ampkv_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SortedSetRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SortedSetRangeResponse) ProtoMessage() {}
+
+func (x *SortedSetRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SortedSetRangeResponse.ProtoReflect.Descriptor instead.
+func (*SortedSetRangeResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *SortedSetRangeResponse) GetMembers() []*ScoredMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Key    string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Prefix bool                   `protobuf:"varint,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// start_revision replays retained events from this revision on; 0 only
+	// streams new events.
+	StartRevision uint64 `protobuf:"varint,3,opt,name=start_revision,json=startRevision,proto3" json:"start_revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_ampkv_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *WatchRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetPrefix() bool {
+	if x != nil {
+		return x.Prefix
+	}
+	return false
+}
+
+func (x *WatchRequest) GetStartRevision() uint64 {
+	if x != nil {
+		return x.StartRevision
+	}
+	return 0
+}
+
+type WatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  WatchEventType         `protobuf:"varint,1,opt,name=type,proto3,enum=ampkv.WatchEventType" json:"type,omitempty"`
+	// Only the key is set for delete and expire events.
+	Kv            *KeyValue `protobuf:"bytes,2,opt,name=kv,proto3" json:"kv,omitempty"`
+	Revision      uint64    `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchResponse) Reset() {
+	*x = WatchResponse{}
+	mi := &file_ampkv_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchResponse) ProtoMessage() {}
+
+func (x *WatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchResponse.ProtoReflect.Descriptor instead.
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *WatchResponse) GetType() WatchEventType {
+	if x != nil {
+		return x.Type
+	}
+	return WatchEventType_WATCH_EVENT_TYPE_UNKNOWN
+}
+
+func (x *WatchResponse) GetKv() *KeyValue {
+	if x != nil {
+		return x.Kv
+	}
+	return nil
+}
+
+func (x *WatchResponse) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type OperationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationResponse) Reset() {
+	*x = OperationResponse{}
+	mi := &file_ampkv_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationResponse) ProtoMessage() {}
+
+func (x *OperationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationResponse.ProtoReflect.Descriptor instead.
+func (*OperationResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *OperationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *OperationResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ApiKeyScope grants permissions on every key starting with prefix, on top
+// of the global permissions of the key.
+type ApiKeyScope struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Permissions   []string               `protobuf:"bytes,2,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApiKeyScope) Reset() {
+	*x = ApiKeyScope{}
+	mi := &file_ampkv_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApiKeyScope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApiKeyScope) ProtoMessage() {}
+
+func (x *ApiKeyScope) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApiKeyScope.ProtoReflect.Descriptor instead.
+func (*ApiKeyScope) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ApiKeyScope) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ApiKeyScope) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+// ApiKeyLimits throttle a key, zero disables a limit. Daily quotas reset at
+// midnight UTC.
+type ApiKeyLimits struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	RequestsPerSecond float64                `protobuf:"fixed64,1,opt,name=requests_per_second,json=requestsPerSecond,proto3" json:"requests_per_second,omitempty"`
+	Burst             int32                  `protobuf:"varint,2,opt,name=burst,proto3" json:"burst,omitempty"`
+	DailyOps          int64                  `protobuf:"varint,3,opt,name=daily_ops,json=dailyOps,proto3" json:"daily_ops,omitempty"`
+	DailyBytes        int64                  `protobuf:"varint,4,opt,name=daily_bytes,json=dailyBytes,proto3" json:"daily_bytes,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ApiKeyLimits) Reset() {
+	*x = ApiKeyLimits{}
+	mi := &file_ampkv_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApiKeyLimits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApiKeyLimits) ProtoMessage() {}
+
+func (x *ApiKeyLimits) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApiKeyLimits.ProtoReflect.Descriptor instead.
+func (*ApiKeyLimits) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ApiKeyLimits) GetRequestsPerSecond() float64 {
+	if x != nil {
+		return x.RequestsPerSecond
+	}
+	return 0
+}
+
+func (x *ApiKeyLimits) GetBurst() int32 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+func (x *ApiKeyLimits) GetDailyOps() int64 {
+	if x != nil {
+		return x.DailyOps
+	}
+	return 0
+}
+
+func (x *ApiKeyLimits) GetDailyBytes() int64 {
+	if x != nil {
+		return x.DailyBytes
+	}
+	return 0
+}
+
+type ApiKeyInfo struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Permissions []string               `protobuf:"bytes,3,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	// Unix timestamps in seconds, expires_at is 0 for keys that do not expire.
+	CreatedAt int64 `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt int64 `protobuf:"varint,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Disabled  bool  `protobuf:"varint,6,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	// legacy keys were migrated from plaintext records and also accept the
+	// bare secret as token.
+	Legacy        bool           `protobuf:"varint,7,opt,name=legacy,proto3" json:"legacy,omitempty"`
+	Scopes        []*ApiKeyScope `protobuf:"bytes,8,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Limits        *ApiKeyLimits  `protobuf:"bytes,9,opt,name=limits,proto3" json:"limits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApiKeyInfo) Reset() {
+	*x = ApiKeyInfo{}
+	mi := &file_ampkv_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApiKeyInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApiKeyInfo) ProtoMessage() {}
+
+func (x *ApiKeyInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApiKeyInfo.ProtoReflect.Descriptor instead.
+func (*ApiKeyInfo) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ApiKeyInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ApiKeyInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ApiKeyInfo) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+func (x *ApiKeyInfo) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ApiKeyInfo) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *ApiKeyInfo) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *ApiKeyInfo) GetLegacy() bool {
+	if x != nil {
+		return x.Legacy
+	}
+	return false
+}
+
+func (x *ApiKeyInfo) GetScopes() []*ApiKeyScope {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *ApiKeyInfo) GetLimits() *ApiKeyLimits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+type CreateApiKeyRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Permissions []string               `protobuf:"bytes,2,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	Disabled    bool                   `protobuf:"varint,3,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	// ttl_seconds of 0 creates a key that does not expire.
+	TtlSeconds    int64          `protobuf:"varint,4,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	Scopes        []*ApiKeyScope `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateApiKeyRequest) Reset() {
+	*x = CreateApiKeyRequest{}
+	mi := &file_ampkv_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateApiKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateApiKeyRequest) ProtoMessage() {}
+
+func (x *CreateApiKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateApiKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateApiKeyRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *CreateApiKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateApiKeyRequest) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+func (x *CreateApiKeyRequest) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *CreateApiKeyRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *CreateApiKeyRequest) GetScopes() []*ApiKeyScope {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+type ApiKeyTokenResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   *ApiKeyInfo            `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// token is only returned once and can not be recovered later.
+	Token         string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApiKeyTokenResponse) Reset() {
+	*x = ApiKeyTokenResponse{}
+	mi := &file_ampkv_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApiKeyTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApiKeyTokenResponse) ProtoMessage() {}
+
+func (x *ApiKeyTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApiKeyTokenResponse.ProtoReflect.Descriptor instead.
+func (*ApiKeyTokenResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ApiKeyTokenResponse) GetKey() *ApiKeyInfo {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *ApiKeyTokenResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type ListApiKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cursor        string                 `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListApiKeysRequest) Reset() {
+	*x = ListApiKeysRequest{}
+	mi := &file_ampkv_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListApiKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListApiKeysRequest) ProtoMessage() {}
+
+func (x *ListApiKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListApiKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListApiKeysRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ListApiKeysRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListApiKeysRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListApiKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*ApiKeyInfo          `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListApiKeysResponse) Reset() {
+	*x = ListApiKeysResponse{}
+	mi := &file_ampkv_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListApiKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListApiKeysResponse) ProtoMessage() {}
+
+func (x *ListApiKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListApiKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListApiKeysResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ListApiKeysResponse) GetKeys() []*ApiKeyInfo {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *ListApiKeysResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ApiKeyIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApiKeyIdRequest) Reset() {
+	*x = ApiKeyIdRequest{}
+	mi := &file_ampkv_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApiKeyIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApiKeyIdRequest) ProtoMessage() {}
+
+func (x *ApiKeyIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApiKeyIdRequest.ProtoReflect.Descriptor instead.
+func (*ApiKeyIdRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ApiKeyIdRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type SetApiKeyDisabledRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Disabled      bool                   `protobuf:"varint,2,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetApiKeyDisabledRequest) Reset() {
+	*x = SetApiKeyDisabledRequest{}
+	mi := &file_ampkv_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetApiKeyDisabledRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetApiKeyDisabledRequest) ProtoMessage() {}
+
+func (x *SetApiKeyDisabledRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetApiKeyDisabledRequest.ProtoReflect.Descriptor instead.
+func (*SetApiKeyDisabledRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *SetApiKeyDisabledRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetApiKeyDisabledRequest) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+type SetApiKeyLimitsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Limits        *ApiKeyLimits          `protobuf:"bytes,2,opt,name=limits,proto3" json:"limits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetApiKeyLimitsRequest) Reset() {
+	*x = SetApiKeyLimitsRequest{}
+	mi := &file_ampkv_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetApiKeyLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetApiKeyLimitsRequest) ProtoMessage() {}
+
+func (x *SetApiKeyLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetApiKeyLimitsRequest.ProtoReflect.Descriptor instead.
+func (*SetApiKeyLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *SetApiKeyLimitsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetApiKeyLimitsRequest) GetLimits() *ApiKeyLimits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+type SetApiKeyExpirationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// expires_at is a Unix timestamp in seconds, 0 removes the expiration.
+	ExpiresAt     int64 `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetApiKeyExpirationRequest) Reset() {
+	*x = SetApiKeyExpirationRequest{}
+	mi := &file_ampkv_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetApiKeyExpirationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetApiKeyExpirationRequest) ProtoMessage() {}
+
+func (x *SetApiKeyExpirationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetApiKeyExpirationRequest.ProtoReflect.Descriptor instead.
+func (*SetApiKeyExpirationRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *SetApiKeyExpirationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetApiKeyExpirationRequest) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type AuditEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unix timestamp in nanoseconds.
+	Time      int64    `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	KeyId     string   `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Protocol  string   `protobuf:"bytes,3,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Operation string   `protobuf:"bytes,4,opt,name=operation,proto3" json:"operation,omitempty"`
+	Keys      []string `protobuf:"bytes,5,rep,name=keys,proto3" json:"keys,omitempty"`
+	// result is the gRPC status code or the Http status of the request.
+	Result        string `protobuf:"bytes,6,opt,name=result,proto3" json:"result,omitempty"`
+	Client        string `protobuf:"bytes,7,opt,name=client,proto3" json:"client,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEntry) Reset() {
+	*x = AuditEntry{}
+	mi := &file_ampkv_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEntry) ProtoMessage() {}
+
+func (x *AuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEntry.ProtoReflect.Descriptor instead.
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *AuditEntry) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
+}
+
+type QueryAuditLogRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty fields match every entry; from and to are Unix timestamps in
+	// seconds.
+	KeyId         string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	From          int64  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	Cursor        string `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         int32  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryAuditLogRequest) Reset() {
+	*x = QueryAuditLogRequest{}
+	mi := &file_ampkv_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryAuditLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryAuditLogRequest) ProtoMessage() {}
+
+func (x *QueryAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*QueryAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *QueryAuditLogRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *QueryAuditLogRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *QueryAuditLogRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type QueryAuditLogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*AuditEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryAuditLogResponse) Reset() {
+	*x = QueryAuditLogResponse{}
+	mi := &file_ampkv_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryAuditLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryAuditLogResponse) ProtoMessage() {}
+
+func (x *QueryAuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ampkv_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryAuditLogResponse.ProtoReflect.Descriptor instead.
+func (*QueryAuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_ampkv_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *QueryAuditLogResponse) GetEntries() []*AuditEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *QueryAuditLogResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+var File_ampkv_proto protoreflect.FileDescriptor
+
+const file_ampkv_proto_rawDesc = "" +
+	"\n" +
+	"\vampkv.proto\x12\x05ampkv\"\x8f\x01\n" +
+	"\bKeyValue\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value\x12-\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x19.ampkv.AmpKVDataTypeProtoR\x04type\x12\x12\n" +
+	"\x04cost\x18\x04 \x01(\x03R\x04cost\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x04R\aversion\"\x1e\n" +
+	"\n" +
+	"GetRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"\xb5\x01\n" +
+	"\vGetResponse\x12\x1f\n" +
+	"\x02kv\x18\x01 \x01(\v2\x0f.ampkv.KeyValueR\x02kv\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\x03R\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\x03R\texpiresAt\x12\x12\n" +
+	"\x04size\x18\x06 \x01(\x03R\x04size\"-\n" +
+	"\n" +
+	"SetRequest\x12\x1f\n" +
+	"\x02kv\x18\x01 \x01(\v2\x0f.ampkv.KeyValueR\x02kv\"U\n" +
+	"\x11SetWithTTLRequest\x12\x1f\n" +
+	"\x02kv\x18\x01 \x01(\v2\x0f.ampkv.KeyValueR\x02kv\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\x03R\n" +
+	"ttlSeconds\"!\n" +
+	"\rDeleteRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"Q\n" +
+	"\vScanRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\tR\x05start\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"P\n" +
+	"\fScanResponse\x12\x1f\n" +
+	"\x02kv\x18\x01 \x01(\v2\x0f.ampkv.KeyValueR\x02kv\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\xa7\x01\n" +
+	"\aCompare\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x06target\x18\x02 \x01(\x0e2\x14.ampkv.CompareTargetR\x06target\x12,\n" +
+	"\x06result\x18\x03 \x01(\x0e2\x14.ampkv.CompareResultR\x06result\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\fR\x05value\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x04R\aversion\"I\n" +
+	"\x05PutOp\x12\x1f\n" +
+	"\x02kv\x18\x01 \x01(\v2\x0f.ampkv.KeyValueR\x02kv\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\x03R\n" +
+	"ttlSeconds\"\x1c\n" +
+	"\bDeleteOp\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"Z\n" +
+	"\x05TxnOp\x12 \n" +
+	"\x03put\x18\x01 \x01(\v2\f.ampkv.PutOpH\x00R\x03put\x12)\n" +
+	"\x06delete\x18\x02 \x01(\v2\x0f.ampkv.DeleteOpH\x00R\x06deleteB\x04\n" +
+	"\x02op\"\x86\x01\n" +
+	"\n" +
+	"TxnRequest\x12(\n" +
+	"\acompare\x18\x01 \x03(\v2\x0e.ampkv.CompareR\acompare\x12&\n" +
+	"\asuccess\x18\x02 \x03(\v2\f.ampkv.TxnOpR\asuccess\x12&\n" +
+	"\afailure\x18\x03 \x03(\v2\f.ampkv.TxnOpR\afailure\"+\n" +
+	"\vTxnResponse\x12\x1c\n" +
+	"\tsucceeded\x18\x01 \x01(\bR\tsucceeded\"\x84\x01\n" +
+	"\x15CompareAndSwapRequest\x12\x1f\n" +
+	"\x02kv\x18\x01 \x01(\v2\x0f.ampkv.KeyValueR\x02kv\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x04R\x0fexpectedVersion\x12\x1f\n" +
+	"\vttl_seconds\x18\x03 \x01(\x03R\n" +
+	"ttlSeconds\"P\n" +
+	"\x16CompareAndSwapResponse\x12\x1c\n" +
+	"\tsucceeded\x18\x01 \x01(\bR\tsucceeded\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\"%\n" +
+	"\x0fMultiGetRequest\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\"@\n" +
+	"\x10MultiGetResponse\x12,\n" +
+	"\aresults\x18\x01 \x03(\v2\x12.ampkv.GetResponseR\aresults\"5\n" +
+	"\x0fMultiSetRequest\x12\"\n" +
+	"\x05items\x18\x01 \x03(\v2\f.ampkv.PutOpR\x05items\".\n" +
+	"\x10MultiSetResponse\x12\x1a\n" +
+	"\bversions\x18\x01 \x03(\x04R\bversions\"(\n" +
+	"\x12MultiDeleteRequest\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\"\x1e\n" +
+	"\n" +
+	"TTLRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"Y\n" +
+	"\vTTLResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12\x15\n" +
+	"\x06ttl_ms\x18\x02 \x01(\x03R\x05ttlMs\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\x03R\texpiresAt\"o\n" +
+	"\rExpireRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12!\n" +
+	"\vttl_seconds\x18\x02 \x01(\x03H\x00R\n" +
+	"ttlSeconds\x12\x1f\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\x03H\x00R\texpiresAtB\b\n" +
+	"\x06expiry\"\"\n" +
+	"\x0ePersistRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"o\n" +
+	"\x10IncrementRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1d\n" +
+	"\tint_delta\x18\x02 \x01(\x03H\x00R\bintDelta\x12!\n" +
+	"\vfloat_delta\x18\x03 \x01(\x01H\x00R\n" +
+	"floatDeltaB\a\n" +
+	"\x05delta\"^\n" +
+	"\x11IncrementResponse\x12\x1d\n" +
+	"\tint_value\x18\x01 \x01(\x03H\x00R\bintValue\x12!\n" +
+	"\vfloat_value\x18\x02 \x01(\x01H\x00R\n" +
+	"floatValueB\a\n" +
+	"\x05value\"Q\n" +
+	"\n" +
+	"TypedValue\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\fR\x05value\x12-\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x19.ampkv.AmpKVDataTypeProtoR\x04type\"%\n" +
+	"\rCountResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\"b\n" +
+	"\x0fListPushRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12)\n" +
+	"\x06values\x18\x02 \x03(\v2\x11.ampkv.TypedValueR\x06values\x12\x12\n" +
+	"\x04left\x18\x03 \x01(\bR\x04left\"6\n" +
+	"\x0eListPopRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
+	"\x04left\x18\x02 \x01(\bR\x04left\"P\n" +
+	"\x0fListPopResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12'\n" +
+	"\x05value\x18\x02 \x01(\v2\x11.ampkv.TypedValueR\x05value\"N\n" +
+	"\x10ListRangeRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\x03R\x05start\x12\x12\n" +
+	"\x04stop\x18\x03 \x01(\x03R\x04stop\">\n" +
+	"\x11ListRangeResponse\x12)\n" +
+	"\x06values\x18\x01 \x03(\v2\x11.ampkv.TypedValueR\x06values\";\n" +
+	"\rSetAddRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x18\n" +
+	"\amembers\x18\x02 \x03(\tR\amembers\">\n" +
+	"\x10SetRemoveRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x18\n" +
+	"\amembers\x18\x02 \x03(\tR\amembers\"%\n" +
+	"\x11SetMembersRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\".\n" +
+	"\x12SetMembersResponse\x12\x18\n" +
+	"\amembers\x18\x01 \x03(\tR\amembers\"a\n" +
+	"\x0eHashSetRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05field\x18\x02 \x01(\tR\x05field\x12'\n" +
+	"\x05value\x18\x03 \x01(\v2\x11.ampkv.TypedValueR\x05value\"+\n" +
+	"\x0fHashSetResponse\x12\x18\n" +
+	"\acreated\x18\x01 \x01(\bR\acreated\"8\n" +
+	"\x0eHashGetRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05field\x18\x02 \x01(\tR\x05field\"P\n" +
+	"\x0fHashGetResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12'\n" +
+	"\x05value\x18\x02 \x01(\v2\x11.ampkv.TypedValueR\x05value\"=\n" +
+	"\x11HashDeleteRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06fields\x18\x02 \x03(\tR\x06fields\"%\n" +
+	"\x11HashGetAllRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"\xa1\x01\n" +
+	"\x12HashGetAllResponse\x12=\n" +
+	"\x06fields\x18\x01 \x03(\v2%.ampkv.HashGetAllResponse.FieldsEntryR\x06fields\x1aL\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12'\n" +
+	"\x05value\x18\x02 \x01(\v2\x11.ampkv.TypedValueR\x05value:\x028\x01\"<\n" +
+	"\fScoredMember\x12\x16\n" +
+	"\x06member\x18\x01 \x01(\tR\x06member\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\"V\n" +
+	"\x13SortedSetAddRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12-\n" +
+	"\amembers\x18\x02 \x03(\v2\x13.ampkv.ScoredMemberR\amembers\"D\n" +
+	"\x16SortedSetRemoveRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x18\n" +
+	"\amembers\x18\x02 \x03(\tR\amembers\"c\n" +
+	"\x15SortedSetRangeRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x10\n" +
+	"\x03min\x18\x02 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\x03 \x01(\x01R\x03max\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"G\n" +
+	"\x16SortedSetRangeResponse\x12-\n" +
+	"\amembers\x18\x01 \x03(\v2\x13.ampkv.ScoredMemberR\amembers\"_\n" +
+	"\fWatchRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\bR\x06prefix\x12%\n" +
+	"\x0estart_revision\x18\x03 \x01(\x04R\rstartRevision\"w\n" +
+	"\rWatchResponse\x12)\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x15.ampkv.WatchEventTypeR\x04type\x12\x1f\n" +
+	"\x02kv\x18\x02 \x01(\v2\x0f.ampkv.KeyValueR\x02kv\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x04R\brevision\"G\n" +
+	"\x11OperationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"G\n" +
+	"\vApiKeyScope\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12 \n" +
+	"\vpermissions\x18\x02 \x03(\tR\vpermissions\"\x92\x01\n" +
+	"\fApiKeyLimits\x12.\n" +
+	"\x13requests_per_second\x18\x01 \x01(\x01R\x11requestsPerSecond\x12\x14\n" +
+	"\x05burst\x18\x02 \x01(\x05R\x05burst\x12\x1b\n" +
+	"\tdaily_ops\x18\x03 \x01(\x03R\bdailyOps\x12\x1f\n" +
+	"\vdaily_bytes\x18\x04 \x01(\x03R\n" +
+	"dailyBytes\"\x9d\x02\n" +
+	"\n" +
+	"ApiKeyInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vpermissions\x18\x03 \x03(\tR\vpermissions\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\x03R\texpiresAt\x12\x1a\n" +
+	"\bdisabled\x18\x06 \x01(\bR\bdisabled\x12\x16\n" +
+	"\x06legacy\x18\a \x01(\bR\x06legacy\x12*\n" +
+	"\x06scopes\x18\b \x03(\v2\x12.ampkv.ApiKeyScopeR\x06scopes\x12+\n" +
+	"\x06limits\x18\t \x01(\v2\x13.ampkv.ApiKeyLimitsR\x06limits\"\xb4\x01\n" +
+	"\x13CreateApiKeyRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vpermissions\x18\x02 \x03(\tR\vpermissions\x12\x1a\n" +
+	"\bdisabled\x18\x03 \x01(\bR\bdisabled\x12\x1f\n" +
+	"\vttl_seconds\x18\x04 \x01(\x03R\n" +
+	"ttlSeconds\x12*\n" +
+	"\x06scopes\x18\x05 \x03(\v2\x12.ampkv.ApiKeyScopeR\x06scopes\"P\n" +
+	"\x13ApiKeyTokenResponse\x12#\n" +
+	"\x03key\x18\x01 \x01(\v2\x11.ampkv.ApiKeyInfoR\x03key\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"B\n" +
+	"\x12ListApiKeysRequest\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"]\n" +
+	"\x13ListApiKeysResponse\x12%\n" +
+	"\x04keys\x18\x01 \x03(\v2\x11.ampkv.ApiKeyInfoR\x04keys\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"!\n" +
+	"\x0fApiKeyIdRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"F\n" +
+	"\x18SetApiKeyDisabledRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bdisabled\x18\x02 \x01(\bR\bdisabled\"U\n" +
+	"\x16SetApiKeyLimitsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12+\n" +
+	"\x06limits\x18\x02 \x01(\v2\x13.ampkv.ApiKeyLimitsR\x06limits\"K\n" +
+	"\x1aSetApiKeyExpirationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\"\xb5\x01\n" +
+	"\n" +
+	"AuditEntry\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\x03R\x04time\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\x12\x1a\n" +
+	"\bprotocol\x18\x03 \x01(\tR\bprotocol\x12\x1c\n" +
+	"\toperation\x18\x04 \x01(\tR\toperation\x12\x12\n" +
+	"\x04keys\x18\x05 \x03(\tR\x04keys\x12\x16\n" +
+	"\x06result\x18\x06 \x01(\tR\x06result\x12\x16\n" +
+	"\x06client\x18\a \x01(\tR\x06client\"\x7f\n" +
+	"\x14QueryAuditLogRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"e\n" +
+	"\x15QueryAuditLogResponse\x12+\n" +
+	"\aentries\x18\x01 \x03(\v2\x11.ampkv.AuditEntryR\aentries\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor*\xc9\x02\n" +
+	"\x12AmpKVDataTypeProto\x12\x1c\n" +
+	"\x18AMP_KV_DATA_TYPE_UNKNOWN\x10\x00\x12\x1b\n" +
+	"\x17AMP_KV_DATA_TYPE_STRING\x10\x01\x12\x18\n" +
+	"\x14AMP_KV_DATA_TYPE_INT\x10\x02\x12\x1a\n" +
+	"\x16AMP_KV_DATA_TYPE_FLOAT\x10\x03\x12\x19\n" +
+	"\x15AMP_KV_DATA_TYPE_BOOL\x10\x04\x12\x19\n" +
+	"\x15AMP_KV_DATA_TYPE_JSON\x10\x05\x12\x1b\n" +
+	"\x17AMP_KV_DATA_TYPE_BINARY\x10\x06\x12\x19\n" +
+	"\x15AMP_KV_DATA_TYPE_LIST\x10\a\x12\x18\n" +
+	"\x14AMP_KV_DATA_TYPE_SET\x10\b\x12\x19\n" +
+	"\x15AMP_KV_DATA_TYPE_HASH\x10\t\x12\x1f\n" +
+	"\x1bAMP_KV_DATA_TYPE_SORTED_SET\x10\n" +
+	"*`\n" +
+	"\rCompareTarget\x12\x19\n" +
+	"\x15COMPARE_TARGET_EXISTS\x10\x00\x12\x18\n" +
+	"\x14COMPARE_TARGET_VALUE\x10\x01\x12\x1a\n" +
+	"\x16COMPARE_TARGET_VERSION\x10\x02*|\n" +
+	"\rCompareResult\x12\x18\n" +
+	"\x14COMPARE_RESULT_EQUAL\x10\x00\x12\x1c\n" +
+	"\x18COMPARE_RESULT_NOT_EQUAL\x10\x01\x12\x1a\n" +
+	"\x16COMPARE_RESULT_GREATER\x10\x02\x12\x17\n" +
+	"\x13COMPARE_RESULT_LESS\x10\x03*\x82\x01\n" +
+	"\x0eWatchEventType\x12\x1c\n" +
+	"\x18WATCH_EVENT_TYPE_UNKNOWN\x10\x00\x12\x18\n" +
+	"\x14WATCH_EVENT_TYPE_PUT\x10\x01\x12\x1b\n" +
+	"\x17WATCH_EVENT_TYPE_DELETE\x10\x02\x12\x1b\n" +
+	"\x17WATCH_EVENT_TYPE_EXPIRE\x10\x032\xb2\r\n" +
+	"\fAmpKVService\x12,\n" +
+	"\x03Get\x12\x11.ampkv.GetRequest\x1a\x12.ampkv.GetResponse\x122\n" +
+	"\x03Set\x12\x11.ampkv.SetRequest\x1a\x18.ampkv.OperationResponse\x12@\n" +
+	"\n" +
+	"SetWithTTL\x12\x18.ampkv.SetWithTTLRequest\x1a\x18.ampkv.OperationResponse\x128\n" +
+	"\x06Delete\x12\x14.ampkv.DeleteRequest\x1a\x18.ampkv.OperationResponse\x121\n" +
+	"\x04Scan\x12\x12.ampkv.ScanRequest\x1a\x13.ampkv.ScanResponse0\x01\x12,\n" +
+	"\x03Txn\x12\x11.ampkv.TxnRequest\x1a\x12.ampkv.TxnResponse\x12M\n" +
+	"\x0eCompareAndSwap\x12\x1c.ampkv.CompareAndSwapRequest\x1a\x1d.ampkv.CompareAndSwapResponse\x12;\n" +
+	"\bMultiGet\x12\x16.ampkv.MultiGetRequest\x1a\x17.ampkv.MultiGetResponse\x12;\n" +
+	"\bMultiSet\x12\x16.ampkv.MultiSetRequest\x1a\x17.ampkv.MultiSetResponse\x12B\n" +
+	"\vMultiDelete\x12\x19.ampkv.MultiDeleteRequest\x1a\x18.ampkv.OperationResponse\x12,\n" +
+	"\x03TTL\x12\x11.ampkv.TTLRequest\x1a\x12.ampkv.TTLResponse\x128\n" +
+	"\x06Expire\x12\x14.ampkv.ExpireRequest\x1a\x18.ampkv.OperationResponse\x12:\n" +
+	"\aPersist\x12\x15.ampkv.PersistRequest\x1a\x18.ampkv.OperationResponse\x12>\n" +
+	"\tIncrement\x12\x17.ampkv.IncrementRequest\x1a\x18.ampkv.IncrementResponse\x128\n" +
+	"\bListPush\x12\x16.ampkv.ListPushRequest\x1a\x14.ampkv.CountResponse\x128\n" +
+	"\aListPop\x12\x15.ampkv.ListPopRequest\x1a\x16.ampkv.ListPopResponse\x12>\n" +
+	"\tListRange\x12\x17.ampkv.ListRangeRequest\x1a\x18.ampkv.ListRangeResponse\x124\n" +
+	"\x06SetAdd\x12\x14.ampkv.SetAddRequest\x1a\x14.ampkv.CountResponse\x12:\n" +
+	"\tSetRemove\x12\x17.ampkv.SetRemoveRequest\x1a\x14.ampkv.CountResponse\x12A\n" +
+	"\n" +
+	"SetMembers\x12\x18.ampkv.SetMembersRequest\x1a\x19.ampkv.SetMembersResponse\x128\n" +
+	"\aHashSet\x12\x15.ampkv.HashSetRequest\x1a\x16.ampkv.HashSetResponse\x128\n" +
+	"\aHashGet\x12\x15.ampkv.HashGetRequest\x1a\x16.ampkv.HashGetResponse\x12<\n" +
+	"\n" +
+	"HashDelete\x12\x18.ampkv.HashDeleteRequest\x1a\x14.ampkv.CountResponse\x12A\n" +
+	"\n" +
+	"HashGetAll\x12\x18.ampkv.HashGetAllRequest\x1a\x19.ampkv.HashGetAllResponse\x12@\n" +
+	"\fSortedSetAdd\x12\x1a.ampkv.SortedSetAddRequest\x1a\x14.ampkv.CountResponse\x12F\n" +
+	"\x0fSortedSetRemove\x12\x1d.ampkv.SortedSetRemoveRequest\x1a\x14.ampkv.CountResponse\x12T\n" +
+	"\x15SortedSetRangeByScore\x12\x1c.ampkv.SortedSetRangeRequest\x1a\x1d.ampkv.SortedSetRangeResponse\x124\n" +
+	"\x05Watch\x12\x13.ampkv.WatchRequest\x1a\x14.ampkv.WatchResponse0\x012\x81\x05\n" +
+	"\fAdminService\x12F\n" +
+	"\fCreateApiKey\x12\x1a.ampkv.CreateApiKeyRequest\x1a\x1a.ampkv.ApiKeyTokenResponse\x12D\n" +
+	"\vListApiKeys\x12\x19.ampkv.ListApiKeysRequest\x1a\x1a.ampkv.ListApiKeysResponse\x126\n" +
+	"\tGetApiKey\x12\x16.ampkv.ApiKeyIdRequest\x1a\x11.ampkv.ApiKeyInfo\x12G\n" +
+	"\x11SetApiKeyDisabled\x12\x1f.ampkv.SetApiKeyDisabledRequest\x1a\x11.ampkv.ApiKeyInfo\x12K\n" +
+	"\x13SetApiKeyExpiration\x12!.ampkv.SetApiKeyExpirationRequest\x1a\x11.ampkv.ApiKeyInfo\x12C\n" +
+	"\x0fSetApiKeyLimits\x12\x1d.ampkv.SetApiKeyLimitsRequest\x1a\x11.ampkv.ApiKeyInfo\x12B\n" +
+	"\fRotateApiKey\x12\x16.ampkv.ApiKeyIdRequest\x1a\x1a.ampkv.ApiKeyTokenResponse\x12@\n" +
+	"\fDeleteApiKey\x12\x16.ampkv.ApiKeyIdRequest\x1a\x18.ampkv.OperationResponse\x12J\n" +
+	"\rQueryAuditLog\x12\x1b.ampkv.QueryAuditLogRequest\x1a\x1c.ampkv.QueryAuditLogResponseB)Z'github.com/Unfield/AmpKV/pkg/client/rpcb\x06proto3"
+
+var (
+	file_ampkv_proto_rawDescOnce sync.Once
+	file_ampkv_proto_rawDescData []byte
+)
+
+func file_ampkv_proto_rawDescGZIP() []byte {
+	file_ampkv_proto_rawDescOnce.Do(func() {
+		file_ampkv_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ampkv_proto_rawDesc), len(file_ampkv_proto_rawDesc)))
+	})
+	return file_ampkv_proto_rawDescData
+}
+
+var file_ampkv_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_ampkv_proto_msgTypes = make([]protoimpl.MessageInfo, 68)
+var file_ampkv_proto_goTypes = []any{
+	(AmpKVDataTypeProto)(0),            // 0: ampkv.AmpKVDataTypeProto
+	(CompareTarget)(0),                 // 1: ampkv.CompareTarget
+	(CompareResult)(0),                 // 2: ampkv.CompareResult
+	(WatchEventType)(0),                // 3: ampkv.WatchEventType
+	(*KeyValue)(nil),                   // 4: ampkv.KeyValue
+	(*GetRequest)(nil),                 // 5: ampkv.GetRequest
+	(*GetResponse)(nil),                // 6: ampkv.GetResponse
+	(*SetRequest)(nil),                 // 7: ampkv.SetRequest
+	(*SetWithTTLRequest)(nil),          // 8: ampkv.SetWithTTLRequest
+	(*DeleteRequest)(nil),              // 9: ampkv.DeleteRequest
+	(*ScanRequest)(nil),                // 10: ampkv.ScanRequest
+	(*ScanResponse)(nil),               // 11: ampkv.ScanResponse
+	(*Compare)(nil),                    // 12: ampkv.Compare
+	(*PutOp)(nil),                      // 13: ampkv.PutOp
+	(*DeleteOp)(nil),                   // 14: ampkv.DeleteOp
+	(*TxnOp)(nil),                      // 15: ampkv.TxnOp
+	(*TxnRequest)(nil),                 // 16: ampkv.TxnRequest
+	(*TxnResponse)(nil),                // 17: ampkv.TxnResponse
+	(*CompareAndSwapRequest)(nil),      // 18: ampkv.CompareAndSwapRequest
+	(*CompareAndSwapResponse)(nil),     // 19: ampkv.CompareAndSwapResponse
+	(*MultiGetRequest)(nil),            // 20: ampkv.MultiGetRequest
+	(*MultiGetResponse)(nil),           // 21: ampkv.MultiGetResponse
+	(*MultiSetRequest)(nil),            // 22: ampkv.MultiSetRequest
+	(*MultiSetResponse)(nil),           // 23: ampkv.MultiSetResponse
+	(*MultiDeleteRequest)(nil),         // 24: ampkv.MultiDeleteRequest
+	(*TTLRequest)(nil),                 // 25: ampkv.TTLRequest
+	(*TTLResponse)(nil),                // 26: ampkv.TTLResponse
+	(*ExpireRequest)(nil),              // 27: ampkv.ExpireRequest
+	(*PersistRequest)(nil),             // 28: ampkv.PersistRequest
+	(*IncrementRequest)(nil),           // 29: ampkv.IncrementRequest
+	(*IncrementResponse)(nil),          // 30: ampkv.IncrementResponse
+	(*TypedValue)(nil),                 // 31: ampkv.TypedValue
+	(*CountResponse)(nil),              // 32: ampkv.CountResponse
+	(*ListPushRequest)(nil),            // 33: ampkv.ListPushRequest
+	(*ListPopRequest)(nil),             // 34: ampkv.ListPopRequest
+	(*ListPopResponse)(nil),            // 35: ampkv.ListPopResponse
+	(*ListRangeRequest)(nil),           // 36: ampkv.ListRangeRequest
+	(*ListRangeResponse)(nil),          // 37: ampkv.ListRangeResponse
+	(*SetAddRequest)(nil),              // 38: ampkv.SetAddRequest
+	(*SetRemoveRequest)(nil),           // 39: ampkv.SetRemoveRequest
+	(*SetMembersRequest)(nil),          // 40: ampkv.SetMembersRequest
+	(*SetMembersResponse)(nil),         // 41: ampkv.SetMembersResponse
+	(*HashSetRequest)(nil),             // 42: ampkv.HashSetRequest
+	(*HashSetResponse)(nil),            // 43: ampkv.HashSetResponse
+	(*HashGetRequest)(nil),             // 44: ampkv.HashGetRequest
+	(*HashGetResponse)(nil),            // 45: ampkv.HashGetResponse
+	(*HashDeleteRequest)(nil),          // 46: ampkv.HashDeleteRequest
+	(*HashGetAllRequest)(nil),          // 47: ampkv.HashGetAllRequest
+	(*HashGetAllResponse)(nil),         // 48: ampkv.HashGetAllResponse
+	(*ScoredMember)(nil),               // 49: ampkv.ScoredMember
+	(*SortedSetAddRequest)(nil),        // 50: ampkv.SortedSetAddRequest
+	(*SortedSetRemoveRequest)(nil),     // 51: ampkv.SortedSetRemoveRequest
+	(*SortedSetRangeRequest)(nil),      // 52: ampkv.SortedSetRangeRequest
+	(*SortedSetRangeResponse)(nil),     // 53: ampkv.SortedSetRangeResponse
+	(*WatchRequest)(nil),               // 54: ampkv.WatchRequest
+	(*WatchResponse)(nil),              // 55: ampkv.WatchResponse
+	(*OperationResponse)(nil),          // 56: ampkv.OperationResponse
+	(*ApiKeyScope)(nil),                // 57: ampkv.ApiKeyScope
+	(*ApiKeyLimits)(nil),               // 58: ampkv.ApiKeyLimits
+	(*ApiKeyInfo)(nil),                 // 59: ampkv.ApiKeyInfo
+	(*CreateApiKeyRequest)(nil),        // 60: ampkv.CreateApiKeyRequest
+	(*ApiKeyTokenResponse)(nil),        // 61: ampkv.ApiKeyTokenResponse
+	(*ListApiKeysRequest)(nil),         // 62: ampkv.ListApiKeysRequest
+	(*ListApiKeysResponse)(nil),        // 63: ampkv.ListApiKeysResponse
+	(*ApiKeyIdRequest)(nil),            // 64: ampkv.ApiKeyIdRequest
+	(*SetApiKeyDisabledRequest)(nil),   // 65: ampkv.SetApiKeyDisabledRequest
+	(*SetApiKeyLimitsRequest)(nil),     // 66: ampkv.SetApiKeyLimitsRequest
+	(*SetApiKeyExpirationRequest)(nil), // 67: ampkv.SetApiKeyExpirationRequest
+	(*AuditEntry)(nil),                 // 68: ampkv.AuditEntry
+	(*QueryAuditLogRequest)(nil),       // 69: ampkv.QueryAuditLogRequest
+	(*QueryAuditLogResponse)(nil),      // 70: ampkv.QueryAuditLogResponse
+	nil,                                // 71: ampkv.HashGetAllResponse.FieldsEntry
+}
+var file_ampkv_proto_depIdxs = []int32{
+	0,  // 0: ampkv.KeyValue.type:type_name -> ampkv.AmpKVDataTypeProto
+	4,  // 1: ampkv.GetResponse.kv:type_name -> ampkv.KeyValue
+	4,  // 2: ampkv.SetRequest.kv:type_name -> ampkv.KeyValue
+	4,  // 3: ampkv.SetWithTTLRequest.kv:type_name -> ampkv.KeyValue
+	4,  // 4: ampkv.ScanResponse.kv:type_name -> ampkv.KeyValue
+	1,  // 5: ampkv.Compare.target:type_name -> ampkv.CompareTarget
+	2,  // 6: ampkv.Compare.result:type_name -> ampkv.CompareResult
+	4,  // 7: ampkv.PutOp.kv:type_name -> ampkv.KeyValue
+	13, // 8: ampkv.TxnOp.put:type_name -> ampkv.PutOp
+	14, // 9: ampkv.TxnOp.delete:type_name -> ampkv.DeleteOp
+	12, // 10: ampkv.TxnRequest.compare:type_name -> ampkv.Compare
+	15, // 11: ampkv.TxnRequest.success:type_name -> ampkv.TxnOp
+	15, // 12: ampkv.TxnRequest.failure:type_name -> ampkv.TxnOp
+	4,  // 13: ampkv.CompareAndSwapRequest.kv:type_name -> ampkv.KeyValue
+	6,  // 14: ampkv.MultiGetResponse.results:type_name -> ampkv.GetResponse
+	13, // 15: ampkv.MultiSetRequest.items:type_name -> ampkv.PutOp
+	0,  // 16: ampkv.TypedValue.type:type_name -> ampkv.AmpKVDataTypeProto
+	31, // 17: ampkv.ListPushRequest.values:type_name -> ampkv.TypedValue
+	31, // 18: ampkv.ListPopResponse.value:type_name -> ampkv.TypedValue
+	31, // 19: ampkv.ListRangeResponse.values:type_name -> ampkv.TypedValue
+	31, // 20: ampkv.HashSetRequest.value:type_name -> ampkv.TypedValue
+	31, // 21: ampkv.HashGetResponse.value:type_name -> ampkv.TypedValue
+	71, // 22: ampkv.HashGetAllResponse.fields:type_name -> ampkv.HashGetAllResponse.FieldsEntry
+	49, // 23: ampkv.SortedSetAddRequest.members:type_name -> ampkv.ScoredMember
+	49, // 24: ampkv.SortedSetRangeResponse.members:type_name -> ampkv.ScoredMember
+	3,  // 25: ampkv.WatchResponse.type:type_name -> ampkv.WatchEventType
+	4,  // 26: ampkv.WatchResponse.kv:type_name -> ampkv.KeyValue
+	57, // 27: ampkv.ApiKeyInfo.scopes:type_name -> ampkv.ApiKeyScope
+	58, // 28: ampkv.ApiKeyInfo.limits:type_name -> ampkv.ApiKeyLimits
+	57, // 29: ampkv.CreateApiKeyRequest.scopes:type_name -> ampkv.ApiKeyScope
+	59, // 30: ampkv.ApiKeyTokenResponse.key:type_name -> ampkv.ApiKeyInfo
+	59, // 31: ampkv.ListApiKeysResponse.keys:type_name -> ampkv.ApiKeyInfo
+	58, // 32: ampkv.SetApiKeyLimitsRequest.limits:type_name -> ampkv.ApiKeyLimits
+	68, // 33: ampkv.QueryAuditLogResponse.entries:type_name -> ampkv.AuditEntry
+	31, // 34: ampkv.HashGetAllResponse.FieldsEntry.value:type_name -> ampkv.TypedValue
+	5,  // 35: ampkv.AmpKVService.Get:input_type -> ampkv.GetRequest
+	7,  // 36: ampkv.AmpKVService.Set:input_type -> ampkv.SetRequest
+	8,  // 37: ampkv.AmpKVService.SetWithTTL:input_type -> ampkv.SetWithTTLRequest
+	9,  // 38: ampkv.AmpKVService.Delete:input_type -> ampkv.DeleteRequest
+	10, // 39: ampkv.AmpKVService.Scan:input_type -> ampkv.ScanRequest
+	16, // 40: ampkv.AmpKVService.Txn:input_type -> ampkv.TxnRequest
+	18, // 41: ampkv.AmpKVService.CompareAndSwap:input_type -> ampkv.CompareAndSwapRequest
+	20, // 42: ampkv.AmpKVService.MultiGet:input_type -> ampkv.MultiGetRequest
+	22, // 43: ampkv.AmpKVService.MultiSet:input_type -> ampkv.MultiSetRequest
+	24, // 44: ampkv.AmpKVService.MultiDelete:input_type -> ampkv.MultiDeleteRequest
+	25, // 45: ampkv.AmpKVService.TTL:input_type -> ampkv.TTLRequest
+	27, // 46: ampkv.AmpKVService.Expire:input_type -> ampkv.ExpireRequest
+	28, // 47: ampkv.AmpKVService.Persist:input_type -> ampkv.PersistRequest
+	29, // 48: ampkv.AmpKVService.Increment:input_type -> ampkv.IncrementRequest
+	33, // 49: ampkv.AmpKVService.ListPush:input_type -> ampkv.ListPushRequest
+	34, // 50: ampkv.AmpKVService.ListPop:input_type -> ampkv.ListPopRequest
+	36, // 51: ampkv.AmpKVService.ListRange:input_type -> ampkv.ListRangeRequest
+	38, // 52: ampkv.AmpKVService.SetAdd:input_type -> ampkv.SetAddRequest
+	39, // 53: ampkv.AmpKVService.SetRemove:input_type -> ampkv.SetRemoveRequest
+	40, // 54: ampkv.AmpKVService.SetMembers:input_type -> ampkv.SetMembersRequest
+	42, // 55: ampkv.AmpKVService.HashSet:input_type -> ampkv.HashSetRequest
+	44, // 56: ampkv.AmpKVService.HashGet:input_type -> ampkv.HashGetRequest
+	46, // 57: ampkv.AmpKVService.HashDelete:input_type -> ampkv.HashDeleteRequest
+	47, // 58: ampkv.AmpKVService.HashGetAll:input_type -> ampkv.HashGetAllRequest
+	50, // 59: ampkv.AmpKVService.SortedSetAdd:input_type -> ampkv.SortedSetAddRequest
+	51, // 60: ampkv.AmpKVService.SortedSetRemove:input_type -> ampkv.SortedSetRemoveRequest
+	52, // 61: ampkv.AmpKVService.SortedSetRangeByScore:input_type -> ampkv.SortedSetRangeRequest
+	54, // 62: ampkv.AmpKVService.Watch:input_type -> ampkv.WatchRequest
+	60, // 63: ampkv.AdminService.CreateApiKey:input_type -> ampkv.CreateApiKeyRequest
+	62, // 64: ampkv.AdminService.ListApiKeys:input_type -> ampkv.ListApiKeysRequest
+	64, // 65: ampkv.AdminService.GetApiKey:input_type -> ampkv.ApiKeyIdRequest
+	65, // 66: ampkv.AdminService.SetApiKeyDisabled:input_type -> ampkv.SetApiKeyDisabledRequest
+	67, // 67: ampkv.AdminService.SetApiKeyExpiration:input_type -> ampkv.SetApiKeyExpirationRequest
+	66, // 68: ampkv.AdminService.SetApiKeyLimits:input_type -> ampkv.SetApiKeyLimitsRequest
+	64, // 69: ampkv.AdminService.RotateApiKey:input_type -> ampkv.ApiKeyIdRequest
+	64, // 70: ampkv.AdminService.DeleteApiKey:input_type -> ampkv.ApiKeyIdRequest
+	69, // 71: ampkv.AdminService.QueryAuditLog:input_type -> ampkv.QueryAuditLogRequest
+	6,  // 72: ampkv.AmpKVService.Get:output_type -> ampkv.GetResponse
+	56, // 73: ampkv.AmpKVService.Set:output_type -> ampkv.OperationResponse
+	56, // 74: ampkv.AmpKVService.SetWithTTL:output_type -> ampkv.OperationResponse
+	56, // 75: ampkv.AmpKVService.Delete:output_type -> ampkv.OperationResponse
+	11, // 76: ampkv.AmpKVService.Scan:output_type -> ampkv.ScanResponse
+	17, // 77: ampkv.AmpKVService.Txn:output_type -> ampkv.TxnResponse
+	19, // 78: ampkv.AmpKVService.CompareAndSwap:output_type -> ampkv.CompareAndSwapResponse
+	21, // 79: ampkv.AmpKVService.MultiGet:output_type -> ampkv.MultiGetResponse
+	23, // 80: ampkv.AmpKVService.MultiSet:output_type -> ampkv.MultiSetResponse
+	56, // 81: ampkv.AmpKVService.MultiDelete:output_type -> ampkv.OperationResponse
+	26, // 82: ampkv.AmpKVService.TTL:output_type -> ampkv.TTLResponse
+	56, // 83: ampkv.AmpKVService.Expire:output_type -> ampkv.OperationResponse
+	56, // 84: ampkv.AmpKVService.Persist:output_type -> ampkv.OperationResponse
+	30, // 85: ampkv.AmpKVService.Increment:output_type -> ampkv.IncrementResponse
+	32, // 86: ampkv.AmpKVService.ListPush:output_type -> ampkv.CountResponse
+	35, // 87: ampkv.AmpKVService.ListPop:output_type -> ampkv.ListPopResponse
+	37, // 88: ampkv.AmpKVService.ListRange:output_type -> ampkv.ListRangeResponse
+	32, // 89: ampkv.AmpKVService.SetAdd:output_type -> ampkv.CountResponse
+	32, // 90: ampkv.AmpKVService.SetRemove:output_type -> ampkv.CountResponse
+	41, // 91: ampkv.AmpKVService.SetMembers:output_type -> ampkv.SetMembersResponse
+	43, // 92: ampkv.AmpKVService.HashSet:output_type -> ampkv.HashSetResponse
+	45, // 93: ampkv.AmpKVService.HashGet:output_type -> ampkv.HashGetResponse
+	32, // 94: ampkv.AmpKVService.HashDelete:output_type -> ampkv.CountResponse
+	48, // 95: ampkv.AmpKVService.HashGetAll:output_type -> ampkv.HashGetAllResponse
+	32, // 96: ampkv.AmpKVService.SortedSetAdd:output_type -> ampkv.CountResponse
+	32, // 97: ampkv.AmpKVService.SortedSetRemove:output_type -> ampkv.CountResponse
+	53, // 98: ampkv.AmpKVService.SortedSetRangeByScore:output_type -> ampkv.SortedSetRangeResponse
+	55, // 99: ampkv.AmpKVService.Watch:output_type -> ampkv.WatchResponse
+	61, // 100: ampkv.AdminService.CreateApiKey:output_type -> ampkv.ApiKeyTokenResponse
+	63, // 101: ampkv.AdminService.ListApiKeys:output_type -> ampkv.ListApiKeysResponse
+	59, // 102: ampkv.AdminService.GetApiKey:output_type -> ampkv.ApiKeyInfo
+	59, // 103: ampkv.AdminService.SetApiKeyDisabled:output_type -> ampkv.ApiKeyInfo
+	59, // 104: ampkv.AdminService.SetApiKeyExpiration:output_type -> ampkv.ApiKeyInfo
+	59, // 105: ampkv.AdminService.SetApiKeyLimits:output_type -> ampkv.ApiKeyInfo
+	61, // 106: ampkv.AdminService.RotateApiKey:output_type -> ampkv.ApiKeyTokenResponse
+	56, // 107: ampkv.AdminService.DeleteApiKey:output_type -> ampkv.OperationResponse
+	70, // 108: ampkv.AdminService.QueryAuditLog:output_type -> ampkv.QueryAuditLogResponse
+	72, // [72:109] is the sub-list for method output_type
+	35, // [35:72] is the sub-list for method input_type
+	35, // [35:35] is the sub-list for extension type_name
+	35, // [35:35] is the sub-list for extension extendee
+	0,  // [0:35] is the sub-list for field type_name
+}
+
+func init() { file_ampkv_proto_init() }
+func file_ampkv_proto_init() {
+	if File_ampkv_proto != nil {
+		return
+	}
+	file_ampkv_proto_msgTypes[11].OneofWrappers = []any{
+		(*TxnOp_Put)(nil),
+		(*TxnOp_Delete)(nil),
+	}
+	file_ampkv_proto_msgTypes[23].OneofWrappers = []any{
+		(*ExpireRequest_TtlSeconds)(nil),
+		(*ExpireRequest_ExpiresAt)(nil),
+	}
+	file_ampkv_proto_msgTypes[25].OneofWrappers = []any{
+		(*IncrementRequest_IntDelta)(nil),
+		(*IncrementRequest_FloatDelta)(nil),
+	}
+	file_ampkv_proto_msgTypes[26].OneofWrappers = []any{
+		(*IncrementResponse_IntValue)(nil),
+		(*IncrementResponse_FloatValue)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ampkv_proto_rawDesc), len(file_ampkv_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   7,
+			NumEnums:      4,
+			NumMessages:   68,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_ampkv_proto_goTypes,
 		DependencyIndexes: file_ampkv_proto_depIdxs,
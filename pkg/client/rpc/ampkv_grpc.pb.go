@@ -19,10 +19,34 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AmpKVService_Get_FullMethodName        = "/ampkv.AmpKVService/Get"
-	AmpKVService_Set_FullMethodName        = "/ampkv.AmpKVService/Set"
-	AmpKVService_SetWithTTL_FullMethodName = "/ampkv.AmpKVService/SetWithTTL"
-	AmpKVService_Delete_FullMethodName     = "/ampkv.AmpKVService/Delete"
+	AmpKVService_Get_FullMethodName                   = "/ampkv.AmpKVService/Get"
+	AmpKVService_Set_FullMethodName                   = "/ampkv.AmpKVService/Set"
+	AmpKVService_SetWithTTL_FullMethodName            = "/ampkv.AmpKVService/SetWithTTL"
+	AmpKVService_Delete_FullMethodName                = "/ampkv.AmpKVService/Delete"
+	AmpKVService_Scan_FullMethodName                  = "/ampkv.AmpKVService/Scan"
+	AmpKVService_Txn_FullMethodName                   = "/ampkv.AmpKVService/Txn"
+	AmpKVService_CompareAndSwap_FullMethodName        = "/ampkv.AmpKVService/CompareAndSwap"
+	AmpKVService_MultiGet_FullMethodName              = "/ampkv.AmpKVService/MultiGet"
+	AmpKVService_MultiSet_FullMethodName              = "/ampkv.AmpKVService/MultiSet"
+	AmpKVService_MultiDelete_FullMethodName           = "/ampkv.AmpKVService/MultiDelete"
+	AmpKVService_TTL_FullMethodName                   = "/ampkv.AmpKVService/TTL"
+	AmpKVService_Expire_FullMethodName                = "/ampkv.AmpKVService/Expire"
+	AmpKVService_Persist_FullMethodName               = "/ampkv.AmpKVService/Persist"
+	AmpKVService_Increment_FullMethodName             = "/ampkv.AmpKVService/Increment"
+	AmpKVService_ListPush_FullMethodName              = "/ampkv.AmpKVService/ListPush"
+	AmpKVService_ListPop_FullMethodName               = "/ampkv.AmpKVService/ListPop"
+	AmpKVService_ListRange_FullMethodName             = "/ampkv.AmpKVService/ListRange"
+	AmpKVService_SetAdd_FullMethodName                = "/ampkv.AmpKVService/SetAdd"
+	AmpKVService_SetRemove_FullMethodName             = "/ampkv.AmpKVService/SetRemove"
+	AmpKVService_SetMembers_FullMethodName            = "/ampkv.AmpKVService/SetMembers"
+	AmpKVService_HashSet_FullMethodName               = "/ampkv.AmpKVService/HashSet"
+	AmpKVService_HashGet_FullMethodName               = "/ampkv.AmpKVService/HashGet"
+	AmpKVService_HashDelete_FullMethodName            = "/ampkv.AmpKVService/HashDelete"
+	AmpKVService_HashGetAll_FullMethodName            = "/ampkv.AmpKVService/HashGetAll"
+	AmpKVService_SortedSetAdd_FullMethodName          = "/ampkv.AmpKVService/SortedSetAdd"
+	AmpKVService_SortedSetRemove_FullMethodName       = "/ampkv.AmpKVService/SortedSetRemove"
+	AmpKVService_SortedSetRangeByScore_FullMethodName = "/ampkv.AmpKVService/SortedSetRangeByScore"
+	AmpKVService_Watch_FullMethodName                 = "/ampkv.AmpKVService/Watch"
 )
 
 // AmpKVServiceClient is the client API for AmpKVService service.
@@ -33,6 +57,32 @@ type AmpKVServiceClient interface {
 	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*OperationResponse, error)
 	SetWithTTL(ctx context.Context, in *SetWithTTLRequest, opts ...grpc.CallOption) (*OperationResponse, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*OperationResponse, error)
+	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ScanResponse], error)
+	Txn(ctx context.Context, in *TxnRequest, opts ...grpc.CallOption) (*TxnResponse, error)
+	CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, opts ...grpc.CallOption) (*CompareAndSwapResponse, error)
+	MultiGet(ctx context.Context, in *MultiGetRequest, opts ...grpc.CallOption) (*MultiGetResponse, error)
+	MultiSet(ctx context.Context, in *MultiSetRequest, opts ...grpc.CallOption) (*MultiSetResponse, error)
+	MultiDelete(ctx context.Context, in *MultiDeleteRequest, opts ...grpc.CallOption) (*OperationResponse, error)
+	TTL(ctx context.Context, in *TTLRequest, opts ...grpc.CallOption) (*TTLResponse, error)
+	Expire(ctx context.Context, in *ExpireRequest, opts ...grpc.CallOption) (*OperationResponse, error)
+	Persist(ctx context.Context, in *PersistRequest, opts ...grpc.CallOption) (*OperationResponse, error)
+	Increment(ctx context.Context, in *IncrementRequest, opts ...grpc.CallOption) (*IncrementResponse, error)
+	// Count responses hold the new length for ListPush and the number of
+	// members or fields added or removed otherwise.
+	ListPush(ctx context.Context, in *ListPushRequest, opts ...grpc.CallOption) (*CountResponse, error)
+	ListPop(ctx context.Context, in *ListPopRequest, opts ...grpc.CallOption) (*ListPopResponse, error)
+	ListRange(ctx context.Context, in *ListRangeRequest, opts ...grpc.CallOption) (*ListRangeResponse, error)
+	SetAdd(ctx context.Context, in *SetAddRequest, opts ...grpc.CallOption) (*CountResponse, error)
+	SetRemove(ctx context.Context, in *SetRemoveRequest, opts ...grpc.CallOption) (*CountResponse, error)
+	SetMembers(ctx context.Context, in *SetMembersRequest, opts ...grpc.CallOption) (*SetMembersResponse, error)
+	HashSet(ctx context.Context, in *HashSetRequest, opts ...grpc.CallOption) (*HashSetResponse, error)
+	HashGet(ctx context.Context, in *HashGetRequest, opts ...grpc.CallOption) (*HashGetResponse, error)
+	HashDelete(ctx context.Context, in *HashDeleteRequest, opts ...grpc.CallOption) (*CountResponse, error)
+	HashGetAll(ctx context.Context, in *HashGetAllRequest, opts ...grpc.CallOption) (*HashGetAllResponse, error)
+	SortedSetAdd(ctx context.Context, in *SortedSetAddRequest, opts ...grpc.CallOption) (*CountResponse, error)
+	SortedSetRemove(ctx context.Context, in *SortedSetRemoveRequest, opts ...grpc.CallOption) (*CountResponse, error)
+	SortedSetRangeByScore(ctx context.Context, in *SortedSetRangeRequest, opts ...grpc.CallOption) (*SortedSetRangeResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error)
 }
 
 type ampKVServiceClient struct {
@@ -83,6 +133,264 @@ func (c *ampKVServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts
 	return out, nil
 }
 
+func (c *ampKVServiceClient) Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ScanResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AmpKVService_ServiceDesc.Streams[0], AmpKVService_Scan_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ScanRequest, ScanResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AmpKVService_ScanClient = grpc.ServerStreamingClient[ScanResponse]
+
+func (c *ampKVServiceClient) Txn(ctx context.Context, in *TxnRequest, opts ...grpc.CallOption) (*TxnResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TxnResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_Txn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, opts ...grpc.CallOption) (*CompareAndSwapResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompareAndSwapResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_CompareAndSwap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) MultiGet(ctx context.Context, in *MultiGetRequest, opts ...grpc.CallOption) (*MultiGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MultiGetResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_MultiGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) MultiSet(ctx context.Context, in *MultiSetRequest, opts ...grpc.CallOption) (*MultiSetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MultiSetResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_MultiSet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) MultiDelete(ctx context.Context, in *MultiDeleteRequest, opts ...grpc.CallOption) (*OperationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OperationResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_MultiDelete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) TTL(ctx context.Context, in *TTLRequest, opts ...grpc.CallOption) (*TTLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TTLResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_TTL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) Expire(ctx context.Context, in *ExpireRequest, opts ...grpc.CallOption) (*OperationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OperationResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_Expire_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) Persist(ctx context.Context, in *PersistRequest, opts ...grpc.CallOption) (*OperationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OperationResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_Persist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) Increment(ctx context.Context, in *IncrementRequest, opts ...grpc.CallOption) (*IncrementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IncrementResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_Increment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) ListPush(ctx context.Context, in *ListPushRequest, opts ...grpc.CallOption) (*CountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_ListPush_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) ListPop(ctx context.Context, in *ListPopRequest, opts ...grpc.CallOption) (*ListPopResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPopResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_ListPop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) ListRange(ctx context.Context, in *ListRangeRequest, opts ...grpc.CallOption) (*ListRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRangeResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_ListRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) SetAdd(ctx context.Context, in *SetAddRequest, opts ...grpc.CallOption) (*CountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_SetAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) SetRemove(ctx context.Context, in *SetRemoveRequest, opts ...grpc.CallOption) (*CountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_SetRemove_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) SetMembers(ctx context.Context, in *SetMembersRequest, opts ...grpc.CallOption) (*SetMembersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetMembersResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_SetMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) HashSet(ctx context.Context, in *HashSetRequest, opts ...grpc.CallOption) (*HashSetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HashSetResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_HashSet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) HashGet(ctx context.Context, in *HashGetRequest, opts ...grpc.CallOption) (*HashGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HashGetResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_HashGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) HashDelete(ctx context.Context, in *HashDeleteRequest, opts ...grpc.CallOption) (*CountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_HashDelete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) HashGetAll(ctx context.Context, in *HashGetAllRequest, opts ...grpc.CallOption) (*HashGetAllResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HashGetAllResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_HashGetAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) SortedSetAdd(ctx context.Context, in *SortedSetAddRequest, opts ...grpc.CallOption) (*CountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_SortedSetAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) SortedSetRemove(ctx context.Context, in *SortedSetRemoveRequest, opts ...grpc.CallOption) (*CountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_SortedSetRemove_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) SortedSetRangeByScore(ctx context.Context, in *SortedSetRangeRequest, opts ...grpc.CallOption) (*SortedSetRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SortedSetRangeResponse)
+	err := c.cc.Invoke(ctx, AmpKVService_SortedSetRangeByScore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ampKVServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AmpKVService_ServiceDesc.Streams[1], AmpKVService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AmpKVService_WatchClient = grpc.ServerStreamingClient[WatchResponse]
+
 // AmpKVServiceServer is the server API for AmpKVService service.
 // All implementations must embed UnimplementedAmpKVServiceServer
 // for forward compatibility.
@@ -91,6 +399,32 @@ type AmpKVServiceServer interface {
 	Set(context.Context, *SetRequest) (*OperationResponse, error)
 	SetWithTTL(context.Context, *SetWithTTLRequest) (*OperationResponse, error)
 	Delete(context.Context, *DeleteRequest) (*OperationResponse, error)
+	Scan(*ScanRequest, grpc.ServerStreamingServer[ScanResponse]) error
+	Txn(context.Context, *TxnRequest) (*TxnResponse, error)
+	CompareAndSwap(context.Context, *CompareAndSwapRequest) (*CompareAndSwapResponse, error)
+	MultiGet(context.Context, *MultiGetRequest) (*MultiGetResponse, error)
+	MultiSet(context.Context, *MultiSetRequest) (*MultiSetResponse, error)
+	MultiDelete(context.Context, *MultiDeleteRequest) (*OperationResponse, error)
+	TTL(context.Context, *TTLRequest) (*TTLResponse, error)
+	Expire(context.Context, *ExpireRequest) (*OperationResponse, error)
+	Persist(context.Context, *PersistRequest) (*OperationResponse, error)
+	Increment(context.Context, *IncrementRequest) (*IncrementResponse, error)
+	// Count responses hold the new length for ListPush and the number of
+	// members or fields added or removed otherwise.
+	ListPush(context.Context, *ListPushRequest) (*CountResponse, error)
+	ListPop(context.Context, *ListPopRequest) (*ListPopResponse, error)
+	ListRange(context.Context, *ListRangeRequest) (*ListRangeResponse, error)
+	SetAdd(context.Context, *SetAddRequest) (*CountResponse, error)
+	SetRemove(context.Context, *SetRemoveRequest) (*CountResponse, error)
+	SetMembers(context.Context, *SetMembersRequest) (*SetMembersResponse, error)
+	HashSet(context.Context, *HashSetRequest) (*HashSetResponse, error)
+	HashGet(context.Context, *HashGetRequest) (*HashGetResponse, error)
+	HashDelete(context.Context, *HashDeleteRequest) (*CountResponse, error)
+	HashGetAll(context.Context, *HashGetAllRequest) (*HashGetAllResponse, error)
+	SortedSetAdd(context.Context, *SortedSetAddRequest) (*CountResponse, error)
+	SortedSetRemove(context.Context, *SortedSetRemoveRequest) (*CountResponse, error)
+	SortedSetRangeByScore(context.Context, *SortedSetRangeRequest) (*SortedSetRangeResponse, error)
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error
 	mustEmbedUnimplementedAmpKVServiceServer()
 }
 
@@ -113,6 +447,78 @@ func (UnimplementedAmpKVServiceServer) SetWithTTL(context.Context, *SetWithTTLRe
 func (UnimplementedAmpKVServiceServer) Delete(context.Context, *DeleteRequest) (*OperationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedAmpKVServiceServer) Scan(*ScanRequest, grpc.ServerStreamingServer[ScanResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Scan not implemented")
+}
+func (UnimplementedAmpKVServiceServer) Txn(context.Context, *TxnRequest) (*TxnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Txn not implemented")
+}
+func (UnimplementedAmpKVServiceServer) CompareAndSwap(context.Context, *CompareAndSwapRequest) (*CompareAndSwapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareAndSwap not implemented")
+}
+func (UnimplementedAmpKVServiceServer) MultiGet(context.Context, *MultiGetRequest) (*MultiGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MultiGet not implemented")
+}
+func (UnimplementedAmpKVServiceServer) MultiSet(context.Context, *MultiSetRequest) (*MultiSetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MultiSet not implemented")
+}
+func (UnimplementedAmpKVServiceServer) MultiDelete(context.Context, *MultiDeleteRequest) (*OperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MultiDelete not implemented")
+}
+func (UnimplementedAmpKVServiceServer) TTL(context.Context, *TTLRequest) (*TTLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TTL not implemented")
+}
+func (UnimplementedAmpKVServiceServer) Expire(context.Context, *ExpireRequest) (*OperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Expire not implemented")
+}
+func (UnimplementedAmpKVServiceServer) Persist(context.Context, *PersistRequest) (*OperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Persist not implemented")
+}
+func (UnimplementedAmpKVServiceServer) Increment(context.Context, *IncrementRequest) (*IncrementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Increment not implemented")
+}
+func (UnimplementedAmpKVServiceServer) ListPush(context.Context, *ListPushRequest) (*CountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPush not implemented")
+}
+func (UnimplementedAmpKVServiceServer) ListPop(context.Context, *ListPopRequest) (*ListPopResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPop not implemented")
+}
+func (UnimplementedAmpKVServiceServer) ListRange(context.Context, *ListRangeRequest) (*ListRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRange not implemented")
+}
+func (UnimplementedAmpKVServiceServer) SetAdd(context.Context, *SetAddRequest) (*CountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAdd not implemented")
+}
+func (UnimplementedAmpKVServiceServer) SetRemove(context.Context, *SetRemoveRequest) (*CountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRemove not implemented")
+}
+func (UnimplementedAmpKVServiceServer) SetMembers(context.Context, *SetMembersRequest) (*SetMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMembers not implemented")
+}
+func (UnimplementedAmpKVServiceServer) HashSet(context.Context, *HashSetRequest) (*HashSetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HashSet not implemented")
+}
+func (UnimplementedAmpKVServiceServer) HashGet(context.Context, *HashGetRequest) (*HashGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HashGet not implemented")
+}
+func (UnimplementedAmpKVServiceServer) HashDelete(context.Context, *HashDeleteRequest) (*CountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HashDelete not implemented")
+}
+func (UnimplementedAmpKVServiceServer) HashGetAll(context.Context, *HashGetAllRequest) (*HashGetAllResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HashGetAll not implemented")
+}
+func (UnimplementedAmpKVServiceServer) SortedSetAdd(context.Context, *SortedSetAddRequest) (*CountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SortedSetAdd not implemented")
+}
+func (UnimplementedAmpKVServiceServer) SortedSetRemove(context.Context, *SortedSetRemoveRequest) (*CountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SortedSetRemove not implemented")
+}
+func (UnimplementedAmpKVServiceServer) SortedSetRangeByScore(context.Context, *SortedSetRangeRequest) (*SortedSetRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SortedSetRangeByScore not implemented")
+}
+func (UnimplementedAmpKVServiceServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedAmpKVServiceServer) mustEmbedUnimplementedAmpKVServiceServer() {}
 func (UnimplementedAmpKVServiceServer) testEmbeddedByValue()                      {}
 
@@ -206,28 +612,955 @@ func _AmpKVService_Delete_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
-// AmpKVService_ServiceDesc is the grpc.ServiceDesc for AmpKVService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var AmpKVService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "ampkv.AmpKVService",
-	HandlerType: (*AmpKVServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Get",
-			Handler:    _AmpKVService_Get_Handler,
-		},
-		{
-			MethodName: "Set",
-			Handler:    _AmpKVService_Set_Handler,
-		},
-		{
-			MethodName: "SetWithTTL",
-			Handler:    _AmpKVService_SetWithTTL_Handler,
-		},
-		{
-			MethodName: "Delete",
-			Handler:    _AmpKVService_Delete_Handler,
+func _AmpKVService_Scan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ScanRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AmpKVServiceServer).Scan(m, &grpc.GenericServerStream[ScanRequest, ScanResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AmpKVService_ScanServer = grpc.ServerStreamingServer[ScanResponse]
+
+func _AmpKVService_Txn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).Txn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_Txn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).Txn(ctx, req.(*TxnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_CompareAndSwap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareAndSwapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).CompareAndSwap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_CompareAndSwap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).CompareAndSwap(ctx, req.(*CompareAndSwapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_MultiGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).MultiGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_MultiGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).MultiGet(ctx, req.(*MultiGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_MultiSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).MultiSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_MultiSet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).MultiSet(ctx, req.(*MultiSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_MultiDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).MultiDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_MultiDelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).MultiDelete(ctx, req.(*MultiDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_TTL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TTLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).TTL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_TTL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).TTL(ctx, req.(*TTLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_Expire_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExpireRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).Expire(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_Expire_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).Expire(ctx, req.(*ExpireRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_Persist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PersistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).Persist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_Persist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).Persist(ctx, req.(*PersistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_Increment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).Increment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_Increment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).Increment(ctx, req.(*IncrementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_ListPush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPushRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).ListPush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_ListPush_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).ListPush(ctx, req.(*ListPushRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_ListPop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).ListPop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_ListPop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).ListPop(ctx, req.(*ListPopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_ListRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).ListRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_ListRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).ListRange(ctx, req.(*ListRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_SetAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).SetAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_SetAdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).SetAdd(ctx, req.(*SetAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_SetRemove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRemoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).SetRemove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_SetRemove_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).SetRemove(ctx, req.(*SetRemoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_SetMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).SetMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_SetMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).SetMembers(ctx, req.(*SetMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_HashSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HashSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).HashSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_HashSet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).HashSet(ctx, req.(*HashSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_HashGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HashGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).HashGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_HashGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).HashGet(ctx, req.(*HashGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_HashDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HashDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).HashDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_HashDelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).HashDelete(ctx, req.(*HashDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_HashGetAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HashGetAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).HashGetAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_HashGetAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).HashGetAll(ctx, req.(*HashGetAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_SortedSetAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SortedSetAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).SortedSetAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_SortedSetAdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).SortedSetAdd(ctx, req.(*SortedSetAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_SortedSetRemove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SortedSetRemoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).SortedSetRemove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_SortedSetRemove_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).SortedSetRemove(ctx, req.(*SortedSetRemoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_SortedSetRangeByScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SortedSetRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AmpKVServiceServer).SortedSetRangeByScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AmpKVService_SortedSetRangeByScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AmpKVServiceServer).SortedSetRangeByScore(ctx, req.(*SortedSetRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AmpKVService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AmpKVServiceServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AmpKVService_WatchServer = grpc.ServerStreamingServer[WatchResponse]
+
+// AmpKVService_ServiceDesc is the grpc.ServiceDesc for AmpKVService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AmpKVService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ampkv.AmpKVService",
+	HandlerType: (*AmpKVServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Get",
+			Handler:    _AmpKVService_Get_Handler,
+		},
+		{
+			MethodName: "Set",
+			Handler:    _AmpKVService_Set_Handler,
+		},
+		{
+			MethodName: "SetWithTTL",
+			Handler:    _AmpKVService_SetWithTTL_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _AmpKVService_Delete_Handler,
+		},
+		{
+			MethodName: "Txn",
+			Handler:    _AmpKVService_Txn_Handler,
+		},
+		{
+			MethodName: "CompareAndSwap",
+			Handler:    _AmpKVService_CompareAndSwap_Handler,
+		},
+		{
+			MethodName: "MultiGet",
+			Handler:    _AmpKVService_MultiGet_Handler,
+		},
+		{
+			MethodName: "MultiSet",
+			Handler:    _AmpKVService_MultiSet_Handler,
+		},
+		{
+			MethodName: "MultiDelete",
+			Handler:    _AmpKVService_MultiDelete_Handler,
+		},
+		{
+			MethodName: "TTL",
+			Handler:    _AmpKVService_TTL_Handler,
+		},
+		{
+			MethodName: "Expire",
+			Handler:    _AmpKVService_Expire_Handler,
+		},
+		{
+			MethodName: "Persist",
+			Handler:    _AmpKVService_Persist_Handler,
+		},
+		{
+			MethodName: "Increment",
+			Handler:    _AmpKVService_Increment_Handler,
+		},
+		{
+			MethodName: "ListPush",
+			Handler:    _AmpKVService_ListPush_Handler,
+		},
+		{
+			MethodName: "ListPop",
+			Handler:    _AmpKVService_ListPop_Handler,
+		},
+		{
+			MethodName: "ListRange",
+			Handler:    _AmpKVService_ListRange_Handler,
+		},
+		{
+			MethodName: "SetAdd",
+			Handler:    _AmpKVService_SetAdd_Handler,
+		},
+		{
+			MethodName: "SetRemove",
+			Handler:    _AmpKVService_SetRemove_Handler,
+		},
+		{
+			MethodName: "SetMembers",
+			Handler:    _AmpKVService_SetMembers_Handler,
+		},
+		{
+			MethodName: "HashSet",
+			Handler:    _AmpKVService_HashSet_Handler,
+		},
+		{
+			MethodName: "HashGet",
+			Handler:    _AmpKVService_HashGet_Handler,
+		},
+		{
+			MethodName: "HashDelete",
+			Handler:    _AmpKVService_HashDelete_Handler,
+		},
+		{
+			MethodName: "HashGetAll",
+			Handler:    _AmpKVService_HashGetAll_Handler,
+		},
+		{
+			MethodName: "SortedSetAdd",
+			Handler:    _AmpKVService_SortedSetAdd_Handler,
+		},
+		{
+			MethodName: "SortedSetRemove",
+			Handler:    _AmpKVService_SortedSetRemove_Handler,
+		},
+		{
+			MethodName: "SortedSetRangeByScore",
+			Handler:    _AmpKVService_SortedSetRangeByScore_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Scan",
+			Handler:       _AmpKVService_Scan_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _AmpKVService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "ampkv.proto",
+}
+
+const (
+	AdminService_CreateApiKey_FullMethodName        = "/ampkv.AdminService/CreateApiKey"
+	AdminService_ListApiKeys_FullMethodName         = "/ampkv.AdminService/ListApiKeys"
+	AdminService_GetApiKey_FullMethodName           = "/ampkv.AdminService/GetApiKey"
+	AdminService_SetApiKeyDisabled_FullMethodName   = "/ampkv.AdminService/SetApiKeyDisabled"
+	AdminService_SetApiKeyExpiration_FullMethodName = "/ampkv.AdminService/SetApiKeyExpiration"
+	AdminService_SetApiKeyLimits_FullMethodName     = "/ampkv.AdminService/SetApiKeyLimits"
+	AdminService_RotateApiKey_FullMethodName        = "/ampkv.AdminService/RotateApiKey"
+	AdminService_DeleteApiKey_FullMethodName        = "/ampkv.AdminService/DeleteApiKey"
+	AdminService_QueryAuditLog_FullMethodName       = "/ampkv.AdminService/QueryAuditLog"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdminService manages api keys. Every method requires the admin permission.
+type AdminServiceClient interface {
+	CreateApiKey(ctx context.Context, in *CreateApiKeyRequest, opts ...grpc.CallOption) (*ApiKeyTokenResponse, error)
+	ListApiKeys(ctx context.Context, in *ListApiKeysRequest, opts ...grpc.CallOption) (*ListApiKeysResponse, error)
+	GetApiKey(ctx context.Context, in *ApiKeyIdRequest, opts ...grpc.CallOption) (*ApiKeyInfo, error)
+	SetApiKeyDisabled(ctx context.Context, in *SetApiKeyDisabledRequest, opts ...grpc.CallOption) (*ApiKeyInfo, error)
+	SetApiKeyExpiration(ctx context.Context, in *SetApiKeyExpirationRequest, opts ...grpc.CallOption) (*ApiKeyInfo, error)
+	SetApiKeyLimits(ctx context.Context, in *SetApiKeyLimitsRequest, opts ...grpc.CallOption) (*ApiKeyInfo, error)
+	RotateApiKey(ctx context.Context, in *ApiKeyIdRequest, opts ...grpc.CallOption) (*ApiKeyTokenResponse, error)
+	DeleteApiKey(ctx context.Context, in *ApiKeyIdRequest, opts ...grpc.CallOption) (*OperationResponse, error)
+	QueryAuditLog(ctx context.Context, in *QueryAuditLogRequest, opts ...grpc.CallOption) (*QueryAuditLogResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) CreateApiKey(ctx context.Context, in *CreateApiKeyRequest, opts ...grpc.CallOption) (*ApiKeyTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApiKeyTokenResponse)
+	err := c.cc.Invoke(ctx, AdminService_CreateApiKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListApiKeys(ctx context.Context, in *ListApiKeysRequest, opts ...grpc.CallOption) (*ListApiKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListApiKeysResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListApiKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetApiKey(ctx context.Context, in *ApiKeyIdRequest, opts ...grpc.CallOption) (*ApiKeyInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApiKeyInfo)
+	err := c.cc.Invoke(ctx, AdminService_GetApiKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetApiKeyDisabled(ctx context.Context, in *SetApiKeyDisabledRequest, opts ...grpc.CallOption) (*ApiKeyInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApiKeyInfo)
+	err := c.cc.Invoke(ctx, AdminService_SetApiKeyDisabled_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetApiKeyExpiration(ctx context.Context, in *SetApiKeyExpirationRequest, opts ...grpc.CallOption) (*ApiKeyInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApiKeyInfo)
+	err := c.cc.Invoke(ctx, AdminService_SetApiKeyExpiration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetApiKeyLimits(ctx context.Context, in *SetApiKeyLimitsRequest, opts ...grpc.CallOption) (*ApiKeyInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApiKeyInfo)
+	err := c.cc.Invoke(ctx, AdminService_SetApiKeyLimits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RotateApiKey(ctx context.Context, in *ApiKeyIdRequest, opts ...grpc.CallOption) (*ApiKeyTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApiKeyTokenResponse)
+	err := c.cc.Invoke(ctx, AdminService_RotateApiKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteApiKey(ctx context.Context, in *ApiKeyIdRequest, opts ...grpc.CallOption) (*OperationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OperationResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteApiKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) QueryAuditLog(ctx context.Context, in *QueryAuditLogRequest, opts ...grpc.CallOption) (*QueryAuditLogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryAuditLogResponse)
+	err := c.cc.Invoke(ctx, AdminService_QueryAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// AdminService manages api keys. Every method requires the admin permission.
+type AdminServiceServer interface {
+	CreateApiKey(context.Context, *CreateApiKeyRequest) (*ApiKeyTokenResponse, error)
+	ListApiKeys(context.Context, *ListApiKeysRequest) (*ListApiKeysResponse, error)
+	GetApiKey(context.Context, *ApiKeyIdRequest) (*ApiKeyInfo, error)
+	SetApiKeyDisabled(context.Context, *SetApiKeyDisabledRequest) (*ApiKeyInfo, error)
+	SetApiKeyExpiration(context.Context, *SetApiKeyExpirationRequest) (*ApiKeyInfo, error)
+	SetApiKeyLimits(context.Context, *SetApiKeyLimitsRequest) (*ApiKeyInfo, error)
+	RotateApiKey(context.Context, *ApiKeyIdRequest) (*ApiKeyTokenResponse, error)
+	DeleteApiKey(context.Context, *ApiKeyIdRequest) (*OperationResponse, error)
+	QueryAuditLog(context.Context, *QueryAuditLogRequest) (*QueryAuditLogResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) CreateApiKey(context.Context, *CreateApiKeyRequest) (*ApiKeyTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateApiKey not implemented")
+}
+func (UnimplementedAdminServiceServer) ListApiKeys(context.Context, *ListApiKeysRequest) (*ListApiKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListApiKeys not implemented")
+}
+func (UnimplementedAdminServiceServer) GetApiKey(context.Context, *ApiKeyIdRequest) (*ApiKeyInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetApiKey not implemented")
+}
+func (UnimplementedAdminServiceServer) SetApiKeyDisabled(context.Context, *SetApiKeyDisabledRequest) (*ApiKeyInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetApiKeyDisabled not implemented")
+}
+func (UnimplementedAdminServiceServer) SetApiKeyExpiration(context.Context, *SetApiKeyExpirationRequest) (*ApiKeyInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetApiKeyExpiration not implemented")
+}
+func (UnimplementedAdminServiceServer) SetApiKeyLimits(context.Context, *SetApiKeyLimitsRequest) (*ApiKeyInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetApiKeyLimits not implemented")
+}
+func (UnimplementedAdminServiceServer) RotateApiKey(context.Context, *ApiKeyIdRequest) (*ApiKeyTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateApiKey not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteApiKey(context.Context, *ApiKeyIdRequest) (*OperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteApiKey not implemented")
+}
+func (UnimplementedAdminServiceServer) QueryAuditLog(context.Context, *QueryAuditLogRequest) (*QueryAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAuditLog not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_CreateApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateApiKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CreateApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CreateApiKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CreateApiKey(ctx, req.(*CreateApiKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListApiKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListApiKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListApiKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListApiKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListApiKeys(ctx, req.(*ListApiKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApiKeyIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetApiKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetApiKey(ctx, req.(*ApiKeyIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetApiKeyDisabled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetApiKeyDisabledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetApiKeyDisabled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetApiKeyDisabled_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetApiKeyDisabled(ctx, req.(*SetApiKeyDisabledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetApiKeyExpiration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetApiKeyExpirationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetApiKeyExpiration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetApiKeyExpiration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetApiKeyExpiration(ctx, req.(*SetApiKeyExpirationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetApiKeyLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetApiKeyLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetApiKeyLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetApiKeyLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetApiKeyLimits(ctx, req.(*SetApiKeyLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RotateApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApiKeyIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RotateApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RotateApiKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RotateApiKey(ctx, req.(*ApiKeyIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApiKeyIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteApiKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteApiKey(ctx, req.(*ApiKeyIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_QueryAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).QueryAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_QueryAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).QueryAuditLog(ctx, req.(*QueryAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ampkv.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateApiKey",
+			Handler:    _AdminService_CreateApiKey_Handler,
+		},
+		{
+			MethodName: "ListApiKeys",
+			Handler:    _AdminService_ListApiKeys_Handler,
+		},
+		{
+			MethodName: "GetApiKey",
+			Handler:    _AdminService_GetApiKey_Handler,
+		},
+		{
+			MethodName: "SetApiKeyDisabled",
+			Handler:    _AdminService_SetApiKeyDisabled_Handler,
+		},
+		{
+			MethodName: "SetApiKeyExpiration",
+			Handler:    _AdminService_SetApiKeyExpiration_Handler,
+		},
+		{
+			MethodName: "SetApiKeyLimits",
+			Handler:    _AdminService_SetApiKeyLimits_Handler,
+		},
+		{
+			MethodName: "RotateApiKey",
+			Handler:    _AdminService_RotateApiKey_Handler,
+		},
+		{
+			MethodName: "DeleteApiKey",
+			Handler:    _AdminService_DeleteApiKey_Handler,
+		},
+		{
+			MethodName: "QueryAuditLog",
+			Handler:    _AdminService_QueryAuditLog_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -0,0 +1,10 @@
+package rpc
+
+// Reasons sent in an errdetails.ErrorInfo with FailedPrecondition errors, so
+// clients can tell the embedded errors that share the code apart.
+const (
+	ErrorDomain                = "ampkv"
+	ErrorReasonVersionMismatch = "VERSION_MISMATCH"
+	ErrorReasonTypeMismatch    = "TYPE_MISMATCH"
+	ErrorReasonUnsupported     = "UNSUPPORTED"
+)
@@ -0,0 +1,169 @@
+package common
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Records are stored in a binary envelope:
+//
+//	magic | format version | type tag | flags | uvarint payload length | payload
+//
+// The magic byte can not start a gob stream, so values written before the
+// envelope existed are still recognized and decoded with gob.
+const (
+	EnvelopeMagic   byte = 0xA7
+	EnvelopeVersion byte = 1
+
+	envelopeHeaderSize = 4
+)
+
+// An AmpKVValue payload starts with the metadata its flags announce, in flag
+// order, followed by the data.
+const (
+	// flagVersioned: uvarint revision.
+	flagVersioned byte = 1 << iota
+	// flagTimestamps: varint CreatedAt and UpdatedAt in Unix nanoseconds.
+	flagTimestamps
+	// flagExpires: varint ExpiresAt in Unix nanoseconds.
+	flagExpires
+
+	knownFlags = flagVersioned | flagTimestamps | flagExpires
+)
+
+var ErrMalformedEnvelope = errors.New("malformed binary envelope")
+
+// IsEnvelope reports whether data starts like a binary envelope.
+func IsEnvelope(data []byte) bool {
+	return len(data) > 0 && data[0] == EnvelopeMagic
+}
+
+// AppendEnvelope appends payload wrapped in an envelope to dst.
+func AppendEnvelope(dst []byte, tag, flags byte, payload []byte) []byte {
+	dst = append(dst, EnvelopeMagic, EnvelopeVersion, tag, flags)
+	dst = binary.AppendUvarint(dst, uint64(len(payload)))
+	return append(dst, payload...)
+}
+
+// OpenEnvelope returns the tag, flags and payload of an envelope. The payload
+// shares memory with data.
+func OpenEnvelope(data []byte) (tag, flags byte, payload []byte, err error) {
+	if len(data) < envelopeHeaderSize+1 || data[0] != EnvelopeMagic {
+		return 0, 0, nil, ErrMalformedEnvelope
+	}
+	if data[1] != EnvelopeVersion {
+		return 0, 0, nil, fmt.Errorf("unsupported envelope format version %d", data[1])
+	}
+
+	length, n := binary.Uvarint(data[envelopeHeaderSize:])
+	if n <= 0 {
+		return 0, 0, nil, ErrMalformedEnvelope
+	}
+	payload = data[envelopeHeaderSize+n:]
+	if uint64(len(payload)) != length {
+		return 0, 0, nil, ErrMalformedEnvelope
+	}
+	return data[2], data[3], payload, nil
+}
+
+func encodeAmpKVValue(v *AmpKVValue) []byte {
+	var (
+		flags    byte
+		metadata [4 * binary.MaxVarintLen64]byte
+	)
+	meta := metadata[:0]
+	if v.Version != 0 {
+		flags |= flagVersioned
+		meta = binary.AppendUvarint(meta, v.Version)
+	}
+	if !v.CreatedAt.IsZero() || !v.UpdatedAt.IsZero() {
+		flags |= flagTimestamps
+		meta = appendTime(meta, v.CreatedAt)
+		meta = appendTime(meta, v.UpdatedAt)
+	}
+	if v.ExpiresAt != nil {
+		flags |= flagExpires
+		meta = appendTime(meta, *v.ExpiresAt)
+	}
+
+	payloadLen := len(meta) + len(v.Data)
+	buf := make([]byte, 0, envelopeHeaderSize+uvarintLen(uint64(payloadLen))+payloadLen)
+	buf = append(buf, EnvelopeMagic, EnvelopeVersion, byte(v.Type), flags)
+	buf = binary.AppendUvarint(buf, uint64(payloadLen))
+	buf = append(buf, meta...)
+	return append(buf, v.Data...)
+}
+
+// decodeAmpKVValue decodes a binary envelope without copying: Data of the
+// returned value is a sub-slice of data.
+func decodeAmpKVValue(data []byte) (*AmpKVValue, error) {
+	tag, flags, payload, err := OpenEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if flags&^knownFlags != 0 {
+		return nil, fmt.Errorf("%w: unknown flags %#x", ErrMalformedEnvelope, flags&^knownFlags)
+	}
+
+	value := &AmpKVValue{Type: AmpKVDataType(tag)}
+	if flags&flagVersioned != 0 {
+		version, n := binary.Uvarint(payload)
+		if n <= 0 {
+			return nil, ErrMalformedEnvelope
+		}
+		value.Version = version
+		payload = payload[n:]
+	}
+	if flags&flagTimestamps != 0 {
+		if value.CreatedAt, payload, err = readTime(payload); err != nil {
+			return nil, err
+		}
+		if value.UpdatedAt, payload, err = readTime(payload); err != nil {
+			return nil, err
+		}
+	}
+	if flags&flagExpires != 0 {
+		var expiresAt time.Time
+		if expiresAt, payload, err = readTime(payload); err != nil {
+			return nil, err
+		}
+		value.ExpiresAt = &expiresAt
+	}
+	// Data is capped so that appending to it can not overwrite whatever
+	// follows it in data.
+	if len(payload) > 0 {
+		value.Data = payload[:len(payload):len(payload)]
+	}
+	return value, nil
+}
+
+// appendTime appends t in Unix nanoseconds, or 0 for the zero time.
+func appendTime(dst []byte, t time.Time) []byte {
+	if t.IsZero() {
+		return binary.AppendVarint(dst, 0)
+	}
+	return binary.AppendVarint(dst, t.UnixNano())
+}
+
+func readTime(data []byte) (time.Time, []byte, error) {
+	nanos, n := binary.Varint(data)
+	if n <= 0 {
+		return time.Time{}, nil, ErrMalformedEnvelope
+	}
+	if nanos == 0 {
+		return time.Time{}, data[n:], nil
+	}
+	return time.Unix(0, nanos).UTC(), data[n:], nil
+}
+
+func uvarintLen(x uint64) int {
+	n := 1
+	for x >= 0x80 {
+		x >>= 7
+		n++
+	}
+	return n
+}
@@ -0,0 +1,133 @@
+package common
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func encodeGob(t testing.TB, v *AmpKVValue) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(v); err != nil {
+		t.Fatalf("Failed to gob encode value: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+func TestAmpKVValueCodec(t *testing.T) {
+	created := time.Date(2025, 6, 1, 12, 0, 0, 123, time.UTC)
+	expires := created.Add(time.Hour)
+	values := []*AmpKVValue{
+		{Type: TypeString, Data: []byte("hello")},
+		{Type: TypeInt, Data: []byte{0, 0, 0, 0, 0, 0, 0, 42}, Version: 7},
+		{Type: TypeBinary, Data: bytes.Repeat([]byte{0xA7}, 300), Version: 1 << 40},
+		{Type: TypeJSON},
+		{Type: TypeFloat, Data: make([]byte, 8), Version: 9, CreatedAt: created, UpdatedAt: created.Add(time.Second), ExpiresAt: &expires},
+	}
+
+	for _, want := range values {
+		t.Run(want.Type.String(), func(t *testing.T) {
+			data, err := want.ToByteSlice()
+			if err != nil {
+				t.Fatalf("ToByteSlice failed: %v", err)
+			}
+			if !IsEnvelope(data) {
+				t.Fatalf("Expected a binary envelope, got %x", data[:4])
+			}
+			got, err := AmpKVValueFrom(data)
+			if err != nil {
+				t.Fatalf("AmpKVValueFrom failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected %+v, got %+v", want, got)
+			}
+
+			legacy, err := AmpKVValueFrom(encodeGob(t, want))
+			if err != nil {
+				t.Fatalf("Failed to decode gob value: %v", err)
+			}
+			if !reflect.DeepEqual(legacy, want) {
+				t.Errorf("Expected gob value %+v, got %+v", want, legacy)
+			}
+		})
+	}
+
+	t.Run("Malformed", func(t *testing.T) {
+		data, _ := (&AmpKVValue{Type: TypeString, Data: []byte("hello"), Version: 3}).ToByteSlice()
+		wrongVersion := bytes.Clone(data)
+		wrongVersion[1] = EnvelopeVersion + 1
+		unknownFlags := bytes.Clone(data)
+		unknownFlags[3] |= 0x80
+
+		for name, data := range map[string][]byte{
+			"Truncated":    data[:len(data)-1],
+			"Trailing":     append(bytes.Clone(data), 0),
+			"HeaderOnly":   data[:4],
+			"WrongVersion": wrongVersion,
+			"UnknownFlags": unknownFlags,
+		} {
+			if _, err := AmpKVValueFrom(data); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+		if _, _, _, err := OpenEnvelope(data[:4]); !errors.Is(err, ErrMalformedEnvelope) {
+			t.Errorf("Expected ErrMalformedEnvelope, got %v", err)
+		}
+	})
+
+	t.Run("NoAliasingAppend", func(t *testing.T) {
+		data, _ := (&AmpKVValue{Type: TypeString, Data: []byte("ab")}).ToByteSlice()
+		data = append(data, 'x')
+		value, err := AmpKVValueFrom(data[:len(data)-1])
+		if err != nil {
+			t.Fatalf("AmpKVValueFrom failed: %v", err)
+		}
+		_ = append(value.Data, 'y')
+		if data[len(data)-1] != 'x' {
+			t.Error("Appending to Data overwrote the buffer it was decoded from")
+		}
+		if &value.Data[0] != &data[len(data)-3] {
+			t.Error("Expected Data to alias the buffer it was decoded from")
+		}
+	})
+}
+
+var benchmarkValue = &AmpKVValue{Type: TypeJSON, Data: bytes.Repeat([]byte(`{"field":"value"}`), 16), Version: 123456}
+
+func BenchmarkAmpKVValueEncode(b *testing.B) {
+	b.Run("Binary", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := benchmarkValue.ToByteSlice(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Gob", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			encodeGob(b, benchmarkValue)
+		}
+	})
+}
+
+func BenchmarkAmpKVValueDecode(b *testing.B) {
+	binaryData, _ := benchmarkValue.ToByteSlice()
+	gobData := encodeGob(b, benchmarkValue)
+
+	for name, data := range map[string][]byte{"Binary": binaryData, "Gob": gobData} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for b.Loop() {
+				if _, err := AmpKVValueFrom(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
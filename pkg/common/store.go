@@ -0,0 +1,13 @@
+package common
+
+import "time"
+
+// Store is the API shared by the embedded store and the remote client, so
+// application code can switch between local and remote usage.
+type Store interface {
+	Get(key string) (*AmpKVValue, error)
+	Set(key string, value any, cost int64) error
+	SetWithTTL(key string, value any, cost int64, ttl time.Duration) error
+	Delete(key string) error
+	Close() error
+}
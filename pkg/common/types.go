@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"time"
 )
 
 type AmpKVDataType int
@@ -20,6 +21,12 @@ const (
 	TypeBool
 	TypeJSON
 	TypeBinary
+	// Collections are stored as a header value under their key and one
+	// entry per element; see embedded.AmpKV.
+	TypeList
+	TypeSet
+	TypeHash
+	TypeSortedSet
 )
 
 func (t AmpKVDataType) String() string {
@@ -38,29 +45,66 @@ func (t AmpKVDataType) String() string {
 		return "JSON"
 	case TypeBinary:
 		return "Binary"
+	case TypeList:
+		return "List"
+	case TypeSet:
+		return "Set"
+	case TypeHash:
+		return "Hash"
+	case TypeSortedSet:
+		return "SortedSet"
 	default:
 		return fmt.Sprintf("AmpKVDataType(%d)", t)
 	}
 }
 
+// IsValid reports whether t is one of the types defined above.
+func (t AmpKVDataType) IsValid() bool {
+	return t >= TypeUnknown && t <= TypeSortedSet
+}
+
+// IsCollection reports whether t is a list, set, hash or sorted set.
+func (t AmpKVDataType) IsCollection() bool {
+	return t >= TypeList && t <= TypeSortedSet
+}
+
 type AmpKVValue struct {
-	Type AmpKVDataType
-	Data []byte
+	Type    AmpKVDataType
+	Data    []byte
+	Version uint64
+	// CreatedAt is when the key was first written and UpdatedAt when this
+	// value was. Both are zero for values written before they were recorded.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// ExpiresAt is nil for values without a TTL.
+	ExpiresAt *time.Time
 }
 
-func (t *AmpKVValue) ToByteSlice() ([]byte, error) {
-	var buffer bytes.Buffer
-	encoder := gob.NewEncoder(&buffer)
+// Size is the length of Data in bytes.
+func (t *AmpKVValue) Size() int {
+	return len(t.Data)
+}
 
-	err := encoder.Encode(t)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to encode AmpKV Type to byte slice: %w", err)
+// ToByteSlice encodes the value in the binary envelope described in codec.go.
+func (t *AmpKVValue) ToByteSlice() ([]byte, error) {
+	if t.Type < 0 || t.Type > math.MaxUint8 {
+		return nil, fmt.Errorf("Failed to encode AmpKV Type to byte slice: invalid type %s", t.Type)
 	}
-
-	return buffer.Bytes(), nil
+	return encodeAmpKVValue(t), nil
 }
 
+// AmpKVValueFrom decodes a value written by ToByteSlice, or by the gob
+// encoding used before. Data of a binary encoded value aliases data, so data
+// must not be modified while the value is in use and the other way round.
 func AmpKVValueFrom(data []byte) (*AmpKVValue, error) {
+	if IsEnvelope(data) {
+		value, err := decodeAmpKVValue(data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode AmpKV Type from data: %w", err)
+		}
+		return value, nil
+	}
+
 	decoder := gob.NewDecoder(bytes.NewReader(data))
 	var decodedAmpKVValue AmpKVValue
 	err := decoder.Decode(&decodedAmpKVValue)
@@ -76,6 +120,16 @@ func NewAmpKVValue(value any) (*AmpKVValue, error) {
 		return nil, fmt.Errorf("value cannot be nil")
 	}
 
+	switch v := value.(type) {
+	case *AmpKVValue:
+		if v == nil {
+			return nil, fmt.Errorf("nil pointer value provided")
+		}
+		return &AmpKVValue{Type: v.Type, Data: v.Data}, nil
+	case AmpKVValue:
+		return &AmpKVValue{Type: v.Type, Data: v.Data}, nil
+	}
+
 	valType := reflect.TypeOf(value)
 	valValue := reflect.ValueOf(value)
 
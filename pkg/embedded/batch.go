@@ -0,0 +1,290 @@
+package embedded
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+type BatchItem struct {
+	Key   string
+	Value any
+	Cost  int64
+	TTL   time.Duration
+}
+
+// GetMany looks up all keys at once. Keys that do not exist are absent from
+// the returned map. Like Get, it fails with ErrTypeMismatch if any key holds a
+// collection.
+func (ampkv *AmpKV) GetMany(keys []string) (map[string]*common.AmpKVValue, error) {
+	values := make(map[string]*common.AmpKVValue, len(keys))
+	var misses []string
+	for _, key := range keys {
+		rawVal, err := ampkv.cache.Get(key)
+		if errors.Is(err, storage.ErrNotFound) {
+			misses = append(misses, key)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if values[key], err = decodeValue(key, rawVal); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(misses) > 0 {
+		storeValues, err := ampkv.store.GetMany(misses)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := make([]storage.Entry, 0, len(storeValues))
+		for key, rawVal := range storeValues {
+			ampKVValue, err := decodeValue(key, rawVal)
+			if err != nil {
+				return nil, err
+			}
+			ttl, ok := ampkv.cacheTTL(ampKVValue)
+			if !ok {
+				continue
+			}
+			values[key] = ampKVValue
+			entries = append(entries, storage.Entry{Key: key, Value: rawVal, Cost: ampkv.defaultCost, TTL: ttl})
+		}
+		ampkv.cache.SetMany(entries)
+	}
+
+	for key, value := range values {
+		if value.Type.IsCollection() {
+			return nil, errCollectionRead(key, value.Type)
+		}
+	}
+	return values, nil
+}
+
+func decodeValue(key string, rawVal []byte) (*common.AmpKVValue, error) {
+	ampKVValue, err := common.AmpKVValueFrom(rawVal)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode AmpKVValue for key '%s': %w", key, err)
+	}
+	return ampKVValue, nil
+}
+
+// SetMany writes all items in one batch and returns their new versions in
+// item order. Batches are not transactional: a failing batch may have been
+// partially applied.
+func (ampkv *AmpKV) SetMany(items []BatchItem) ([]uint64, error) {
+	writes := make([]txnWrite, 0, len(items))
+	for _, item := range items {
+		ampKVData, err := common.NewAmpKVValue(item.Value)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to encode value for key '%s': %w", item.Key, err)
+		}
+		if ampKVData.Type.IsCollection() {
+			return nil, ErrCollectionValue
+		}
+
+		cost := item.Cost
+		if cost <= 0 {
+			cost = ampkv.defaultCost
+		}
+		ttl := item.TTL
+		if ttl < 0 {
+			ttl = 0
+		}
+		writes = append(writes, txnWrite{key: item.Key, value: ampKVData, cost: cost, ttl: ttl})
+	}
+
+	revisions, err := ampkv.revisions.reserve(len(writes))
+	if err != nil {
+		return nil, err
+	}
+	defer revisions.release()
+
+	var versions []uint64
+	err = ampkv.commitBatch(func() (func() error, error) {
+		keys := make([]string, 0, len(writes))
+		for _, write := range writes {
+			keys = append(keys, write.key)
+		}
+		previous, err := ampkv.currentValues(keys)
+		if err != nil {
+			return nil, err
+		}
+		createdAt := make(map[string]time.Time, len(previous))
+		for key, value := range previous {
+			if !value.CreatedAt.IsZero() {
+				createdAt[key] = value.CreatedAt
+			}
+		}
+
+		now := time.Now()
+		entries := make([]storage.Entry, 0, len(writes))
+		for i := range writes {
+			write := &writes[i]
+
+			version, err := revisions.next()
+			if err != nil {
+				return nil, err
+			}
+			write.stamp(version, createdAt[write.key], now)
+			createdAt[write.key] = write.value.CreatedAt
+			write.raw, err = write.value.ToByteSlice()
+			if err != nil {
+				return nil, err
+			}
+
+			entries = append(entries, storage.Entry{Key: write.key, Value: write.raw, Cost: write.cost, TTL: write.ttl})
+			versions = append(versions, version)
+		}
+
+		if ampkv.store.IsNil() {
+			return func() error {
+				if err := ampkv.cache.SetMany(entries); err != nil {
+					return fmt.Errorf("Failed to set values to Cache: %w", err)
+				}
+				ampkv.publishWrites(writes)
+				return nil
+			}, nil
+		}
+
+		if err := ampkv.store.SetMany(entries); err != nil {
+			return func() error {
+				ampkv.invalidateCache(writes)
+				return nil
+			}, fmt.Errorf("Failed to set values to Store: %w", err)
+		}
+		return func() error {
+			if err := ampkv.cache.SetMany(entries); err != nil {
+				ampkv.invalidateCache(writes)
+			}
+			ampkv.publishWrites(writes)
+			ampkv.orphans.add(replacedCollections(previous)...)
+			return nil
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// currentValues returns the values stored under keys before a batch replaces
+// them. Keys that do not exist yet are absent.
+func (ampkv *AmpKV) currentValues(keys []string) (map[string]*common.AmpKVValue, error) {
+	rawValues := make(map[string][]byte, len(keys))
+	var misses []string
+	for _, key := range keys {
+		rawVal, err := ampkv.cache.Get(key)
+		if errors.Is(err, storage.ErrNotFound) {
+			misses = append(misses, key)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rawValues[key] = rawVal
+	}
+
+	if len(misses) > 0 {
+		storeValues, err := ampkv.store.GetMany(misses)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(rawValues, storeValues)
+	}
+
+	values := make(map[string]*common.AmpKVValue, len(rawValues))
+	for key, rawVal := range rawValues {
+		// A value that can not be decoded is overwritten like a missing one.
+		if value, err := common.AmpKVValueFrom(rawVal); err == nil {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// replacedCollections returns the ids of the collections among values, whose
+// elements a batch leaves without a header.
+func replacedCollections(values map[string]*common.AmpKVValue) []string {
+	var ids []string
+	for _, value := range values {
+		if id, ok := collectionID(value); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (ampkv *AmpKV) DeleteMany(keys []string) error {
+	revisions, err := ampkv.revisions.reserve(len(keys))
+	if err != nil {
+		return err
+	}
+	defer revisions.release()
+
+	return ampkv.commitBatch(func() (func() error, error) {
+		previous, err := ampkv.currentValues(keys)
+		if err != nil {
+			return nil, err
+		}
+
+		writes := make([]txnWrite, 0, len(keys))
+		for _, key := range keys {
+			version, err := revisions.next()
+			if err != nil {
+				return nil, err
+			}
+			writes = append(writes, txnWrite{key: key, delete: true, version: version})
+		}
+
+		if err := ampkv.store.DeleteMany(keys); err != nil {
+			return func() error {
+				ampkv.invalidateCache(writes)
+				return nil
+			}, fmt.Errorf("Failed to delete values from Store: %w", err)
+		}
+		return func() error {
+			for _, key := range keys {
+				if err := ampkv.cache.Delete(key); err != nil {
+					return fmt.Errorf("Failed to delete value from Cache: %w", err)
+				}
+			}
+			ampkv.publishWrites(writes)
+			ampkv.orphans.add(replacedCollections(previous)...)
+			return nil
+		}, nil
+	})
+}
+
+// commitBatch runs commit under commitMu and queues the function it returns
+// to update the cache and publish the batch once commitMu has been released.
+// The error of either is returned.
+func (ampkv *AmpKV) commitBatch(commit func() (apply func() error, err error)) error {
+	ampkv.commitMu.Lock()
+	apply, err := commit()
+	var applyErr error
+	if apply != nil {
+		ampkv.commits.push(func() {
+			applyErr = apply()
+		})
+	}
+	ampkv.commitMu.Unlock()
+	ampkv.commits.run()
+
+	if err != nil {
+		return err
+	}
+	return applyErr
+}
+
+func (ampkv *AmpKV) invalidateCache(writes []txnWrite) {
+	for _, write := range writes {
+		ampkv.cache.Delete(write.key)
+	}
+}
@@ -0,0 +1,114 @@
+package embedded
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+// Condition decides whether a conditional write may proceed. current is nil
+// when the key does not exist.
+type Condition func(current *common.AmpKVValue) error
+
+func VersionEquals(expectedVersion uint64) Condition {
+	return func(current *common.AmpKVValue) error {
+		if current == nil {
+			if expectedVersion == 0 {
+				return nil
+			}
+			return ErrVersionMismatch
+		}
+		if current.Version != expectedVersion {
+			return ErrVersionMismatch
+		}
+		return nil
+	}
+}
+
+func NotExists() Condition {
+	return func(current *common.AmpKVValue) error {
+		if current != nil {
+			return ErrKeyExists
+		}
+		return nil
+	}
+}
+
+// SetIf atomically writes value if cond accepts the key's current value and
+// returns the new version. When cond rejects the write, its error is returned
+// together with the current version (0 if the key does not exist).
+func (ampkv *AmpKV) SetIf(key string, value any, cost int64, ttl time.Duration, cond Condition) (uint64, error) {
+	var currentVersion uint64
+	tx, err := ampkv.update(func(tx *Txn) error {
+		if cond != nil {
+			current, err := tx.currentValue(key)
+			if err != nil {
+				return err
+			}
+			if err := cond(current); err != nil {
+				if current != nil {
+					currentVersion = current.Version
+				}
+				return err
+			}
+		}
+		return tx.SetWithTTL(key, value, cost, ttl)
+	})
+	if err != nil {
+		return currentVersion, err
+	}
+	return tx.committedVersion(key), nil
+}
+
+// DeleteIf atomically deletes key if cond accepts its current value. When cond
+// rejects the delete, its error is returned together with the current version.
+func (ampkv *AmpKV) DeleteIf(key string, cond Condition) (uint64, error) {
+	var version uint64
+	err := ampkv.Update(func(tx *Txn) error {
+		current, err := tx.currentValue(key)
+		if err != nil {
+			return err
+		}
+		if cond != nil {
+			if err := cond(current); err != nil {
+				if current != nil {
+					version = current.Version
+				}
+				return err
+			}
+		}
+		return tx.Delete(key)
+	})
+	return version, err
+}
+
+// CompareAndSwap replaces the value of key only if its version still equals
+// expectedVersion. An expectedVersion of 0 requires the key to be absent.
+func (ampkv *AmpKV) CompareAndSwap(key string, expectedVersion uint64, value any, cost int64) (uint64, error) {
+	return ampkv.SetIf(key, value, cost, 0, VersionEquals(expectedVersion))
+}
+
+func (ampkv *AmpKV) CompareAndSwapWithTTL(key string, expectedVersion uint64, value any, cost int64, ttl time.Duration) (uint64, error) {
+	return ampkv.SetIf(key, value, cost, ttl, VersionEquals(expectedVersion))
+}
+
+func (ampkv *AmpKV) SetIfNotExists(key string, value any, cost int64) (uint64, error) {
+	return ampkv.SetIf(key, value, cost, 0, NotExists())
+}
+
+func (ampkv *AmpKV) SetIfNotExistsWithTTL(key string, value any, cost int64, ttl time.Duration) (uint64, error) {
+	return ampkv.SetIf(key, value, cost, ttl, NotExists())
+}
+
+func (ampkv *AmpKV) CompareAndDelete(key string, expectedVersion uint64) (uint64, error) {
+	return ampkv.DeleteIf(key, VersionEquals(expectedVersion))
+}
+
+func (tx *Txn) currentValue(key string) (*common.AmpKVValue, error) {
+	current, err := tx.Get(key)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return current, err
+}
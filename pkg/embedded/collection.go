@@ -0,0 +1,285 @@
+package embedded
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+// Collections keep a header value under their own key and every element under
+// collectionPrefix, namespaced by a random id chosen when the collection is
+// created:
+//
+//	internal::col::<id>::                  owner record holding the key
+//	internal::col::<id>::l<index>          list element
+//	internal::col::<id>::m<member>         set member
+//	internal::col::<id>::f<field>          hash field and its value
+//	internal::col::<id>::s<member>         sorted set member and its score
+//	internal::col::<id>::z<score><member>  sorted set score index
+//
+// Empty collections are deleted. The header is the only source of truth:
+// elements carry no TTL and are only reached through the id in the header, so
+// they expire with it. Deleting or overwriting the header orphans the id, as a
+// collection created under the same key later gets a new one, and the orphaned
+// elements are deleted in the background (see gc.go). Element writes carry no
+// revision and are not published to watchers.
+const collectionPrefix = internalPrefix + "col::"
+
+const (
+	listElement     byte = 'l'
+	setMember       byte = 'm'
+	hashField       byte = 'f'
+	sortedSetMember byte = 's'
+	sortedSetScore  byte = 'z'
+
+	collectionIDSize = 8
+	// sortableLen is the length of an index or score in an element key.
+	sortableLen = 16
+)
+
+var errCollectionsUnsupported = fmt.Errorf("Collections require a store driver: %w", ErrUnsupported)
+
+type collection struct {
+	key    string
+	typ    common.AmpKVDataType
+	id     string
+	exists bool
+	// head and tail bound the indexes of a list, count is the size of every
+	// other collection.
+	head, tail int64
+	count      int64
+	ttl        time.Duration
+}
+
+func newCollection(key string, t common.AmpKVDataType) (*collection, error) {
+	var id [collectionIDSize]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, fmt.Errorf("Failed to generate collection id: %w", err)
+	}
+	return &collection{key: key, typ: t, id: hex.EncodeToString(id[:])}, nil
+}
+
+// decodeCollection reads the header stored under key. The header holds the
+// id followed by the list bounds or the size of the collection.
+func decodeCollection(key string, value *common.AmpKVValue) (*collection, error) {
+	data := value.Data
+	if len(data) < collectionIDSize {
+		return nil, fmt.Errorf("Failed to decode collection header for key '%s': %w", key, common.ErrMalformedEnvelope)
+	}
+	c := &collection{key: key, typ: value.Type, id: hex.EncodeToString(data[:collectionIDSize]), exists: true}
+	data = data[collectionIDSize:]
+
+	var n int
+	if c.typ == common.TypeList {
+		if c.head, n = binary.Varint(data); n > 0 {
+			c.tail, n = binary.Varint(data[n:])
+		}
+	} else {
+		c.count, n = binary.Varint(data)
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("Failed to decode collection header for key '%s': %w", key, common.ErrMalformedEnvelope)
+	}
+	return c, nil
+}
+
+func (c *collection) encode() []byte {
+	data, _ := hex.DecodeString(c.id)
+	if c.typ == common.TypeList {
+		data = binary.AppendVarint(data, c.head)
+		return binary.AppendVarint(data, c.tail)
+	}
+	return binary.AppendVarint(data, c.count)
+}
+
+func (c *collection) len() int64 {
+	if c.typ == common.TypeList {
+		return c.tail - c.head
+	}
+	return c.count
+}
+
+// ownerKey returns the key of the record that ties the elements of c to
+// its header.
+func (c *collection) ownerKey() string {
+	return collectionPrefix + c.id + "::"
+}
+
+func (c *collection) elementPrefix(kind byte) string {
+	return collectionPrefix + c.id + "::" + string(kind)
+}
+
+func (c *collection) elementKey(kind byte, suffix string) string {
+	return c.elementPrefix(kind) + suffix
+}
+
+func errCollectionRead(key string, t common.AmpKVDataType) error {
+	return fmt.Errorf("%w: key '%s' holds a %s, which only its collection operations can read", ErrTypeMismatch, key, t)
+}
+
+func isCollectionElement(key string) bool {
+	return strings.HasPrefix(key, collectionPrefix)
+}
+
+// collectionID returns the id of the collection whose header is value.
+func collectionID(value *common.AmpKVValue) (string, bool) {
+	if value == nil || !value.Type.IsCollection() || len(value.Data) < collectionIDSize {
+		return "", false
+	}
+	return hex.EncodeToString(value.Data[:collectionIDSize]), true
+}
+
+// replacedCollection returns the id of the collection previous if write
+// deletes it or replaces it with something else.
+func replacedCollection(previous *common.AmpKVValue, write *txnWrite) (string, bool) {
+	id, ok := collectionID(previous)
+	if !ok {
+		return "", false
+	}
+	if !write.delete {
+		if newID, ok := collectionID(write.value); ok && newID == id {
+			return "", false
+		}
+	}
+	return id, true
+}
+
+// collection returns the collection of type t stored under key for a write,
+// or a new, empty one if the key does not exist.
+func (tx *Txn) collection(key string, t common.AmpKVDataType) (*collection, error) {
+	if tx.ampkv.store.IsNil() {
+		return nil, errCollectionsUnsupported
+	}
+
+	current, ttl, err := tx.typedValue(key, t)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return newCollection(key, t)
+	}
+
+	c, err := decodeCollection(key, current)
+	if err != nil {
+		return nil, err
+	}
+	c.ttl = ttl
+	return c, nil
+}
+
+// saveCollection writes the header of c, or deletes the key once c is empty.
+func (tx *Txn) saveCollection(c *collection) error {
+	if c.len() == 0 {
+		if !c.exists {
+			return nil
+		}
+		return tx.Delete(c.key)
+	}
+
+	if !c.exists {
+		if err := tx.Set(c.ownerKey(), c.key, tx.ampkv.defaultCost); err != nil {
+			return err
+		}
+	}
+	header := &common.AmpKVValue{Type: c.typ, Data: c.encode()}
+	tx.writes = append(tx.writes, txnWrite{key: c.key, value: header, cost: tx.ampkv.defaultCost, ttl: c.ttl})
+	return nil
+}
+
+// setElement writes an element of c, which expires together with its header.
+func (tx *Txn) setElement(c *collection, key string, value any) error {
+	return tx.Set(key, value, tx.ampkv.defaultCost)
+}
+
+// hasElement reports whether the element under key exists in tx.
+func (tx *Txn) hasElement(key string) (bool, error) {
+	_, err := tx.Get(key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// readCollection returns the collection of type t stored under key, or nil if
+// the key does not exist.
+func (ampkv *AmpKV) readCollection(key string, t common.AmpKVDataType) (*collection, error) {
+	if ampkv.store.IsNil() {
+		return nil, errCollectionsUnsupported
+	}
+
+	value, err := ampkv.get(key)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if value.Type != t {
+		return nil, errTypeMismatch(key, value.Type, t)
+	}
+	return decodeCollection(key, value)
+}
+
+// elements calls fn for the elements of c of one kind in key order, starting
+// at the element with the given suffix, until fn returns false.
+func (ampkv *AmpKV) elements(c *collection, kind byte, start string, fn func(suffix string, rawVal []byte) (bool, error)) error {
+	prefix := c.elementPrefix(kind)
+	it, err := ampkv.store.Iterate(storage.IteratorOptions{Prefix: prefix, Start: prefix + start})
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	for it.Next() {
+		more, err := fn(it.Key()[len(prefix):], it.Value())
+		if err != nil {
+			return err
+		}
+		if !more {
+			break
+		}
+	}
+	return it.Err()
+}
+
+// sortableIndex encodes a list index so that keys sort in index order.
+func sortableIndex(i int64) string {
+	return fmt.Sprintf("%016x", uint64(i)^(1<<63))
+}
+
+// sortableScore encodes a score so that keys sort in score order.
+func sortableScore(score float64) string {
+	if score == 0 {
+		// -0 and +0 are the same score.
+		score = 0
+	}
+	bits := math.Float64bits(score)
+	if bits>>63 == 0 {
+		bits ^= 1 << 63
+	} else {
+		bits = ^bits
+	}
+	return fmt.Sprintf("%016x", bits)
+}
+
+func parseSortableScore(s string) (float64, error) {
+	bits, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, err
+	}
+	if bits>>63 == 1 {
+		bits ^= 1 << 63
+	} else {
+		bits = ^bits
+	}
+	return math.Float64frombits(bits), nil
+}
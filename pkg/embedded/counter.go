@@ -0,0 +1,112 @@
+package embedded
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+// IncrBy atomically adds delta to the TypeInt value of key and returns the
+// result. A missing key is created with a value of delta; a negative delta
+// decrements. The key keeps its TTL.
+func (ampkv *AmpKV) IncrBy(key string, delta int64) (int64, error) {
+	var result int64
+	err := ampkv.Update(func(tx *Txn) error {
+		var err error
+		result, err = tx.IncrBy(key, delta)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+// IncrByFloat is IncrBy for TypeFloat values.
+func (ampkv *AmpKV) IncrByFloat(key string, delta float64) (float64, error) {
+	var result float64
+	err := ampkv.Update(func(tx *Txn) error {
+		var err error
+		result, err = tx.IncrByFloat(key, delta)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+func (tx *Txn) IncrBy(key string, delta int64) (int64, error) {
+	current, ttl, err := tx.typedValue(key, common.TypeInt)
+	if err != nil {
+		return 0, err
+	}
+
+	var value int64
+	if current != nil {
+		if value, err = current.AsInt64(); err != nil {
+			return 0, err
+		}
+	}
+	result := value + delta
+	if (delta > 0 && result < value) || (delta < 0 && result > value) {
+		return 0, fmt.Errorf("%w: %d + %d", ErrOverflow, value, delta)
+	}
+
+	return result, tx.SetWithTTL(key, result, tx.ampkv.defaultCost, ttl)
+}
+
+func (tx *Txn) IncrByFloat(key string, delta float64) (float64, error) {
+	current, ttl, err := tx.typedValue(key, common.TypeFloat)
+	if err != nil {
+		return 0, err
+	}
+
+	var value float64
+	if current != nil {
+		if value, err = current.AsFloat64(); err != nil {
+			return 0, err
+		}
+	}
+	result := value + delta
+	if math.IsInf(result, 0) || math.IsNaN(result) {
+		return 0, fmt.Errorf("%w: %v + %v", ErrOverflow, value, delta)
+	}
+
+	return result, tx.SetWithTTL(key, result, tx.ampkv.defaultCost, ttl)
+}
+
+// typedValue returns the current value of key, which must have type t, for a
+// write that replaces it, and the TTL that keeps the key expiring when it does
+// now. current is nil if the key does not exist.
+func (tx *Txn) typedValue(key string, t common.AmpKVDataType) (*common.AmpKVValue, time.Duration, error) {
+	if tx.readOnly {
+		return nil, 0, ErrReadOnlyTxn
+	}
+
+	current, err := tx.currentValue(key)
+	if err != nil || current == nil {
+		return nil, 0, err
+	}
+	if current.Type != t {
+		return nil, 0, errTypeMismatch(key, current.Type, t)
+	}
+
+	var ttl time.Duration
+	if pending := tx.pendingWrite(key); pending != nil {
+		// Uncommitted values carry no expiry yet.
+		ttl = pending.ttl
+	} else if current.ExpiresAt != nil {
+		if ttl = time.Until(*current.ExpiresAt); ttl <= 0 {
+			// The value lapsed but has not been removed yet.
+			return nil, 0, nil
+		}
+	}
+	return current, ttl, nil
+}
+
+func errTypeMismatch(key string, got, want common.AmpKVDataType) error {
+	return fmt.Errorf("%w: key '%s' holds %s, not %s", ErrTypeMismatch, key, got, want)
+}
@@ -3,6 +3,8 @@ package embedded
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	nilCacheDriver "github.com/Unfield/AmpKV/drivers/cache/nil"
@@ -16,6 +18,16 @@ type AmpKV struct {
 	store       storage.KVStore
 	defaultTTL  time.Duration
 	defaultCost int64
+	txnMu       sync.Mutex
+	commitMu    sync.Mutex
+	commits     commitQueue
+	revisions   *revisionAllocator
+	feed        *changeFeed
+	expiry      *expiryTracker
+	orphans     *orphanQueue
+	done        chan struct{}
+	workers     sync.WaitGroup
+	closeOnce   sync.Once
 }
 
 type AmpKVStorageMode uint8
@@ -39,10 +51,26 @@ func (m AmpKVStorageMode) ToString() string {
 	}
 }
 
+// ParseAmpKVStorageMode accepts the names used on the command line
+// ("default", "cache-only", "store-only") as well as those of ToString.
+func ParseAmpKVStorageMode(s string) (AmpKVStorageMode, error) {
+	switch strings.ToLower(s) {
+	case "default", "":
+		return AmpKVStorageModeDefault, nil
+	case "cache-only", "cacheonly":
+		return AmpKVStorageModeCacheOnly, nil
+	case "store-only", "storeonly":
+		return AmpKVStorageModeStoreOnly, nil
+	default:
+		return 0, fmt.Errorf("unknown storage mode %q", s)
+	}
+}
+
 type AmpKVOptions struct {
-	DefaultTTL  time.Duration
-	DefaultCost int64
-	Mode        AmpKVStorageMode
+	DefaultTTL       time.Duration
+	DefaultCost      int64
+	Mode             AmpKVStorageMode
+	WatchHistorySize int
 }
 
 func NewAmpKV(cacheDriver, storeDriver any, options AmpKVOptions) (*AmpKV, error) {
@@ -115,107 +143,95 @@ func createAmpKV(cache storage.ICache, store storage.KVStore, options AmpKVOptio
 		return nil, fmt.Errorf("cache and store can not be nil at the same time")
 	}
 
-	return &AmpKV{
+	revisions, err := newRevisionAllocator(store)
+	if err != nil {
+		return nil, err
+	}
+
+	ampkv := &AmpKV{
 		cache:       cache,
 		store:       store,
 		defaultTTL:  options.DefaultTTL,
 		defaultCost: options.DefaultCost,
-	}, nil
-}
-
-func (ampkv *AmpKV) Get(key string) (*common.AmpKVValue, bool) {
-	var rawVal []byte
-	var found bool
-
-	cacheVal, cacheHit := ampkv.cache.Get(key)
-	if cacheHit {
-		rawVal = cacheVal
-		found = true
-	} else {
-		storeVal, storeFound := ampkv.store.Get(key)
-		if storeFound {
-			rawVal = storeVal
-			found = true
-			if ampkv.defaultTTL > 0 {
-				ampkv.cache.SetWithTTL(key, rawVal, ampkv.defaultCost, ampkv.defaultTTL)
-			} else {
-				ampkv.cache.Set(key, rawVal, ampkv.defaultCost)
-			}
-		}
+		revisions:   revisions,
+		feed:        newChangeFeed(options.WatchHistorySize, revisions.current+1),
+		expiry:      newExpiryTracker(),
+		orphans:     newOrphanQueue(),
+		done:        make(chan struct{}),
 	}
-
-	if found {
-		ampKVValue, err := common.AmpKVValueFrom(rawVal)
-		if err != nil {
-			fmt.Printf("Error decoding AmpKVValue for key '%s': %v\n", key, err)
-			return nil, false
-		}
-		return ampKVValue, true
+	go ampkv.runExpiry()
+	if !store.IsNil() {
+		ampkv.workers.Add(1)
+		go ampkv.runCollectionGC()
 	}
 
-	return nil, false
+	return ampkv, nil
 }
 
-func (ampkv *AmpKV) Set(key string, value any, cost int64) error {
-	ampKVData, err := common.NewAmpKVValue(value)
+// Get returns the value of key. Collections can only be read with their own
+// operations and fail with ErrTypeMismatch. Data may share memory with the
+// cache and must not be modified.
+func (ampkv *AmpKV) Get(key string) (*common.AmpKVValue, error) {
+	value, err := ampkv.get(key)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	ampKVDataByteSlice, err := ampKVData.ToByteSlice()
-	if err != nil {
-		return err
+	if value.Type.IsCollection() {
+		return nil, errCollectionRead(key, value.Type)
 	}
+	return value, nil
+}
 
-	err = ampkv.cache.Set(key, ampKVDataByteSlice, cost)
-	if err != nil {
-		return fmt.Errorf("Failed to set value to Cache: %w", err)
+func (ampkv *AmpKV) get(key string) (*common.AmpKVValue, error) {
+	rawVal, err := ampkv.cache.Get(key)
+	cacheMiss := errors.Is(err, storage.ErrNotFound)
+	if cacheMiss {
+		rawVal, err = ampkv.store.Get(key)
 	}
-	err = ampkv.store.Set(key, ampKVDataByteSlice, cost)
 	if err != nil {
-		return fmt.Errorf("Failed to set value to Store: %w", err)
+		return nil, err
 	}
-	return nil
-}
 
-func (ampkv *AmpKV) SetWithTTL(key string, value any, cost int64, ttl time.Duration) error {
-	ampKVData, err := common.NewAmpKVValue(value)
-	if err != nil {
-		return err
-	}
-	ampKVDataByteSlice, err := ampKVData.ToByteSlice()
+	ampKVValue, err := decodeValue(key, rawVal)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if ttl > 0 {
-		err = ampkv.cache.SetWithTTL(key, ampKVDataByteSlice, cost, ttl)
-		if err != nil {
-			return fmt.Errorf("Failed to set value to Cache: %w", err)
-		}
-		err = ampkv.store.SetWithTTL(key, ampKVDataByteSlice, cost, ttl)
-		if err != nil {
-			return fmt.Errorf("Failed to set value to Store: %w", err)
-		}
-		return nil
-	} else {
-		err = ampkv.cache.Set(key, ampKVDataByteSlice, cost)
-		if err != nil {
-			return fmt.Errorf("Failed to set value to Cache: %w", err)
-		}
-		err = ampkv.store.Set(key, ampKVDataByteSlice, cost)
-		if err != nil {
-			return fmt.Errorf("Failed to set value to Store: %w", err)
+	if cacheMiss {
+		if ttl, ok := ampkv.cacheTTL(ampKVValue); !ok {
+			return nil, ErrNotFound
+		} else if ttl > 0 {
+			ampkv.cache.SetWithTTL(key, rawVal, ampkv.defaultCost, ttl)
+		} else {
+			ampkv.cache.Set(key, rawVal, ampkv.defaultCost)
 		}
-		return nil
 	}
+	return ampKVValue, nil
 }
 
-func (ampkv *AmpKV) Delete(key string) {
-	ampkv.cache.Delete(key)
-	ampkv.store.Delete(key)
+func (ampkv *AmpKV) Set(key string, value any, cost int64) error {
+	return ampkv.SetWithTTL(key, value, cost, 0)
+}
+
+func (ampkv *AmpKV) SetWithTTL(key string, value any, cost int64, ttl time.Duration) error {
+	_, err := ampkv.SetIf(key, value, cost, ttl, nil)
+	return err
+}
+
+func (ampkv *AmpKV) Delete(key string) error {
+	return ampkv.Update(func(tx *Txn) error {
+		return tx.Delete(key)
+	})
 }
 
 func (ampkv *AmpKV) Close() error {
+	ampkv.closeOnce.Do(func() {
+		close(ampkv.done)
+		ampkv.feed.close()
+	})
+	// The collection GC must not iterate a store that is being closed.
+	ampkv.workers.Wait()
+
 	err := ampkv.cache.Close()
 	if err != nil {
 		return fmt.Errorf("Failed to close AmpKV Cache: %w", err)
@@ -1,13 +1,21 @@
 package embedded_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/Unfield/AmpKV/drivers/cache/ristretto"
 	"github.com/Unfield/AmpKV/drivers/store/badger"
+	"github.com/Unfield/AmpKV/pkg/common"
 	"github.com/Unfield/AmpKV/pkg/embedded"
 )
 
@@ -54,9 +62,9 @@ func TestAmpKVOperations(t *testing.T) {
 	ampkv := setupTestAmpKV(t)
 
 	t.Run("Get on non-existent key", func(t *testing.T) {
-		_, foundVal := ampkv.Get("nonexistentkey")
-		if foundVal {
-			t.Errorf("Expected key 'nonexistentkey' not to be found, but it was.")
+		_, err := ampkv.Get("nonexistentkey")
+		if !errors.Is(err, embedded.ErrNotFound) {
+			t.Errorf("Expected key 'nonexistentkey' not to be found, but got: %v", err)
 		}
 	})
 
@@ -68,9 +76,9 @@ func TestAmpKVOperations(t *testing.T) {
 			t.Fatalf("Error setting key '%s'", key)
 		}
 
-		retrievedValue, foundVal := ampkv.Get(key)
-		if !foundVal {
-			t.Fatalf("Expected key '%s' to be found after Set, but it was not.", key)
+		retrievedValue, err := ampkv.Get(key)
+		if err != nil {
+			t.Fatalf("Expected key '%s' to be found after Set, but got: %v", key, err)
 		}
 
 		stringValue, err := retrievedValue.AsString()
@@ -79,7 +87,7 @@ func TestAmpKVOperations(t *testing.T) {
 		}
 
 		if stringValue != string(value) {
-			t.Errorf("Retrieved value for '%s' was '%s', expected '%s'", key, retrievedValue, value)
+			t.Errorf("Retrieved value for '%s' was '%s', expected '%s'", key, stringValue, value)
 		}
 	})
 
@@ -88,9 +96,9 @@ func TestAmpKVOperations(t *testing.T) {
 		value := "testvaluewithttl"
 		ampkv.SetWithTTL(key, value, 1, 2*time.Second)
 
-		retrievedValue, foundVal := ampkv.Get(key)
-		if !foundVal {
-			t.Fatalf("Expected key '%s' to be found before TTL expiry, but it was not.", key)
+		retrievedValue, err := ampkv.Get(key)
+		if err != nil {
+			t.Fatalf("Expected key '%s' to be found before TTL expiry, but got: %v", key, err)
 		}
 
 		stringValue, err := retrievedValue.AsString()
@@ -99,7 +107,7 @@ func TestAmpKVOperations(t *testing.T) {
 		}
 
 		if stringValue != string(value) {
-			t.Errorf("Retrieved value for '%s' was '%s', expected '%s'", key, retrievedValue, value)
+			t.Errorf("Retrieved value for '%s' was '%s', expected '%s'", key, stringValue, value)
 		}
 	})
 
@@ -110,9 +118,9 @@ func TestAmpKVOperations(t *testing.T) {
 
 		time.Sleep(2*time.Second + 500*time.Millisecond)
 
-		_, foundVal := ampkv.Get(key)
-		if foundVal {
-			t.Errorf("Expected key '%s' to be gone after TTL expiry, but it was still found.", key)
+		_, err := ampkv.Get(key)
+		if !errors.Is(err, embedded.ErrNotFound) {
+			t.Errorf("Expected key '%s' to be gone after TTL expiry, but got: %v", key, err)
 		}
 	})
 
@@ -120,26 +128,1160 @@ func TestAmpKVOperations(t *testing.T) {
 		key := "keyToDelete"
 		ampkv.Set(key, "valueToDelete", 1)
 
-		_, foundVal := ampkv.Get(key)
-		if !foundVal {
-			t.Fatalf("Pre-condition failed: '%s' not found before deletion attempt.", key)
+		_, err := ampkv.Get(key)
+		if err != nil {
+			t.Fatalf("Pre-condition failed: '%s' not found before deletion attempt: %v", key, err)
 		}
 
-		ampkv.Delete(key)
+		if err := ampkv.Delete(key); err != nil {
+			t.Fatalf("Failed to delete key '%s': %v", key, err)
+		}
 
-		_, foundVal = ampkv.Get(key)
-		if foundVal {
-			t.Errorf("Expected key '%s' to be gone after Delete, but it was still found.", key)
+		_, err = ampkv.Get(key)
+		if !errors.Is(err, embedded.ErrNotFound) {
+			t.Errorf("Expected key '%s' to be gone after Delete, but got: %v", key, err)
 		}
 	})
 
 	t.Run("Delete non-existent key", func(t *testing.T) {
 		key := "nonExistentKeyToDelete"
-		ampkv.Delete(key)
+		if err := ampkv.Delete(key); err != nil {
+			t.Errorf("Expected Delete of non-existent key '%s' to succeed, but got: %v", key, err)
+		}
+
+		_, err := ampkv.Get(key)
+		if !errors.Is(err, embedded.ErrNotFound) {
+			t.Errorf("Expected non-existent key '%s' to remain non-existent after Delete, but got: %v", key, err)
+		}
+	})
+}
+
+func TestAmpKVClosedStore(t *testing.T) {
+	store, err := badger.NewBadgerStore(filepath.Join(t.TempDir(), "ampkv.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize Store: %v", err)
+	}
+
+	ampkv, err := embedded.NewAmpKV(nil, store, embedded.AmpKVOptions{Mode: embedded.AmpKVStorageModeStoreOnly})
+	if err != nil {
+		t.Fatalf("Failed to initialize AmpKV: %v", err)
+	}
+
+	if err := ampkv.Set("key", "value", 1); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	if err := ampkv.Close(); err != nil {
+		t.Fatalf("Failed to close AmpKV: %v", err)
+	}
+
+	_, err = ampkv.Get("key")
+	if !errors.Is(err, embedded.ErrClosed) {
+		t.Errorf("Expected Get on closed store to return ErrClosed, but got: %v", err)
+	}
+	if errors.Is(err, embedded.ErrNotFound) {
+		t.Errorf("Expected Get on closed store not to be reported as not found")
+	}
+
+	err = ampkv.Delete("key")
+	if !errors.Is(err, embedded.ErrClosed) {
+		t.Errorf("Expected Delete on closed store to return ErrClosed, but got: %v", err)
+	}
+}
+
+func TestAmpKVScan(t *testing.T) {
+	ampkv := setupTestAmpKV(t)
+
+	keys := []string{"session::a", "session::b", "session::c", "session::d", "session::e"}
+	for _, key := range keys {
+		if err := ampkv.Set(key, key, 1); err != nil {
+			t.Fatalf("Failed to set key '%s': %v", key, err)
+		}
+	}
+	if err := ampkv.Set("flag::beta", true, 1); err != nil {
+		t.Fatalf("Failed to set key 'flag::beta': %v", err)
+	}
+
+	t.Run("Paginate prefix", func(t *testing.T) {
+		var scanned []string
+		cursor := ""
+		for {
+			page, err := ampkv.Scan("session::", cursor, 2)
+			if err != nil {
+				t.Fatalf("Failed to scan: %v", err)
+			}
+			if len(page.Items) > 2 {
+				t.Fatalf("Expected at most 2 items per page, got %d", len(page.Items))
+			}
+			for _, item := range page.Items {
+				scanned = append(scanned, item.Key)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+
+		if len(scanned) != len(keys) {
+			t.Fatalf("Expected %d keys, got %d: %v", len(keys), len(scanned), scanned)
+		}
+		for i, key := range keys {
+			if scanned[i] != key {
+				t.Errorf("Expected key %d to be '%s', got '%s'", i, key, scanned[i])
+			}
+		}
+	})
+
+	t.Run("Start inside prefix", func(t *testing.T) {
+		page, err := ampkv.Scan("session::", "session::d", 0)
+		if err != nil {
+			t.Fatalf("Failed to scan: %v", err)
+		}
+		if len(page.Items) != 2 || page.Items[0].Key != "session::d" {
+			t.Errorf("Expected scan to start at 'session::d', got %v", page.Items)
+		}
+		if page.NextCursor != "" {
+			t.Errorf("Expected empty cursor at end of prefix, got '%s'", page.NextCursor)
+		}
+	})
+
+	t.Run("Decoded values", func(t *testing.T) {
+		page, err := ampkv.Scan("flag::", "", 10)
+		if err != nil {
+			t.Fatalf("Failed to scan: %v", err)
+		}
+		if len(page.Items) != 1 {
+			t.Fatalf("Expected 1 item, got %d", len(page.Items))
+		}
+		enabled, err := page.Items[0].Value.AsBool()
+		if err != nil || !enabled {
+			t.Errorf("Expected 'flag::beta' to decode to true, got %v (err: %v)", enabled, err)
+		}
+	})
+}
+
+func TestAmpKVTransactions(t *testing.T) {
+	ampkv := setupTestAmpKV(t)
+
+	t.Run("Update commits all writes", func(t *testing.T) {
+		err := ampkv.Update(func(tx *embedded.Txn) error {
+			if err := tx.Set("txn::a", "a", 1); err != nil {
+				return err
+			}
+			return tx.Set("txn::b", "b", 1)
+		})
+		if err != nil {
+			t.Fatalf("Failed to commit transaction: %v", err)
+		}
+
+		for _, key := range []string{"txn::a", "txn::b"} {
+			if _, err := ampkv.Get(key); err != nil {
+				t.Errorf("Expected key '%s' to be found after commit, but got: %v", key, err)
+			}
+		}
+	})
+
+	t.Run("Update discards writes on error", func(t *testing.T) {
+		abort := errors.New("abort")
+		err := ampkv.Update(func(tx *embedded.Txn) error {
+			if err := tx.Set("txn::rollback", "value", 1); err != nil {
+				return err
+			}
+			if _, err := tx.Get("txn::rollback"); err != nil {
+				t.Errorf("Expected transaction to read its own write, but got: %v", err)
+			}
+			return abort
+		})
+		if !errors.Is(err, abort) {
+			t.Fatalf("Expected Update to return the callback error, but got: %v", err)
+		}
+
+		if _, err := ampkv.Get("txn::rollback"); !errors.Is(err, embedded.ErrNotFound) {
+			t.Errorf("Expected key 'txn::rollback' not to be found after rollback, but got: %v", err)
+		}
+	})
+
+	t.Run("View rejects writes", func(t *testing.T) {
+		err := ampkv.View(func(tx *embedded.Txn) error {
+			return tx.Set("txn::view", "value", 1)
+		})
+		if !errors.Is(err, embedded.ErrReadOnlyTxn) {
+			t.Errorf("Expected ErrReadOnlyTxn, but got: %v", err)
+		}
+	})
+
+	t.Run("Concurrent updates retry on conflict", func(t *testing.T) {
+		const workers = 8
+		if err := ampkv.Set("txn::counter", 0, 1); err != nil {
+			t.Fatalf("Failed to set counter: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := ampkv.Update(func(tx *embedded.Txn) error {
+					val, err := tx.Get("txn::counter")
+					if err != nil {
+						return err
+					}
+					counter, err := val.AsInt()
+					if err != nil {
+						return err
+					}
+					return tx.Set("txn::counter", counter+1, 1)
+				})
+				if err != nil {
+					t.Errorf("Failed to increment counter: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		val, err := ampkv.Get("txn::counter")
+		if err != nil {
+			t.Fatalf("Failed to get counter: %v", err)
+		}
+		counter, err := val.AsInt()
+		if err != nil {
+			t.Fatalf("Failed to read counter as int: %v", err)
+		}
+		if counter != workers {
+			t.Errorf("Expected counter to be %d, got %d", workers, counter)
+		}
+	})
+}
+
+func TestAmpKVVersions(t *testing.T) {
+	ampkv := setupTestAmpKV(t)
+
+	t.Run("Versions increase on every write", func(t *testing.T) {
+		if err := ampkv.Set("version::key", "v1", 1); err != nil {
+			t.Fatalf("Failed to set key: %v", err)
+		}
+		first, err := ampkv.Get("version::key")
+		if err != nil {
+			t.Fatalf("Failed to get key: %v", err)
+		}
+		if err := ampkv.Set("version::key", "v2", 1); err != nil {
+			t.Fatalf("Failed to set key: %v", err)
+		}
+		second, err := ampkv.Get("version::key")
+		if err != nil {
+			t.Fatalf("Failed to get key: %v", err)
+		}
+
+		if first.Version == 0 || second.Version <= first.Version {
+			t.Errorf("Expected increasing non-zero versions, got %d then %d", first.Version, second.Version)
+		}
+	})
+
+	t.Run("CompareAndSwap", func(t *testing.T) {
+		version, err := ampkv.SetIfNotExists("cas::key", "initial", 1)
+		if err != nil {
+			t.Fatalf("Failed to create key: %v", err)
+		}
+
+		if _, err := ampkv.SetIfNotExists("cas::key", "again", 1); !errors.Is(err, embedded.ErrKeyExists) {
+			t.Errorf("Expected ErrKeyExists, but got: %v", err)
+		}
+
+		newVersion, err := ampkv.CompareAndSwap("cas::key", version, "updated", 1)
+		if err != nil {
+			t.Fatalf("Expected CompareAndSwap with current version to succeed, but got: %v", err)
+		}
+
+		current, err := ampkv.CompareAndSwap("cas::key", version, "stale", 1)
+		if !errors.Is(err, embedded.ErrVersionMismatch) {
+			t.Fatalf("Expected ErrVersionMismatch for stale version, but got: %v", err)
+		}
+		if current != newVersion {
+			t.Errorf("Expected mismatch to report current version %d, got %d", newVersion, current)
+		}
+
+		val, err := ampkv.Get("cas::key")
+		if err != nil {
+			t.Fatalf("Failed to get key: %v", err)
+		}
+		if s, _ := val.AsString(); s != "updated" || val.Version != newVersion {
+			t.Errorf("Expected 'updated' at version %d, got '%s' at version %d", newVersion, s, val.Version)
+		}
+	})
+
+	t.Run("CompareAndDelete", func(t *testing.T) {
+		version, err := ampkv.SetIfNotExists("cad::key", "value", 1)
+		if err != nil {
+			t.Fatalf("Failed to create key: %v", err)
+		}
+
+		if _, err := ampkv.CompareAndDelete("cad::key", version+1); !errors.Is(err, embedded.ErrVersionMismatch) {
+			t.Errorf("Expected ErrVersionMismatch, but got: %v", err)
+		}
+		if _, err := ampkv.CompareAndDelete("cad::key", version); err != nil {
+			t.Fatalf("Expected CompareAndDelete with current version to succeed, but got: %v", err)
+		}
+		if _, err := ampkv.Get("cad::key"); !errors.Is(err, embedded.ErrNotFound) {
+			t.Errorf("Expected key to be deleted, but got: %v", err)
+		}
+	})
+}
+
+func TestAmpKVMetadata(t *testing.T) {
+	ampkv := setupTestAmpKV(t)
+
+	before := time.Now()
+	if err := ampkv.Set("meta::key", "v1", 1); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	first, err := ampkv.Get("meta::key")
+	if err != nil {
+		t.Fatalf("Failed to get key: %v", err)
+	}
+	if first.CreatedAt.Before(before) || !first.UpdatedAt.Equal(first.CreatedAt) || first.ExpiresAt != nil {
+		t.Errorf("Unexpected metadata of a new key: created %v, updated %v, expires %v", first.CreatedAt, first.UpdatedAt, first.ExpiresAt)
+	}
+	if first.Size() != 2 {
+		t.Errorf("Expected a size of 2, got %d", first.Size())
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if err := ampkv.SetWithTTL("meta::key", "v2", 1, time.Hour); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	second, err := ampkv.Get("meta::key")
+	if err != nil {
+		t.Fatalf("Failed to get key: %v", err)
+	}
+	if !second.CreatedAt.Equal(first.CreatedAt) || !second.UpdatedAt.After(first.UpdatedAt) {
+		t.Errorf("Expected CreatedAt %v to be kept and UpdatedAt to advance, got %v and %v", first.CreatedAt, second.CreatedAt, second.UpdatedAt)
+	}
+	if second.ExpiresAt == nil || !second.ExpiresAt.Equal(second.UpdatedAt.Add(time.Hour)) {
+		t.Errorf("Expected the value to expire an hour after it was written, got %v", second.ExpiresAt)
+	}
+
+	if _, err := ampkv.SetMany([]embedded.BatchItem{{Key: "meta::key", Value: "v3"}, {Key: "meta::other", Value: "x"}}); err != nil {
+		t.Fatalf("Failed to set batch: %v", err)
+	}
+	values, err := ampkv.GetMany([]string{"meta::key", "meta::other"})
+	if err != nil {
+		t.Fatalf("Failed to get batch: %v", err)
+	}
+	if third := values["meta::key"]; !third.CreatedAt.Equal(first.CreatedAt) || third.ExpiresAt != nil {
+		t.Errorf("Expected the batch to keep CreatedAt and clear the expiry, got %v and %v", third.CreatedAt, third.ExpiresAt)
+	}
+	if other := values["meta::other"]; other.CreatedAt.IsZero() || !other.CreatedAt.Equal(other.UpdatedAt) {
+		t.Errorf("Expected a new key to be created by the batch, got %v and %v", other.CreatedAt, other.UpdatedAt)
+	}
+
+	if err := ampkv.Delete("meta::key"); err != nil {
+		t.Fatalf("Failed to delete key: %v", err)
+	}
+	if err := ampkv.Set("meta::key", "v4", 1); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	recreated, err := ampkv.Get("meta::key")
+	if err != nil {
+		t.Fatalf("Failed to get key: %v", err)
+	}
+	if !recreated.CreatedAt.After(first.CreatedAt) {
+		t.Errorf("Expected a recreated key to get a new CreatedAt, got %v", recreated.CreatedAt)
+	}
+}
+
+func TestAmpKVTTL(t *testing.T) {
+	ampkv := setupTestAmpKV(t)
+
+	if err := ampkv.Set("ttl::key", "value", 1); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	written, err := ampkv.Get("ttl::key")
+	if err != nil {
+		t.Fatalf("Failed to get key: %v", err)
+	}
+	if ttl, err := ampkv.TTL("ttl::key"); err != nil || ttl != 0 {
+		t.Errorf("Expected no TTL, got %v (%v)", ttl, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := ampkv.Watch(ctx, "ttl::key", embedded.WatchOptions{})
+	if err != nil {
+		t.Fatalf("Failed to start watch: %v", err)
+	}
+
+	if err := ampkv.Expire("ttl::key", time.Hour); err != nil {
+		t.Fatalf("Failed to expire key: %v", err)
+	}
+	if ttl, err := ampkv.TTL("ttl::key"); err != nil || ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Errorf("Expected a TTL of about an hour, got %v (%v)", ttl, err)
+	}
+	expiring, err := ampkv.Get("ttl::key")
+	if err != nil {
+		t.Fatalf("Failed to get key: %v", err)
+	}
+	if string(expiring.Data) != "value" || expiring.ExpiresAt == nil || expiring.Version != written.Version {
+		t.Errorf("Expected the value and version to be kept with a new expiry, got %+v", expiring)
+	}
+	if !expiring.UpdatedAt.Equal(written.UpdatedAt) || !expiring.CreatedAt.Equal(written.CreatedAt) {
+		t.Errorf("Expected the timestamps to be kept, got %v and %v", expiring.CreatedAt, expiring.UpdatedAt)
+	}
+
+	if err := ampkv.Persist("ttl::key"); err != nil {
+		t.Fatalf("Failed to persist key: %v", err)
+	}
+	if ttl, err := ampkv.TTL("ttl::key"); err != nil || ttl != 0 {
+		t.Errorf("Expected the TTL to be removed, got %v (%v)", ttl, err)
+	}
+	if persisted, err := ampkv.Get("ttl::key"); err != nil || persisted.Version != written.Version {
+		t.Errorf("Expected Persist to keep the version, got %+v (%v)", persisted, err)
+	}
+
+	if err := ampkv.ExpireAt("ttl::key", time.Now().Add(1100*time.Millisecond)); err != nil {
+		t.Fatalf("Failed to expire key: %v", err)
+	}
+	time.Sleep(1200 * time.Millisecond)
+	if _, err := ampkv.Get("ttl::key"); !errors.Is(err, embedded.ErrNotFound) {
+		t.Errorf("Expected the key to have expired, got %v", err)
+	}
+	// Changing the TTL publishes nothing, and only the last one expires.
+	if event := receiveEvent(t, events); event.Type != embedded.EventExpire || event.Revision <= written.Version {
+		t.Errorf("Expected only the expiry to be published, got %s at %d", event.Type, event.Revision)
+	}
+
+	if err := ampkv.Set("ttl::past", "value", 1); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	if err := ampkv.ExpireAt("ttl::past", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Failed to expire key: %v", err)
+	}
+	if _, err := ampkv.Get("ttl::past"); !errors.Is(err, embedded.ErrNotFound) {
+		t.Errorf("Expected an expiry in the past to delete the key, got %v", err)
+	}
+
+	if _, err := ampkv.TTL("ttl::missing"); !errors.Is(err, embedded.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound from TTL, got %v", err)
+	}
+	if err := ampkv.Expire("ttl::missing", time.Minute); !errors.Is(err, embedded.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound from Expire, got %v", err)
+	}
+	if err := ampkv.Persist("ttl::missing"); !errors.Is(err, embedded.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound from Persist, got %v", err)
+	}
+}
+
+func TestAmpKVCounters(t *testing.T) {
+	ampkv := setupTestAmpKV(t)
+
+	if value, err := ampkv.IncrBy("counter::hits", 5); err != nil || value != 5 {
+		t.Fatalf("Expected a missing counter to be created with 5, got %d (%v)", value, err)
+	}
+	if value, err := ampkv.IncrBy("counter::hits", -2); err != nil || value != 3 {
+		t.Errorf("Expected 3 after decrementing, got %d (%v)", value, err)
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				if _, err := ampkv.IncrBy("counter::hits", 1); err != nil {
+					t.Errorf("IncrBy failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	stored, err := ampkv.Get("counter::hits")
+	if err != nil {
+		t.Fatalf("Failed to get counter: %v", err)
+	}
+	if value, err := stored.AsInt64(); err != nil || value != 203 {
+		t.Errorf("Expected concurrent increments to add up to 203, got %d (%v)", value, err)
+	}
+
+	if _, err := ampkv.IncrByFloat("counter::score", 1.5); err != nil {
+		t.Fatalf("IncrByFloat failed: %v", err)
+	}
+	if value, err := ampkv.IncrByFloat("counter::score", 0.25); err != nil || value != 1.75 {
+		t.Errorf("Expected 1.75, got %v (%v)", value, err)
+	}
 
-		_, foundVal := ampkv.Get(key)
-		if foundVal {
-			t.Errorf("Expected non-existent key '%s' to remain non-existent after Delete, but it was found.", key)
+	if err := ampkv.SetWithTTL("counter::window", 1, 1, time.Hour); err != nil {
+		t.Fatalf("Failed to set counter: %v", err)
+	}
+	if _, err := ampkv.IncrBy("counter::window", 1); err != nil {
+		t.Fatalf("IncrBy failed: %v", err)
+	}
+	if ttl, err := ampkv.TTL("counter::window"); err != nil || ttl <= 59*time.Minute {
+		t.Errorf("Expected the counter to keep its TTL, got %v (%v)", ttl, err)
+	}
+
+	if err := ampkv.Set("counter::name", "not a number", 1); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	if _, err := ampkv.IncrBy("counter::name", 1); !errors.Is(err, embedded.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch for a string, got %v", err)
+	}
+	if _, err := ampkv.IncrByFloat("counter::hits", 1); !errors.Is(err, embedded.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch for an int, got %v", err)
+	}
+
+	if err := ampkv.Set("counter::max", int64(math.MaxInt64), 1); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	if _, err := ampkv.IncrBy("counter::max", 1); !errors.Is(err, embedded.ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+	if _, err := ampkv.IncrByFloat("counter::score", math.Inf(1)); !errors.Is(err, embedded.ErrOverflow) {
+		t.Errorf("Expected ErrOverflow for an infinite float, got %v", err)
+	}
+}
+
+func TestAmpKVCollections(t *testing.T) {
+	ampkv := setupTestAmpKV(t)
+
+	t.Run("List", func(t *testing.T) {
+		if length, err := ampkv.RPush("list::jobs", "b", "c"); err != nil || length != 2 {
+			t.Fatalf("Expected a length of 2, got %d (%v)", length, err)
+		}
+		if length, err := ampkv.LPush("list::jobs", "a", 0); err != nil || length != 4 {
+			t.Fatalf("Expected a length of 4, got %d (%v)", length, err)
+		}
+
+		values, err := ampkv.LRange("list::jobs", 0, -1)
+		if err != nil {
+			t.Fatalf("LRange failed: %v", err)
+		}
+		var got []string
+		for _, value := range values {
+			got = append(got, value.Type.String()+":"+string(value.Data))
+		}
+		want := []string{"Int:\x00\x00\x00\x00\x00\x00\x00\x00", "String:a", "String:b", "String:c"}
+		if !slices.Equal(got, want) {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+		if values, err := ampkv.LRange("list::jobs", -2, 10); err != nil || len(values) != 2 || string(values[0].Data) != "b" {
+			t.Errorf("Expected the last two values, got %v (%v)", values, err)
+		}
+
+		if value, err := ampkv.RPop("list::jobs"); err != nil || string(value.Data) != "c" {
+			t.Errorf("Expected to pop 'c', got %v (%v)", value, err)
+		}
+		if value, err := ampkv.LPop("list::jobs"); err != nil || value.Type != common.TypeInt {
+			t.Errorf("Expected to pop the int, got %v (%v)", value, err)
+		}
+		for range 2 {
+			if _, err := ampkv.LPop("list::jobs"); err != nil {
+				t.Fatalf("LPop failed: %v", err)
+			}
+		}
+		if _, err := ampkv.LPop("list::jobs"); !errors.Is(err, embedded.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound from an empty list, got %v", err)
+		}
+		if _, err := ampkv.Get("list::jobs"); !errors.Is(err, embedded.ErrNotFound) {
+			t.Errorf("Expected an empty list to be deleted, got %v", err)
 		}
 	})
+
+	t.Run("Set", func(t *testing.T) {
+		if added, err := ampkv.SAdd("set::tags", "go", "kv", "go"); err != nil || added != 2 {
+			t.Fatalf("Expected 2 members to be added, got %d (%v)", added, err)
+		}
+		if added, err := ampkv.SAdd("set::tags", "db", "kv"); err != nil || added != 1 {
+			t.Errorf("Expected 1 member to be added, got %d (%v)", added, err)
+		}
+		if members, err := ampkv.SMembers("set::tags"); err != nil || !slices.Equal(members, []string{"db", "go", "kv"}) {
+			t.Errorf("Expected [db go kv], got %v (%v)", members, err)
+		}
+		if ok, err := ampkv.SIsMember("set::tags", "go"); err != nil || !ok {
+			t.Errorf("Expected 'go' to be a member, got %v (%v)", ok, err)
+		}
+		if removed, err := ampkv.SRem("set::tags", "go", "rust"); err != nil || removed != 1 {
+			t.Errorf("Expected 1 member to be removed, got %d (%v)", removed, err)
+		}
+		if ok, err := ampkv.SIsMember("set::tags", "go"); err != nil || ok {
+			t.Errorf("Expected 'go' to be removed, got %v (%v)", ok, err)
+		}
+		if members, err := ampkv.SMembers("set::missing"); err != nil || len(members) != 0 {
+			t.Errorf("Expected a missing set to be empty, got %v (%v)", members, err)
+		}
+	})
+
+	t.Run("Hash", func(t *testing.T) {
+		if created, err := ampkv.HSet("hash::user", "name", "ada"); err != nil || !created {
+			t.Fatalf("Expected a new field, got %v (%v)", created, err)
+		}
+		if created, err := ampkv.HSet("hash::user", "age", 36); err != nil || !created {
+			t.Fatalf("Expected a new field, got %v (%v)", created, err)
+		}
+		if created, err := ampkv.HSet("hash::user", "age", 37); err != nil || created {
+			t.Errorf("Expected the field to be updated, got %v (%v)", created, err)
+		}
+
+		age, err := ampkv.HGet("hash::user", "age")
+		if err != nil {
+			t.Fatalf("HGet failed: %v", err)
+		}
+		if value, err := age.AsInt(); err != nil || value != 37 {
+			t.Errorf("Expected 37, got %d (%v)", value, err)
+		}
+		if _, err := ampkv.HGet("hash::user", "email"); !errors.Is(err, embedded.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for a missing field, got %v", err)
+		}
+
+		fields, err := ampkv.HGetAll("hash::user")
+		if err != nil || len(fields) != 2 || string(fields["name"].Data) != "ada" {
+			t.Errorf("Expected both fields, got %v (%v)", fields, err)
+		}
+		if removed, err := ampkv.HDel("hash::user", "age", "email"); err != nil || removed != 1 {
+			t.Errorf("Expected 1 field to be removed, got %d (%v)", removed, err)
+		}
+	})
+
+	t.Run("SortedSet", func(t *testing.T) {
+		added, err := ampkv.ZAdd("zset::scores",
+			embedded.ZMember{Member: "bob", Score: 20},
+			embedded.ZMember{Member: "ada", Score: -1.5},
+			embedded.ZMember{Member: "eve", Score: 20},
+			embedded.ZMember{Member: "dan", Score: 100},
+		)
+		if err != nil || added != 4 {
+			t.Fatalf("Expected 4 members to be added, got %d (%v)", added, err)
+		}
+		if added, err := ampkv.ZAdd("zset::scores", embedded.ZMember{Member: "dan", Score: 5}); err != nil || added != 0 {
+			t.Errorf("Expected the score to be updated, got %d (%v)", added, err)
+		}
+		if score, err := ampkv.ZScore("zset::scores", "dan"); err != nil || score != 5 {
+			t.Errorf("Expected a score of 5, got %v (%v)", score, err)
+		}
+
+		members, err := ampkv.ZRangeByScore("zset::scores", math.Inf(-1), 20, 0)
+		if err != nil {
+			t.Fatalf("ZRangeByScore failed: %v", err)
+		}
+		want := []embedded.ZMember{{"ada", -1.5}, {"dan", 5}, {"bob", 20}, {"eve", 20}}
+		if !slices.Equal(members, want) {
+			t.Errorf("Expected %v, got %v", want, members)
+		}
+		if members, err := ampkv.ZRangeByScore("zset::scores", 0, 100, 2); err != nil || !slices.Equal(members, want[1:3]) {
+			t.Errorf("Expected %v, got %v (%v)", want[1:3], members, err)
+		}
+
+		if removed, err := ampkv.ZRem("zset::scores", "bob", "zed"); err != nil || removed != 1 {
+			t.Errorf("Expected 1 member to be removed, got %d (%v)", removed, err)
+		}
+		if members, err := ampkv.ZRangeByScore("zset::scores", 20, 20, 0); err != nil || !slices.Equal(members, want[3:]) {
+			t.Errorf("Expected %v, got %v (%v)", want[3:], members, err)
+		}
+		if _, err := ampkv.ZAdd("zset::scores", embedded.ZMember{Member: "nan", Score: math.NaN()}); !errors.Is(err, embedded.ErrInvalidScore) {
+			t.Errorf("Expected ErrInvalidScore, got %v", err)
+		}
+	})
+
+	t.Run("TypeMismatch", func(t *testing.T) {
+		if _, err := ampkv.SAdd("hash::user", "x"); !errors.Is(err, embedded.ErrTypeMismatch) {
+			t.Errorf("Expected ErrTypeMismatch from SAdd on a hash, got %v", err)
+		}
+		if _, err := ampkv.LRange("set::tags", 0, -1); !errors.Is(err, embedded.ErrTypeMismatch) {
+			t.Errorf("Expected ErrTypeMismatch from LRange on a set, got %v", err)
+		}
+		if _, err := ampkv.IncrBy("set::tags", 1); !errors.Is(err, embedded.ErrTypeMismatch) {
+			t.Errorf("Expected ErrTypeMismatch from IncrBy on a set, got %v", err)
+		}
+		header := &common.AmpKVValue{Type: common.TypeSet, Data: make([]byte, 9)}
+		if err := ampkv.Set("set::forged", header, 1); !errors.Is(err, embedded.ErrCollectionValue) {
+			t.Errorf("Expected ErrCollectionValue for a forged header, got %v", err)
+		}
+	})
+
+	t.Run("Purged", func(t *testing.T) {
+		if _, err := ampkv.SAdd("set::purged", "a", "b"); err != nil {
+			t.Fatalf("SAdd failed: %v", err)
+		}
+		if err := ampkv.Set("set::purged", "scalar", 1); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if _, err := ampkv.SAdd("set::purged", "c"); !errors.Is(err, embedded.ErrTypeMismatch) {
+			t.Fatalf("Expected ErrTypeMismatch, got %v", err)
+		}
+		if err := ampkv.Delete("set::purged"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if members, err := ampkv.SMembers("set::purged"); err != nil || len(members) != 0 {
+			t.Errorf("Expected a recreated key to start empty, got %v (%v)", members, err)
+		}
+
+		if _, err := ampkv.RPush("list::batched", "a"); err != nil {
+			t.Fatalf("RPush failed: %v", err)
+		}
+		if _, err := ampkv.SetMany([]embedded.BatchItem{{Key: "list::batched", Value: "scalar"}}); err != nil {
+			t.Fatalf("SetMany failed: %v", err)
+		}
+		if _, err := ampkv.HSet("hash::batched", "f", "v"); err != nil {
+			t.Fatalf("HSet failed: %v", err)
+		}
+		if err := ampkv.DeleteMany([]string{"hash::batched"}); err != nil {
+			t.Fatalf("DeleteMany failed: %v", err)
+		}
+		if _, err := ampkv.ZAdd("zset::expired", embedded.ZMember{Member: "a", Score: 1}); err != nil {
+			t.Fatalf("ZAdd failed: %v", err)
+		}
+		if err := ampkv.Expire("zset::expired", 0); err != nil {
+			t.Fatalf("Expire failed: %v", err)
+		}
+
+		for _, key := range []string{"list::jobs", "set::tags", "hash::user", "zset::scores"} {
+			if err := ampkv.Delete(key); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+		}
+		waitForCollectionGC(t, ampkv)
+	})
+
+	t.Run("Large", func(t *testing.T) {
+		// 16 MiB of elements, more than a single Badger transaction holds.
+		value := strings.Repeat("x", 2048)
+		for range 8 {
+			values := make([]any, 1000)
+			for i := range values {
+				values[i] = value
+			}
+			if _, err := ampkv.RPush("list::large", values...); err != nil {
+				t.Fatalf("RPush failed: %v", err)
+			}
+		}
+
+		if err := ampkv.Expire("list::large", time.Minute); err != nil {
+			t.Fatalf("Expire failed: %v", err)
+		}
+		if err := ampkv.Persist("list::large"); err != nil {
+			t.Fatalf("Persist failed: %v", err)
+		}
+		if values, err := ampkv.LRange("list::large", -1, -1); err != nil || len(values) != 1 {
+			t.Errorf("Expected the last of 8000 elements, got %d (%v)", len(values), err)
+		}
+		if err := ampkv.Set("list::large", "scalar", 1); err != nil {
+			t.Fatalf("Overwriting the list failed: %v", err)
+		}
+		waitForCollectionGC(t, ampkv)
+		if err := ampkv.Delete("list::large"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		if _, err := ampkv.SAdd("set::expiring", "a", "b"); err != nil {
+			t.Fatalf("SAdd failed: %v", err)
+		}
+		if err := ampkv.Expire("set::expiring", time.Minute); err != nil {
+			t.Fatalf("Expire failed: %v", err)
+		}
+		if _, err := ampkv.SAdd("set::expiring", "c"); err != nil {
+			t.Fatalf("SAdd failed: %v", err)
+		}
+		if ttl, err := ampkv.TTL("set::expiring"); err != nil || ttl <= 0 || ttl > time.Minute {
+			t.Errorf("Expected adding a member to keep the TTL, got %v (%v)", ttl, err)
+		}
+		if err := ampkv.Persist("set::expiring"); err != nil {
+			t.Fatalf("Persist failed: %v", err)
+		}
+		if members, err := ampkv.SMembers("set::expiring"); err != nil || len(members) != 3 {
+			t.Errorf("Expected Persist to keep the members, got %v (%v)", members, err)
+		}
+
+		if err := ampkv.Expire("set::expiring", time.Second); err != nil {
+			t.Fatalf("Expire failed: %v", err)
+		}
+		time.Sleep(1500 * time.Millisecond)
+		if members, err := ampkv.SMembers("set::expiring"); err != nil || len(members) != 0 {
+			t.Errorf("Expected the members to expire with the set, got %v (%v)", members, err)
+		}
+		if _, err := ampkv.SAdd("set::expiring", "d"); err != nil {
+			t.Fatalf("SAdd failed: %v", err)
+		}
+		if members, err := ampkv.SMembers("set::expiring"); err != nil || !slices.Equal(members, []string{"d"}) {
+			t.Errorf("Expected a recreated set to start empty, got %v (%v)", members, err)
+		}
+		if err := ampkv.Delete("set::expiring"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+	})
+
+	t.Run("ScalarReads", func(t *testing.T) {
+		if _, err := ampkv.RPush("list::scalar", "a"); err != nil {
+			t.Fatalf("RPush failed: %v", err)
+		}
+		if _, err := ampkv.Get("list::scalar"); !errors.Is(err, embedded.ErrTypeMismatch) {
+			t.Errorf("Expected ErrTypeMismatch from Get on a list, got %v", err)
+		}
+		if _, err := ampkv.GetMany([]string{"list::scalar"}); !errors.Is(err, embedded.ErrTypeMismatch) {
+			t.Errorf("Expected ErrTypeMismatch from GetMany on a list, got %v", err)
+		}
+		if err := ampkv.Delete("list::scalar"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+	})
+
+	t.Run("Unpublished", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events, err := ampkv.Watch(ctx, "", embedded.WatchOptions{Prefix: true})
+		if err != nil {
+			t.Fatalf("Failed to start watch: %v", err)
+		}
+
+		before := ampkv.Revision()
+		if _, err := ampkv.RPush("list::quiet", "a", "b", "c"); err != nil {
+			t.Fatalf("RPush failed: %v", err)
+		}
+		if revision := ampkv.Revision(); revision != before+1 {
+			t.Errorf("Expected only the header to take a revision, got %d after %d", revision, before)
+		}
+		if event := receiveEvent(t, events); event.Key != "list::quiet" {
+			t.Errorf("Expected an event for the header only, got one for '%s'", event.Key)
+		}
+		if err := ampkv.Delete("list::quiet"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if event := receiveEvent(t, events); event.Key != "list::quiet" || event.Type != embedded.EventDelete {
+			t.Errorf("Expected the delete of the header only, got %s of '%s'", event.Type, event.Key)
+		}
+	})
+
+	t.Run("CacheOnly", func(t *testing.T) {
+		cacheOnly, err := embedded.NewAmpKV(mustRistretto(t), nil, embedded.AmpKVOptions{Mode: embedded.AmpKVStorageModeCacheOnly})
+		if err != nil {
+			t.Fatalf("Failed to initialize AmpKV: %v", err)
+		}
+		t.Cleanup(func() { cacheOnly.Close() })
+
+		if _, err := cacheOnly.RPush("list::jobs", "a"); !errors.Is(err, embedded.ErrUnsupported) {
+			t.Errorf("Expected ErrUnsupported without a store, got %v", err)
+		}
+	})
+}
+
+func TestAmpKVCollectionSweep(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ampkv.db")
+	open := func() *embedded.AmpKV {
+		store, err := badger.NewBadgerStore(path)
+		if err != nil {
+			t.Fatalf("Failed to initialize Store: %v", err)
+		}
+		ampkv, err := embedded.NewAmpKV(mustRistretto(t), store, embedded.AmpKVOptions{})
+		if err != nil {
+			t.Fatalf("Failed to initialize AmpKV: %v", err)
+		}
+		return ampkv
+	}
+
+	ampkv := open()
+	if _, err := ampkv.SAdd("set::expiring", "a", "b"); err != nil {
+		t.Fatalf("SAdd failed: %v", err)
+	}
+	if err := ampkv.Expire("set::expiring", time.Second); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	time.Sleep(1500 * time.Millisecond)
+	if err := ampkv.Close(); err != nil {
+		t.Fatalf("Failed to close AmpKV: %v", err)
+	}
+
+	// The elements of the expired set are found by the sweep on start.
+	ampkv = open()
+	t.Cleanup(func() { ampkv.Close() })
+	waitForCollectionGC(t, ampkv)
+}
+
+func TestAmpKVBatch(t *testing.T) {
+	ampkv := setupTestAmpKV(t)
+
+	versions, err := ampkv.SetMany([]embedded.BatchItem{
+		{Key: "batch::a", Value: "a"},
+		{Key: "batch::b", Value: 2},
+		{Key: "batch::c", Value: true, TTL: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("Failed to set batch: %v", err)
+	}
+	if len(versions) != 3 || versions[0] == 0 || versions[1] <= versions[0] || versions[2] <= versions[1] {
+		t.Errorf("Expected three increasing versions, got %v", versions)
+	}
+
+	values, err := ampkv.GetMany([]string{"batch::a", "batch::b", "batch::c", "batch::missing"})
+	if err != nil {
+		t.Fatalf("Failed to get batch: %v", err)
+	}
+	if len(values) != 3 {
+		t.Fatalf("Expected 3 values, got %d", len(values))
+	}
+	if _, found := values["batch::missing"]; found {
+		t.Errorf("Expected 'batch::missing' to be absent from the result")
+	}
+	if b, err := values["batch::b"].AsInt(); err != nil || b != 2 {
+		t.Errorf("Expected 'batch::b' to be 2, got %d (err: %v)", b, err)
+	}
+	if values["batch::a"].Version != versions[0] {
+		t.Errorf("Expected 'batch::a' at version %d, got %d", versions[0], values["batch::a"].Version)
+	}
+
+	if err := ampkv.DeleteMany([]string{"batch::a", "batch::b"}); err != nil {
+		t.Fatalf("Failed to delete batch: %v", err)
+	}
+	values, err = ampkv.GetMany([]string{"batch::a", "batch::b", "batch::c"})
+	if err != nil {
+		t.Fatalf("Failed to get batch: %v", err)
+	}
+	if len(values) != 1 || values["batch::c"] == nil {
+		t.Errorf("Expected only 'batch::c' to remain, got %v", values)
+	}
+}
+
+func receiveEvent(t *testing.T, events <-chan embedded.WatchEvent) embedded.WatchEvent {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatalf("Watch channel closed unexpectedly")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for watch event")
+	}
+	return embedded.WatchEvent{}
+}
+
+func TestAmpKVWatch(t *testing.T) {
+	ampkv := setupTestAmpKV(t)
+
+	t.Run("Prefix watch receives puts and deletes", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events, err := ampkv.Watch(ctx, "config::", embedded.WatchOptions{Prefix: true})
+		if err != nil {
+			t.Fatalf("Failed to start watch: %v", err)
+		}
+
+		if err := ampkv.Set("other::key", "ignored", 1); err != nil {
+			t.Fatalf("Failed to set key: %v", err)
+		}
+		if err := ampkv.Set("config::feature", "on", 1); err != nil {
+			t.Fatalf("Failed to set key: %v", err)
+		}
+		if err := ampkv.Delete("config::feature"); err != nil {
+			t.Fatalf("Failed to delete key: %v", err)
+		}
+
+		put := receiveEvent(t, events)
+		if put.Type != embedded.EventPut || put.Key != "config::feature" {
+			t.Fatalf("Expected put of 'config::feature', got %s of '%s'", put.Type, put.Key)
+		}
+		if val, _ := put.Value.AsString(); val != "on" || put.Value.Version != put.Revision {
+			t.Errorf("Expected value 'on' at revision %d, got '%s' at version %d", put.Revision, val, put.Value.Version)
+		}
+
+		del := receiveEvent(t, events)
+		if del.Type != embedded.EventDelete || del.Revision <= put.Revision {
+			t.Errorf("Expected delete after revision %d, got %s at revision %d", put.Revision, del.Type, del.Revision)
+		}
+
+		cancel()
+		select {
+		case _, ok := <-events:
+			if ok {
+				t.Errorf("Expected no further events after cancel")
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("Expected watch channel to close after cancel")
+		}
+	})
+
+	t.Run("Resume from revision", func(t *testing.T) {
+		if err := ampkv.Set("resume::key", 1, 1); err != nil {
+			t.Fatalf("Failed to set key: %v", err)
+		}
+		first, err := ampkv.Get("resume::key")
+		if err != nil {
+			t.Fatalf("Failed to get key: %v", err)
+		}
+		if err := ampkv.Set("resume::key", 2, 1); err != nil {
+			t.Fatalf("Failed to set key: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events, err := ampkv.Watch(ctx, "resume::key", embedded.WatchOptions{StartRevision: first.Version})
+		if err != nil {
+			t.Fatalf("Failed to start watch: %v", err)
+		}
+		for _, expected := range []int{1, 2} {
+			event := receiveEvent(t, events)
+			if val, _ := event.Value.AsInt(); val != expected {
+				t.Errorf("Expected replayed value %d, got %d", expected, val)
+			}
+		}
+	})
+
+	t.Run("Expire event", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events, err := ampkv.Watch(ctx, "expire::key", embedded.WatchOptions{})
+		if err != nil {
+			t.Fatalf("Failed to start watch: %v", err)
+		}
+		if err := ampkv.SetWithTTL("expire::key", "soon gone", 1, time.Second); err != nil {
+			t.Fatalf("Failed to set key: %v", err)
+		}
+
+		if event := receiveEvent(t, events); event.Type != embedded.EventPut {
+			t.Fatalf("Expected put event, got %s", event.Type)
+		}
+		if event := receiveEvent(t, events); event.Type != embedded.EventExpire || event.Key != "expire::key" {
+			t.Errorf("Expected expire event for 'expire::key', got %s for '%s'", event.Type, event.Key)
+		}
+	})
+
+	t.Run("Internal keys are not published", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events, err := ampkv.Watch(ctx, "", embedded.WatchOptions{Prefix: true})
+		if err != nil {
+			t.Fatalf("Failed to start watch: %v", err)
+		}
+		if err := ampkv.SetWithTTL("internal::audit::entry", "secret", 1, time.Second); err != nil {
+			t.Fatalf("Failed to set key: %v", err)
+		}
+		if _, err := ampkv.IncrBy("internal::api::usage::counter", 1); err != nil {
+			t.Fatalf("Failed to increment key: %v", err)
+		}
+		if err := ampkv.Delete("internal::audit::entry"); err != nil {
+			t.Fatalf("Failed to delete key: %v", err)
+		}
+		if err := ampkv.SetWithTTL("public::key", "visible", 1, time.Second); err != nil {
+			t.Fatalf("Failed to set key: %v", err)
+		}
+
+		for _, expected := range []embedded.EventType{embedded.EventPut, embedded.EventExpire} {
+			if event := receiveEvent(t, events); event.Type != expected || event.Key != "public::key" {
+				t.Errorf("Expected %s of 'public::key', got %s of '%s'", expected, event.Type, event.Key)
+			}
+		}
+	})
+
+	t.Run("Concurrent writes are published in revision order", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events, err := ampkv.Watch(ctx, "order::", embedded.WatchOptions{Prefix: true})
+		if err != nil {
+			t.Fatalf("Failed to start watch: %v", err)
+		}
+
+		// Enough writes to cross a revision lease, through transactions
+		// and batches alike.
+		const workers, writes = 8, 150
+		var wg sync.WaitGroup
+		for worker := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range writes {
+					key := fmt.Sprintf("order::%d", i%4)
+					var err error
+					switch (worker + i) % 3 {
+					case 0:
+						err = ampkv.Set(key, worker, 1)
+					case 1:
+						_, err = ampkv.SetMany([]embedded.BatchItem{{Key: key, Value: worker}})
+					default:
+						err = ampkv.DeleteMany([]string{key})
+					}
+					if err != nil {
+						t.Errorf("Write failed: %v", err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		last := make(map[string]embedded.WatchEvent)
+		var revision uint64
+		for range workers * writes {
+			event := receiveEvent(t, events)
+			if event.Revision <= revision {
+				t.Fatalf("Expected increasing revisions, got %d after %d", event.Revision, revision)
+			}
+			revision = event.Revision
+			last[event.Key] = event
+		}
+		for key, event := range last {
+			value, err := ampkv.Get(key)
+			switch {
+			case event.Type == embedded.EventDelete:
+				if !errors.Is(err, embedded.ErrNotFound) {
+					t.Errorf("Expected '%s' to be deleted like its last event, got %v (%v)", key, value, err)
+				}
+			case err != nil || value.Version != event.Revision:
+				t.Errorf("Expected '%s' at its last revision %d, got %v (%v)", key, event.Revision, value, err)
+			}
+		}
+	})
+}
+
+func TestAmpKVWatchCompacted(t *testing.T) {
+	ampkv, err := embedded.NewAmpKV(mustRistretto(t), nil, embedded.AmpKVOptions{
+		Mode:             embedded.AmpKVStorageModeCacheOnly,
+		WatchHistorySize: 2,
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize AmpKV: %v", err)
+	}
+	t.Cleanup(func() { ampkv.Close() })
+
+	for i := range 3 {
+		if err := ampkv.Set("compact::key", i, 1); err != nil {
+			t.Fatalf("Failed to set key: %v", err)
+		}
+	}
+
+	_, err = ampkv.Watch(context.Background(), "compact::key", embedded.WatchOptions{StartRevision: 1})
+	if !errors.Is(err, embedded.ErrCompacted) {
+		t.Errorf("Expected ErrCompacted, but got: %v", err)
+	}
+}
+
+// waitForCollectionGC waits until the elements of every deleted collection
+// have been collected.
+func waitForCollectionGC(t *testing.T, ampkv *embedded.AmpKV) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		page, err := ampkv.Scan("internal::col::", "", 0)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if len(page.Items) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the elements of deleted collections to be collected, %d remain", len(page.Items))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func mustRistretto(t *testing.T) *ristretto.RistrettoCache {
+	t.Helper()
+	cache, err := ristretto.NewRistrettoCache(1e7, 1<<30, 64)
+	if err != nil {
+		t.Fatalf("Failed to initialize Cache: %v", err)
+	}
+	return cache
 }
@@ -0,0 +1,22 @@
+package embedded
+
+import (
+	"errors"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+)
+
+var (
+	ErrNotFound        = storage.ErrNotFound
+	ErrBackendFailure  = storage.ErrBackendFailure
+	ErrClosed          = storage.ErrClosed
+	ErrUnsupported     = storage.ErrUnsupported
+	ErrConflict        = storage.ErrConflict
+	ErrReadOnlyTxn     = errors.New("cannot write in a read-only transaction")
+	ErrVersionMismatch = errors.New("version mismatch")
+	ErrKeyExists       = errors.New("key already exists")
+	ErrTypeMismatch    = errors.New("value has a different type")
+	ErrOverflow        = errors.New("increment overflows the value")
+	ErrCollectionValue = errors.New("collections can only be written by collection operations")
+	ErrInvalidScore    = errors.New("score is not a number")
+)
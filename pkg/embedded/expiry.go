@@ -0,0 +1,126 @@
+package embedded
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+type expiryEntry struct {
+	key       string
+	version   uint64
+	expiresAt time.Time
+}
+
+type expiryHeap []expiryEntry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].expiresAt.Before(h[j].expiresAt) }
+func (h expiryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *expiryHeap) Push(x any)        { *h = append(*h, x.(expiryEntry)) }
+func (h *expiryHeap) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// expiryTracker remembers the keys written with a TTL so that an EventExpire
+// can be published once they lapse. Only writes made by this process are
+// tracked; keys that were given a TTL before a restart expire silently.
+type expiryTracker struct {
+	mu      sync.Mutex
+	entries expiryHeap
+	// latest holds the last entry tracked for every key. A TTL change keeps
+	// the version, so entries are told apart by their expiry time as well.
+	latest map[string]expiryEntry
+	wake   chan struct{}
+}
+
+func newExpiryTracker() *expiryTracker {
+	return &expiryTracker{
+		latest: make(map[string]expiryEntry),
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+func (t *expiryTracker) track(key string, version uint64, ttl time.Duration) {
+	entry := expiryEntry{key: key, version: version, expiresAt: time.Now().Add(ttl)}
+	t.mu.Lock()
+	t.latest[key] = entry
+	heap.Push(&t.entries, entry)
+	t.mu.Unlock()
+
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (t *expiryTracker) forget(key string) {
+	t.mu.Lock()
+	delete(t.latest, key)
+	t.mu.Unlock()
+}
+
+// due pops every entry that has expired and is still the latest write of its
+// key, and reports how long to wait for the next one.
+func (t *expiryTracker) due(now time.Time) ([]expiryEntry, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var expired []expiryEntry
+	for t.entries.Len() > 0 {
+		next := t.entries[0]
+		if next.expiresAt.After(now) {
+			return expired, next.expiresAt.Sub(now)
+		}
+		heap.Pop(&t.entries)
+		if t.latest[next.key] == next {
+			delete(t.latest, next.key)
+			expired = append(expired, next)
+		}
+	}
+	return expired, time.Hour
+}
+
+func (ampkv *AmpKV) runExpiry() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		timer.Reset(ampkv.publishExpired())
+
+		select {
+		case <-ampkv.done:
+			return
+		case <-ampkv.expiry.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// publishExpired publishes an EventExpire for every lapsed key and returns how
+// long to wait before the next key is due.
+func (ampkv *AmpKV) publishExpired() time.Duration {
+	ampkv.commitMu.Lock()
+	expired, wait := ampkv.expiry.due(time.Now())
+	events := make([]WatchEvent, 0, len(expired))
+	for _, entry := range expired {
+		revision, err := ampkv.revisions.next()
+		if err != nil {
+			continue
+		}
+		events = append(events, WatchEvent{Type: EventExpire, Key: entry.key, Revision: revision})
+	}
+	ampkv.commits.push(func() {
+		for _, event := range events {
+			ampkv.cache.Delete(event.Key)
+		}
+		ampkv.feed.publish(events)
+	})
+	ampkv.commitMu.Unlock()
+
+	ampkv.commits.run()
+	return wait
+}
@@ -0,0 +1,194 @@
+package embedded
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+const (
+	// collectionSweepInterval is how often the store is searched for the
+	// elements of collections whose header has expired.
+	collectionSweepInterval = 10 * time.Minute
+	// collectionGCChunkSize bounds the number of elements deleted at once,
+	// so that collections of any size can be collected.
+	collectionGCChunkSize = 1000
+)
+
+// orphanQueue holds the ids of collections whose header was deleted or
+// overwritten until their elements have been collected.
+type orphanQueue struct {
+	mu   sync.Mutex
+	ids  []string
+	wake chan struct{}
+}
+
+func newOrphanQueue() *orphanQueue {
+	return &orphanQueue{wake: make(chan struct{}, 1)}
+}
+
+func (q *orphanQueue) add(ids ...string) {
+	if len(ids) == 0 {
+		return
+	}
+
+	q.mu.Lock()
+	q.ids = append(q.ids, ids...)
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *orphanQueue) take() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ids := q.ids
+	q.ids = nil
+	return ids
+}
+
+// runCollectionGC deletes the elements of orphaned collections as they are
+// queued, and sweeps the store for those of expired collections on start and
+// every collectionSweepInterval.
+func (ampkv *AmpKV) runCollectionGC() {
+	defer ampkv.workers.Done()
+
+	ticker := time.NewTicker(collectionSweepInterval)
+	defer ticker.Stop()
+
+	ampkv.sweepCollections()
+	for {
+		select {
+		case <-ampkv.done:
+			return
+		case <-ampkv.orphans.wake:
+			for _, id := range ampkv.orphans.take() {
+				ampkv.deleteCollection(id)
+			}
+		case <-ticker.C:
+			ampkv.sweepCollections()
+		}
+	}
+}
+
+func (ampkv *AmpKV) closing() bool {
+	select {
+	case <-ampkv.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// sweepCollections collects the elements of every collection that is no
+// longer referenced by the header it was created for. Collections without an
+// owner record are left alone, as there is no header to check them against.
+func (ampkv *AmpKV) sweepCollections() {
+	start := collectionPrefix
+	for !ampkv.closing() {
+		id, owner, ok := ampkv.nextCollection(start)
+		if !ok {
+			return
+		}
+		if owner != "" && !ampkv.ownsCollection(owner, id) {
+			ampkv.deleteCollection(id)
+		}
+		// ';' follows ':', so the sweep continues after the elements of id.
+		start = collectionPrefix + id + ";"
+	}
+}
+
+// nextCollection returns the id of the first collection with elements at or
+// after start, and the key of its header if its owner record comes first.
+func (ampkv *AmpKV) nextCollection(start string) (id, owner string, ok bool) {
+	it, err := ampkv.store.Iterate(storage.IteratorOptions{Prefix: collectionPrefix, Start: start})
+	if err != nil {
+		return "", "", false
+	}
+	defer it.Close()
+
+	if !it.Next() {
+		return "", "", false
+	}
+	id, suffix, _ := strings.Cut(it.Key()[len(collectionPrefix):], "::")
+	if suffix == "" {
+		if value, err := common.AmpKVValueFrom(it.Value()); err == nil {
+			owner = string(value.Data)
+		}
+	}
+	return id, owner, true
+}
+
+// ownsCollection reports whether the header stored under key belongs to the
+// collection id. It is read from the store, which the cache may lag behind.
+func (ampkv *AmpKV) ownsCollection(key, id string) bool {
+	rawVal, err := ampkv.store.Get(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	value, err := common.AmpKVValueFrom(rawVal)
+	if err != nil {
+		return true
+	}
+	current, ok := collectionID(value)
+	return ok && current == id
+}
+
+// deleteCollection deletes the elements of the collection id in chunks of
+// collectionGCChunkSize, and its owner record last so that an interrupted
+// run is picked up again by the next sweep.
+func (ampkv *AmpKV) deleteCollection(id string) error {
+	owner := collectionPrefix + id + "::"
+	for {
+		if ampkv.closing() {
+			return ErrClosed
+		}
+
+		keys, err := ampkv.elementChunk(owner)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			break
+		}
+		if err := ampkv.store.DeleteMany(keys); err != nil {
+			return err
+		}
+		for _, key := range keys {
+			ampkv.cache.Delete(key)
+		}
+	}
+
+	if err := ampkv.store.Delete(owner); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+	ampkv.cache.Delete(owner)
+	return nil
+}
+
+// elementChunk returns the keys of up to collectionGCChunkSize elements under
+// prefix, skipping the owner record stored under prefix itself.
+func (ampkv *AmpKV) elementChunk(prefix string) ([]string, error) {
+	it, err := ampkv.store.Iterate(storage.IteratorOptions{Prefix: prefix, Start: prefix + "\x00"})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	keys := make([]string, 0, collectionGCChunkSize)
+	for len(keys) < collectionGCChunkSize && it.Next() {
+		keys = append(keys, it.Key())
+	}
+	return keys, it.Err()
+}
@@ -0,0 +1,105 @@
+package embedded
+
+import (
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+// HSet sets field of the hash stored under key, creating it if needed, and
+// reports whether the field is new.
+func (ampkv *AmpKV) HSet(key, field string, value any) (bool, error) {
+	var created bool
+	err := ampkv.Update(func(tx *Txn) error {
+		hash, err := tx.collection(key, common.TypeHash)
+		if err != nil {
+			return err
+		}
+
+		fieldKey := hash.elementKey(hashField, field)
+		exists, err := tx.hasElement(fieldKey)
+		if err != nil {
+			return err
+		}
+		if err := tx.setElement(hash, fieldKey, value); err != nil {
+			return err
+		}
+		created = !exists
+		if created {
+			hash.count++
+		}
+		return tx.saveCollection(hash)
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+// HGet returns field of the hash stored under key, or ErrNotFound if either
+// does not exist.
+func (ampkv *AmpKV) HGet(key, field string) (*common.AmpKVValue, error) {
+	hash, err := ampkv.readCollection(key, common.TypeHash)
+	if err != nil {
+		return nil, err
+	}
+	if hash == nil {
+		return nil, ErrNotFound
+	}
+	return ampkv.Get(hash.elementKey(hashField, field))
+}
+
+// HDel removes fields from the hash stored under key and returns how many of
+// them existed.
+func (ampkv *AmpKV) HDel(key string, fields ...string) (int, error) {
+	var removed int
+	err := ampkv.Update(func(tx *Txn) error {
+		removed = 0
+		hash, err := tx.collection(key, common.TypeHash)
+		if err != nil {
+			return err
+		}
+
+		for _, field := range fields {
+			fieldKey := hash.elementKey(hashField, field)
+			exists, err := tx.hasElement(fieldKey)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				continue
+			}
+			if err := tx.Delete(fieldKey); err != nil {
+				return err
+			}
+			hash.count--
+			removed++
+		}
+		return tx.saveCollection(hash)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// HGetAll returns every field of the hash stored under key. A missing key is
+// an empty hash.
+func (ampkv *AmpKV) HGetAll(key string) (map[string]*common.AmpKVValue, error) {
+	hash, err := ampkv.readCollection(key, common.TypeHash)
+	if err != nil || hash == nil {
+		return nil, err
+	}
+
+	fields := make(map[string]*common.AmpKVValue, hash.count)
+	err = ampkv.elements(hash, hashField, "", func(field string, rawVal []byte) (bool, error) {
+		value, err := decodeValue(hash.elementKey(hashField, field), rawVal)
+		if err != nil {
+			return false, err
+		}
+		fields[field] = value
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,139 @@
+package embedded
+
+import (
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+// LPush prepends values to the list stored under key, creating it if needed,
+// and returns its new length. The last value ends up first.
+func (ampkv *AmpKV) LPush(key string, values ...any) (int64, error) {
+	return ampkv.push(key, true, values)
+}
+
+// RPush appends values to the list stored under key, creating it if needed,
+// and returns its new length.
+func (ampkv *AmpKV) RPush(key string, values ...any) (int64, error) {
+	return ampkv.push(key, false, values)
+}
+
+func (ampkv *AmpKV) push(key string, left bool, values []any) (int64, error) {
+	var length int64
+	err := ampkv.Update(func(tx *Txn) error {
+		list, err := tx.collection(key, common.TypeList)
+		if err != nil {
+			return err
+		}
+
+		for _, value := range values {
+			var index int64
+			if left {
+				list.head--
+				index = list.head
+			} else {
+				index = list.tail
+				list.tail++
+			}
+			if err := tx.setElement(list, list.elementKey(listElement, sortableIndex(index)), value); err != nil {
+				return err
+			}
+		}
+
+		length = list.len()
+		return tx.saveCollection(list)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return length, nil
+}
+
+// LPop removes and returns the first value of the list stored under key. It
+// fails with ErrNotFound if the list is empty.
+func (ampkv *AmpKV) LPop(key string) (*common.AmpKVValue, error) {
+	return ampkv.pop(key, true)
+}
+
+// RPop removes and returns the last value of the list stored under key.
+func (ampkv *AmpKV) RPop(key string) (*common.AmpKVValue, error) {
+	return ampkv.pop(key, false)
+}
+
+func (ampkv *AmpKV) pop(key string, left bool) (*common.AmpKVValue, error) {
+	var value *common.AmpKVValue
+	err := ampkv.Update(func(tx *Txn) error {
+		list, err := tx.collection(key, common.TypeList)
+		if err != nil {
+			return err
+		}
+		if list.len() == 0 {
+			return ErrNotFound
+		}
+
+		var index int64
+		if left {
+			index = list.head
+			list.head++
+		} else {
+			list.tail--
+			index = list.tail
+		}
+		elementKey := list.elementKey(listElement, sortableIndex(index))
+		if value, err = tx.Get(elementKey); err != nil {
+			return err
+		}
+		if err := tx.Delete(elementKey); err != nil {
+			return err
+		}
+		return tx.saveCollection(list)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// LRange returns the values of the list stored under key from start to stop,
+// both inclusive. Negative positions count from the end of the list, -1 being
+// the last value. A missing key is an empty list.
+func (ampkv *AmpKV) LRange(key string, start, stop int64) ([]*common.AmpKVValue, error) {
+	if ampkv.store.IsNil() {
+		return nil, errCollectionsUnsupported
+	}
+
+	var values []*common.AmpKVValue
+	err := ampkv.View(func(tx *Txn) error {
+		current, err := tx.currentValue(key)
+		if err != nil || current == nil {
+			return err
+		}
+		if current.Type != common.TypeList {
+			return errTypeMismatch(key, current.Type, common.TypeList)
+		}
+		list, err := decodeCollection(key, current)
+		if err != nil {
+			return err
+		}
+
+		length := list.len()
+		if start < 0 {
+			start = max(length+start, 0)
+		}
+		if stop < 0 {
+			stop = length + stop
+		}
+		stop = min(stop, length-1)
+
+		for i := start; i <= stop; i++ {
+			value, err := tx.Get(list.elementKey(listElement, sortableIndex(list.head+i)))
+			if err != nil {
+				return err
+			}
+			values = append(values, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
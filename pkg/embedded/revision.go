@@ -0,0 +1,148 @@
+package embedded
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+const (
+	revisionKey       = "internal::meta::revision"
+	revisionLeaseSize = 1000
+)
+
+// revisionAllocator hands out strictly increasing revision numbers. Only the
+// upper bound of the current lease is persisted, so a restart skips whatever
+// was left of the previous lease instead of reusing it.
+type revisionAllocator struct {
+	mu      sync.Mutex
+	store   storage.KVStore
+	current uint64
+	leased  uint64
+	// reserved counts the revisions that reservations may still take. The
+	// lease always covers them.
+	reserved uint64
+}
+
+func newRevisionAllocator(store storage.KVStore) (*revisionAllocator, error) {
+	allocator := &revisionAllocator{store: store}
+
+	rawVal, err := store.Get(revisionKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return allocator, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read revision lease: %w", err)
+	}
+
+	val, err := common.AmpKVValueFrom(rawVal)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode revision lease: %w", err)
+	}
+	leased, err := val.AsInt64()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode revision lease: %w", err)
+	}
+
+	allocator.current = uint64(leased)
+	allocator.leased = uint64(leased)
+	return allocator, nil
+}
+
+func (r *revisionAllocator) next() (uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.lease(1); err != nil {
+		return 0, err
+	}
+	r.current++
+	return r.current, nil
+}
+
+// lease persists a new lease if the current one can not cover n more
+// revisions besides the reserved ones. r.mu must be held.
+func (r *revisionAllocator) lease(n uint64) error {
+	needed := r.current + r.reserved + n
+	if needed <= r.leased {
+		return nil
+	}
+
+	leased := max(r.leased+revisionLeaseSize, needed)
+	val, err := common.NewAmpKVValue(int64(leased))
+	if err != nil {
+		return err
+	}
+	rawVal, err := val.ToByteSlice()
+	if err != nil {
+		return err
+	}
+	if err := r.store.Set(revisionKey, rawVal, 1); err != nil {
+		return fmt.Errorf("Failed to persist revision lease: %w", err)
+	}
+	r.leased = leased
+	return nil
+}
+
+// revisionReservation holds revisions that can be taken without persisting a
+// new lease, so that commitMu is never held across a write of the lease.
+// Revisions are still numbered when they are taken, in the order they are
+// taken.
+type revisionReservation struct {
+	allocator *revisionAllocator
+	n         uint64
+}
+
+// reserve makes sure that n revisions can be taken from the returned
+// reservation without writing to the store. Revisions that are not taken
+// must be given back with release.
+func (r *revisionAllocator) reserve(n int) (*revisionReservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.lease(uint64(n)); err != nil {
+		return nil, err
+	}
+	r.reserved += uint64(n)
+	return &revisionReservation{allocator: r, n: uint64(n)}, nil
+}
+
+// next takes the next revision, falling back to the allocator once the
+// reservation is used up.
+func (res *revisionReservation) next() (uint64, error) {
+	if res.n == 0 {
+		return res.allocator.next()
+	}
+
+	r := res.allocator
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res.n--
+	r.reserved--
+	r.current++
+	return r.current, nil
+}
+
+func (res *revisionReservation) release() {
+	r := res.allocator
+	r.mu.Lock()
+	r.reserved -= res.n
+	r.mu.Unlock()
+	res.n = 0
+}
+
+func (r *revisionAllocator) last() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Revision returns the latest revision handed out by this AmpKV. Every value
+// written afterwards carries a higher Version.
+func (ampkv *AmpKV) Revision() uint64 {
+	return ampkv.revisions.last()
+}
@@ -0,0 +1,63 @@
+package embedded
+
+import (
+	"fmt"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+const (
+	DefaultScanLimit = 100
+	MaxScanLimit     = 1000
+)
+
+type ScanItem struct {
+	Key   string
+	Value *common.AmpKVValue
+}
+
+// ScanResult holds one page of a Scan. NextCursor is empty once the prefix is
+// exhausted; otherwise it is passed as start to fetch the following page.
+type ScanResult struct {
+	Items      []ScanItem
+	NextCursor string
+}
+
+func (ampkv *AmpKV) Scan(prefix, start string, limit int) (*ScanResult, error) {
+	if ampkv.store.IsNil() {
+		return nil, fmt.Errorf("Scan requires a store driver: %w", ErrUnsupported)
+	}
+
+	if limit <= 0 {
+		limit = DefaultScanLimit
+	}
+	if limit > MaxScanLimit {
+		limit = MaxScanLimit
+	}
+
+	it, err := ampkv.store.Iterate(storage.IteratorOptions{Prefix: prefix, Start: start})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	result := &ScanResult{Items: make([]ScanItem, 0, limit)}
+	for it.Next() {
+		if len(result.Items) == limit {
+			result.NextCursor = it.Key()
+			break
+		}
+
+		value, err := common.AmpKVValueFrom(it.Value())
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode AmpKVValue for key '%s': %w", it.Key(), err)
+		}
+		result.Items = append(result.Items, ScanItem{Key: it.Key(), Value: value})
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,108 @@
+package embedded
+
+import (
+	"errors"
+
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+// SAdd adds members to the set stored under key, creating it if needed, and
+// returns how many of them were not members yet.
+func (ampkv *AmpKV) SAdd(key string, members ...string) (int, error) {
+	var added int
+	err := ampkv.Update(func(tx *Txn) error {
+		added = 0
+		set, err := tx.collection(key, common.TypeSet)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			memberKey := set.elementKey(setMember, member)
+			exists, err := tx.hasElement(memberKey)
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+			if err := tx.setElement(set, memberKey, ""); err != nil {
+				return err
+			}
+			set.count++
+			added++
+		}
+		return tx.saveCollection(set)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+// SRem removes members from the set stored under key and returns how many of
+// them were members.
+func (ampkv *AmpKV) SRem(key string, members ...string) (int, error) {
+	var removed int
+	err := ampkv.Update(func(tx *Txn) error {
+		removed = 0
+		set, err := tx.collection(key, common.TypeSet)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			memberKey := set.elementKey(setMember, member)
+			exists, err := tx.hasElement(memberKey)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				continue
+			}
+			if err := tx.Delete(memberKey); err != nil {
+				return err
+			}
+			set.count--
+			removed++
+		}
+		return tx.saveCollection(set)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// SIsMember reports whether member is in the set stored under key.
+func (ampkv *AmpKV) SIsMember(key, member string) (bool, error) {
+	set, err := ampkv.readCollection(key, common.TypeSet)
+	if err != nil || set == nil {
+		return false, err
+	}
+
+	_, err = ampkv.Get(set.elementKey(setMember, member))
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// SMembers returns the members of the set stored under key in byte order. A
+// missing key is an empty set.
+func (ampkv *AmpKV) SMembers(key string) ([]string, error) {
+	set, err := ampkv.readCollection(key, common.TypeSet)
+	if err != nil || set == nil {
+		return nil, err
+	}
+
+	members := make([]string, 0, set.count)
+	err = ampkv.elements(set, setMember, "", func(member string, _ []byte) (bool, error) {
+		members = append(members, member)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
@@ -0,0 +1,153 @@
+package embedded
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+// ZMember is a member of a sorted set together with its score.
+type ZMember struct {
+	Member string
+	Score  float64
+}
+
+// ZAdd adds members to the sorted set stored under key, creating it if
+// needed, or updates the score of those already in it. It returns how many
+// members were added.
+func (ampkv *AmpKV) ZAdd(key string, members ...ZMember) (int, error) {
+	for _, member := range members {
+		if math.IsNaN(member.Score) {
+			return 0, fmt.Errorf("%w: member '%s'", ErrInvalidScore, member.Member)
+		}
+	}
+
+	var added int
+	err := ampkv.Update(func(tx *Txn) error {
+		added = 0
+		zset, err := tx.collection(key, common.TypeSortedSet)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			removed, err := tx.removeScored(zset, member.Member)
+			if err != nil {
+				return err
+			}
+			if !removed {
+				zset.count++
+				added++
+			}
+
+			if err := tx.setElement(zset, zset.elementKey(sortedSetMember, member.Member), member.Score); err != nil {
+				return err
+			}
+			if err := tx.setElement(zset, zset.elementKey(sortedSetScore, sortableScore(member.Score)+member.Member), ""); err != nil {
+				return err
+			}
+		}
+		return tx.saveCollection(zset)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+// ZRem removes members from the sorted set stored under key and returns how
+// many of them were in it.
+func (ampkv *AmpKV) ZRem(key string, members ...string) (int, error) {
+	var removed int
+	err := ampkv.Update(func(tx *Txn) error {
+		removed = 0
+		zset, err := tx.collection(key, common.TypeSortedSet)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			ok, err := tx.removeScored(zset, member)
+			if err != nil {
+				return err
+			}
+			if ok {
+				zset.count--
+				removed++
+			}
+		}
+		return tx.saveCollection(zset)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// removeScored deletes member and its score index entry from zset and
+// reports whether it was there. The size of zset is left to the caller.
+func (tx *Txn) removeScored(zset *collection, member string) (bool, error) {
+	memberKey := zset.elementKey(sortedSetMember, member)
+	current, err := tx.currentValue(memberKey)
+	if err != nil || current == nil {
+		return false, err
+	}
+	score, err := current.AsFloat64()
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Delete(memberKey); err != nil {
+		return false, err
+	}
+	return true, tx.Delete(zset.elementKey(sortedSetScore, sortableScore(score)+member))
+}
+
+// ZScore returns the score of member in the sorted set stored under key, or
+// ErrNotFound if either does not exist.
+func (ampkv *AmpKV) ZScore(key, member string) (float64, error) {
+	zset, err := ampkv.readCollection(key, common.TypeSortedSet)
+	if err != nil {
+		return 0, err
+	}
+	if zset == nil {
+		return 0, ErrNotFound
+	}
+
+	value, err := ampkv.Get(zset.elementKey(sortedSetMember, member))
+	if err != nil {
+		return 0, err
+	}
+	return value.AsFloat64()
+}
+
+// ZRangeByScore returns up to limit members of the sorted set stored under
+// key with a score between minScore and maxScore, both inclusive, ordered by
+// score and then by member. A limit of zero or less returns every such member.
+func (ampkv *AmpKV) ZRangeByScore(key string, minScore, maxScore float64, limit int) ([]ZMember, error) {
+	zset, err := ampkv.readCollection(key, common.TypeSortedSet)
+	if err != nil || zset == nil {
+		return nil, err
+	}
+
+	var members []ZMember
+	err = ampkv.elements(zset, sortedSetScore, sortableScore(minScore), func(suffix string, _ []byte) (bool, error) {
+		if len(suffix) < sortableLen {
+			return false, fmt.Errorf("Failed to decode sorted set index of key '%s'", key)
+		}
+		score, err := parseSortableScore(suffix[:sortableLen])
+		if err != nil {
+			return false, fmt.Errorf("Failed to decode sorted set index of key '%s': %w", key, err)
+		}
+		if score > maxScore {
+			return false, nil
+		}
+		members = append(members, ZMember{Member: suffix[sortableLen:], Score: score})
+		return limit <= 0 || len(members) < limit, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
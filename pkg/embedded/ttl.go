@@ -0,0 +1,113 @@
+package embedded
+
+import (
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+// TTL returns how long key has left to live, or 0 if it does not expire.
+func (ampkv *AmpKV) TTL(key string) (time.Duration, error) {
+	value, err := ampkv.get(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var expiresAt time.Time
+	if value.ExpiresAt != nil {
+		expiresAt = *value.ExpiresAt
+	} else if reader, ok := ampkv.store.(storage.ExpiryReader); ok {
+		// Values written before expiry times were recorded only carry
+		// the TTL of the store entry.
+		if expiresAt, err = reader.ExpiresAt(key); err != nil {
+			return 0, err
+		}
+	}
+	if expiresAt.IsZero() {
+		return 0, nil
+	}
+
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return 0, ErrNotFound
+	}
+	return ttl, nil
+}
+
+// Expire sets the TTL of an existing key without changing its value. A ttl of
+// zero or less deletes the key.
+func (ampkv *AmpKV) Expire(key string, ttl time.Duration) error {
+	return ampkv.Update(func(tx *Txn) error {
+		return tx.Expire(key, ttl)
+	})
+}
+
+// ExpireAt lets an existing key expire at t. A t in the past deletes the key.
+func (ampkv *AmpKV) ExpireAt(key string, t time.Time) error {
+	return ampkv.Update(func(tx *Txn) error {
+		return tx.Expire(key, time.Until(t))
+	})
+}
+
+// Persist removes the TTL of an existing key.
+func (ampkv *AmpKV) Persist(key string) error {
+	return ampkv.Update(func(tx *Txn) error {
+		return tx.Persist(key)
+	})
+}
+
+// Expire sets the TTL of key. A ttl of zero or less deletes the key.
+func (tx *Txn) Expire(key string, ttl time.Duration) error {
+	if ttl <= 0 {
+		if _, err := tx.Get(key); err != nil {
+			return err
+		}
+		return tx.Delete(key)
+	}
+	return tx.retouch(key, ttl)
+}
+
+// Persist removes the TTL of key.
+func (tx *Txn) Persist(key string) error {
+	return tx.retouch(key, 0)
+}
+
+// retouch gives the store and cache entries of key a new ttl. Only the
+// expiry time recorded in the value changes; its data, Version and UpdatedAt
+// are kept and no EventPut is published.
+func (tx *Txn) retouch(key string, ttl time.Duration) error {
+	if tx.readOnly {
+		return ErrReadOnlyTxn
+	}
+
+	current, err := tx.Get(key)
+	if err != nil {
+		return err
+	}
+	value := *current
+
+	// The elements of a collection expire with its header and keep no TTL
+	// of their own.
+	tx.writes = append(tx.writes, txnWrite{key: key, value: &value, cost: tx.ampkv.defaultCost, ttl: ttl, retouch: true})
+	return nil
+}
+
+// cacheTTL returns the TTL of a value read from the store when it is put
+// into the cache: the default TTL, cut short by the value's own expiry. ok is
+// false for values that have already expired.
+func (ampkv *AmpKV) cacheTTL(value *common.AmpKVValue) (ttl time.Duration, ok bool) {
+	ttl = ampkv.defaultTTL
+	if value.ExpiresAt == nil {
+		return ttl, true
+	}
+
+	remaining := time.Until(*value.ExpiresAt)
+	if remaining <= 0 {
+		return 0, false
+	}
+	if ttl == 0 || remaining < ttl {
+		ttl = remaining
+	}
+	return ttl, true
+}
@@ -0,0 +1,366 @@
+package embedded
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Unfield/AmpKV/internal/storage"
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+const txnMaxAttempts uint8 = 10
+
+type txnWrite struct {
+	key     string
+	value   *common.AmpKVValue
+	raw     []byte
+	cost    int64
+	ttl     time.Duration
+	delete  bool
+	version uint64
+	// retouch marks a write that only changes the expiry of the value, so
+	// its Version and UpdatedAt are kept and it is not published.
+	retouch bool
+}
+
+// stamp sets the version and metadata of the value written. createdAt is
+// that of the value being replaced, zero if there is none.
+func (write *txnWrite) stamp(version uint64, createdAt, now time.Time) {
+	write.version = version
+	write.value.Version = version
+	if !write.retouch || write.value.UpdatedAt.IsZero() {
+		write.value.UpdatedAt = now
+	}
+	write.value.CreatedAt = createdAt
+	if createdAt.IsZero() {
+		write.value.CreatedAt = now
+	}
+	write.value.ExpiresAt = nil
+	if write.ttl > 0 {
+		expiresAt := now.Add(write.ttl)
+		write.value.ExpiresAt = &expiresAt
+	}
+}
+
+// Txn groups reads and writes against an AmpKV. Writes are buffered until fn
+// returns and are then committed atomically; the cache only sees them once the
+// commit has succeeded.
+type Txn struct {
+	ampkv      *AmpKV
+	txn        storage.Txn
+	readOnly   bool
+	writes     []txnWrite
+	committing bool
+	// orphans holds the ids of the collections whose header the
+	// transaction deletes or overwrites.
+	orphans   []string
+	revisions *revisionReservation
+}
+
+// Update runs fn inside a read-write transaction and commits it when fn
+// returns nil. Commits that conflict with a concurrent transaction are retried
+// by calling fn again, so fn must not have side effects outside of tx.
+//
+// In CacheOnly mode there is no store to provide isolation; transactions are
+// serialized against each other instead.
+func (ampkv *AmpKV) Update(fn func(tx *Txn) error) error {
+	_, err := ampkv.update(fn)
+	return err
+}
+
+func (ampkv *AmpKV) update(fn func(tx *Txn) error) (*Txn, error) {
+	if ampkv.store.IsNil() {
+		ampkv.txnMu.Lock()
+		defer ampkv.txnMu.Unlock()
+	}
+
+	for currentAttempt := range txnMaxAttempts {
+		tx := &Txn{ampkv: ampkv}
+		err := ampkv.store.Update(func(txn storage.Txn) error {
+			tx.txn = txn
+			if err := fn(tx); err != nil {
+				return err
+			}
+			var err error
+			if tx.revisions, err = ampkv.revisions.reserve(tx.revisionsNeeded()); err != nil {
+				return err
+			}
+			// Revisions are assigned under commitMu and the lock is held
+			// until the commit is queued for publishing, so revision order
+			// always matches commit order.
+			ampkv.commitMu.Lock()
+			tx.committing = true
+			return tx.flush()
+		})
+		if tx.revisions != nil {
+			tx.revisions.release()
+		}
+		if tx.committing {
+			var applyErr error
+			if err == nil {
+				ampkv.commits.push(func() {
+					applyErr = tx.applyToCache()
+					ampkv.publishWrites(tx.writes)
+					ampkv.orphans.add(tx.orphans...)
+				})
+			}
+			ampkv.commitMu.Unlock()
+			ampkv.commits.run()
+			if err == nil {
+				err = applyErr
+			}
+		}
+
+		if errors.Is(err, storage.ErrConflict) {
+			time.Sleep(time.Duration(1<<currentAttempt) * 5 * time.Millisecond)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return tx, nil
+	}
+
+	return nil, fmt.Errorf("Failed to commit transaction after %d attempts: %w", txnMaxAttempts, ErrConflict)
+}
+
+// commitQueue applies commits to the cache and publishes them after
+// commitMu has been released, in the order they were pushed under it.
+// Every committer runs the queue after pushing to it, so its own commit has
+// been applied once run returns.
+type commitQueue struct {
+	mu      sync.Mutex
+	pending []func()
+	running sync.Mutex
+}
+
+func (q *commitQueue) push(apply func()) {
+	q.mu.Lock()
+	q.pending = append(q.pending, apply)
+	q.mu.Unlock()
+}
+
+func (q *commitQueue) run() {
+	q.running.Lock()
+	defer q.running.Unlock()
+
+	q.mu.Lock()
+	pending := q.pending
+	q.pending = nil
+	q.mu.Unlock()
+
+	for _, apply := range pending {
+		apply()
+	}
+}
+
+// View runs fn inside a read-only transaction.
+func (ampkv *AmpKV) View(fn func(tx *Txn) error) error {
+	return ampkv.store.View(func(txn storage.Txn) error {
+		return fn(&Txn{ampkv: ampkv, txn: txn, readOnly: true})
+	})
+}
+
+// Get returns the value of key as seen by the transaction, including its own
+// uncommitted writes. Uncommitted values carry a Version of 0.
+func (tx *Txn) Get(key string) (*common.AmpKVValue, error) {
+	if write := tx.pendingWrite(key); write != nil {
+		if write.delete {
+			return nil, storage.ErrNotFound
+		}
+		pending := *write.value
+		return &pending, nil
+	}
+	return tx.committed(key)
+}
+
+// committed returns the value of key without the transaction's own writes.
+func (tx *Txn) committed(key string) (*common.AmpKVValue, error) {
+	rawVal, err := tx.committedRaw(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ampKVValue, err := common.AmpKVValueFrom(rawVal)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode AmpKVValue for key '%s': %w", key, err)
+	}
+	return ampKVValue, nil
+}
+
+func (tx *Txn) committedRaw(key string) ([]byte, error) {
+	if tx.ampkv.store.IsNil() {
+		return tx.ampkv.cache.Get(key)
+	}
+	return tx.txn.Get(key)
+}
+
+// replaced returns the value the i-th write replaces: that of an earlier
+// write of the key in tx, or else the committed one. It is nil if the key does
+// not exist.
+func (tx *Txn) replaced(i int) (*common.AmpKVValue, error) {
+	key := tx.writes[i].key
+	for j := i - 1; j >= 0; j-- {
+		if previous := tx.writes[j]; previous.key == key {
+			if previous.delete {
+				return nil, nil
+			}
+			return previous.value, nil
+		}
+	}
+
+	rawVal, err := tx.committedRaw(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// A value that can not be decoded is overwritten like a missing one.
+	current, err := common.AmpKVValueFrom(rawVal)
+	if err != nil {
+		return nil, nil
+	}
+	return current, nil
+}
+
+func (tx *Txn) Set(key string, value any, cost int64) error {
+	return tx.SetWithTTL(key, value, cost, 0)
+}
+
+func (tx *Txn) SetWithTTL(key string, value any, cost int64, ttl time.Duration) error {
+	if tx.readOnly {
+		return ErrReadOnlyTxn
+	}
+
+	ampKVData, err := common.NewAmpKVValue(value)
+	if err != nil {
+		return err
+	}
+	if ampKVData.Type.IsCollection() {
+		return ErrCollectionValue
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	tx.writes = append(tx.writes, txnWrite{key: key, value: ampKVData, cost: cost, ttl: ttl})
+	return nil
+}
+
+func (tx *Txn) Delete(key string) error {
+	if tx.readOnly {
+		return ErrReadOnlyTxn
+	}
+
+	tx.writes = append(tx.writes, txnWrite{key: key, delete: true})
+	return nil
+}
+
+func (tx *Txn) pendingWrite(key string) *txnWrite {
+	for i := len(tx.writes) - 1; i >= 0; i-- {
+		if tx.writes[i].key == key {
+			return &tx.writes[i]
+		}
+	}
+	return nil
+}
+
+// committedVersion returns the version assigned to the last write of key.
+func (tx *Txn) committedVersion(key string) uint64 {
+	if write := tx.pendingWrite(key); write != nil {
+		return write.version
+	}
+	return 0
+}
+
+// revisionsNeeded returns how many revisions flush takes at most.
+func (tx *Txn) revisionsNeeded() int {
+	n := 0
+	for _, write := range tx.writes {
+		if !isCollectionElement(write.key) {
+			n++
+		}
+	}
+	return n
+}
+
+func (tx *Txn) flush() error {
+	now := time.Now()
+	for i := range tx.writes {
+		previous, err := tx.replaced(i)
+		if err != nil {
+			return err
+		}
+		if id, ok := replacedCollection(previous, &tx.writes[i]); ok {
+			tx.orphans = append(tx.orphans, id)
+		}
+
+		write := &tx.writes[i]
+		var version uint64
+		switch {
+		case write.retouch && previous != nil:
+			version = previous.Version
+		case !isCollectionElement(write.key):
+			if version, err = tx.revisions.next(); err != nil {
+				return err
+			}
+		}
+		write.version = version
+
+		if write.delete {
+			if err := tx.txn.Delete(write.key); err != nil {
+				return fmt.Errorf("Failed to delete key in transaction: %w", err)
+			}
+			continue
+		}
+
+		var createdAt time.Time
+		if previous != nil {
+			createdAt = previous.CreatedAt
+		}
+		write.stamp(version, createdAt, now)
+		write.raw, err = write.value.ToByteSlice()
+		if err != nil {
+			return err
+		}
+
+		if write.ttl > 0 {
+			err = tx.txn.SetWithTTL(write.key, write.raw, write.ttl)
+		} else {
+			err = tx.txn.Set(write.key, write.raw)
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to set value in transaction: %w", err)
+		}
+	}
+	return nil
+}
+
+func (tx *Txn) applyToCache() error {
+	cacheOnly := tx.ampkv.store.IsNil()
+
+	for _, write := range tx.writes {
+		var err error
+		switch {
+		case write.delete:
+			err = tx.ampkv.cache.Delete(write.key)
+		case write.ttl > 0:
+			err = tx.ampkv.cache.SetWithTTL(write.key, write.raw, write.cost, write.ttl)
+		default:
+			err = tx.ampkv.cache.Set(write.key, write.raw, write.cost)
+		}
+		if err == nil {
+			continue
+		}
+		if cacheOnly {
+			return fmt.Errorf("Failed to apply transaction to Cache: %w", err)
+		}
+		// The store already holds the committed value; drop the cached copy
+		// rather than leaving a stale one behind.
+		tx.ampkv.cache.Delete(write.key)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package embedded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Unfield/AmpKV/pkg/common"
+)
+
+const (
+	DefaultWatchHistorySize = 1000
+	watchMaxQueuedEvents    = 10000
+
+	// internalPrefix is the same as auth.ReservedPrefix. Changes to keys
+	// under it, such as collection elements, audit entries and usage
+	// counters, are not published.
+	internalPrefix = "internal::"
+)
+
+var ErrCompacted = errors.New("requested revision is no longer available")
+
+type EventType uint8
+
+const (
+	EventPut EventType = iota + 1
+	EventDelete
+	EventExpire
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventPut:
+		return "Put"
+	case EventDelete:
+		return "Delete"
+	case EventExpire:
+		return "Expire"
+	default:
+		return fmt.Sprintf("EventType(%d)", t)
+	}
+}
+
+// WatchEvent describes a single change. Value is only set for EventPut.
+type WatchEvent struct {
+	Type     EventType
+	Key      string
+	Value    *common.AmpKVValue
+	Revision uint64
+}
+
+type WatchOptions struct {
+	// Prefix matches every key starting with the watched key instead of the
+	// key itself.
+	Prefix bool
+	// StartRevision replays retained events with a revision of at least
+	// StartRevision before streaming live ones. Zero streams live events only.
+	StartRevision uint64
+}
+
+// changeFeed keeps a bounded history of committed changes and fans them out
+// to active watchers. Events are published in revision order.
+type changeFeed struct {
+	mu            sync.Mutex
+	history       []WatchEvent
+	historySize   int
+	firstRevision uint64
+	watchers      map[*watcher]struct{}
+	closed        bool
+}
+
+type watcher struct {
+	key    string
+	prefix bool
+	out    chan WatchEvent
+	notify chan struct{}
+	done   chan struct{}
+
+	mu        sync.Mutex
+	queue     []WatchEvent
+	closeOnce sync.Once
+}
+
+func newChangeFeed(historySize int, firstRevision uint64) *changeFeed {
+	if historySize <= 0 {
+		historySize = DefaultWatchHistorySize
+	}
+	return &changeFeed{
+		historySize:   historySize,
+		firstRevision: firstRevision,
+		watchers:      make(map[*watcher]struct{}),
+	}
+}
+
+// Watch streams changes to key, or to every key under it when options.Prefix
+// is set. The returned channel is closed when ctx is done, when the AmpKV is
+// closed, or when the watcher falls too far behind; in the latter case the
+// caller can resume from the revision after the last event it received. Keys
+// under internal:: are never reported.
+func (ampkv *AmpKV) Watch(ctx context.Context, key string, options WatchOptions) (<-chan WatchEvent, error) {
+	return ampkv.feed.watch(ctx, key, options)
+}
+
+func (f *changeFeed) watch(ctx context.Context, key string, options WatchOptions) (<-chan WatchEvent, error) {
+	w := &watcher{
+		key:    key,
+		prefix: options.Prefix,
+		out:    make(chan WatchEvent),
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil, ErrClosed
+	}
+	if options.StartRevision > 0 {
+		if options.StartRevision < f.firstRevision {
+			f.mu.Unlock()
+			return nil, fmt.Errorf("revision %d: %w", options.StartRevision, ErrCompacted)
+		}
+		for _, event := range f.history {
+			if event.Revision >= options.StartRevision && w.matches(event.Key) {
+				w.queue = append(w.queue, event)
+			}
+		}
+	}
+	f.watchers[w] = struct{}{}
+	f.mu.Unlock()
+
+	go w.pump(ctx)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-w.done:
+		}
+		f.remove(w)
+	}()
+
+	return w.out, nil
+}
+
+func (f *changeFeed) publish(events []WatchEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.history = append(f.history, events...)
+	if overflow := len(f.history) - f.historySize; overflow > 0 {
+		f.firstRevision = f.history[overflow].Revision
+		f.history = append(f.history[:0:0], f.history[overflow:]...)
+	}
+
+	for w := range f.watchers {
+		for _, event := range events {
+			if w.matches(event.Key) {
+				w.enqueue(event)
+			}
+		}
+	}
+}
+
+func (f *changeFeed) remove(w *watcher) {
+	f.mu.Lock()
+	delete(f.watchers, w)
+	f.mu.Unlock()
+	w.stop()
+}
+
+func (f *changeFeed) close() {
+	f.mu.Lock()
+	f.closed = true
+	watchers := f.watchers
+	f.watchers = make(map[*watcher]struct{})
+	f.mu.Unlock()
+
+	for w := range watchers {
+		w.stop()
+	}
+}
+
+func (w *watcher) matches(key string) bool {
+	if w.prefix {
+		return strings.HasPrefix(key, w.key)
+	}
+	return key == w.key
+}
+
+func (w *watcher) enqueue(event WatchEvent) {
+	w.mu.Lock()
+	if len(w.queue) >= watchMaxQueuedEvents {
+		w.mu.Unlock()
+		w.stop()
+		return
+	}
+	w.queue = append(w.queue, event)
+	w.mu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (w *watcher) stop() {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+}
+
+func (w *watcher) pump(ctx context.Context) {
+	defer close(w.out)
+
+	for {
+		w.mu.Lock()
+		queue := w.queue
+		w.queue = nil
+		w.mu.Unlock()
+
+		for _, event := range queue {
+			select {
+			case w.out <- event:
+			case <-ctx.Done():
+				return
+			case <-w.done:
+				return
+			}
+		}
+
+		select {
+		case <-w.notify:
+		case <-ctx.Done():
+			return
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (ampkv *AmpKV) publishWrites(writes []txnWrite) {
+	events := make([]WatchEvent, 0, len(writes))
+	for _, write := range writes {
+		if isInternalKey(write.key) {
+			continue
+		}
+		if write.delete {
+			ampkv.expiry.forget(write.key)
+			events = append(events, WatchEvent{Type: EventDelete, Key: write.key, Revision: write.version})
+			continue
+		}
+
+		if write.ttl > 0 {
+			ampkv.expiry.track(write.key, write.version, write.ttl)
+		} else {
+			ampkv.expiry.forget(write.key)
+		}
+		if write.retouch {
+			continue
+		}
+		events = append(events, WatchEvent{Type: EventPut, Key: write.key, Value: write.value, Revision: write.version})
+	}
+	ampkv.feed.publish(events)
+}
+
+func isInternalKey(key string) bool {
+	return strings.HasPrefix(key, internalPrefix)
+}
@@ -0,0 +1,546 @@
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Unfield/AmpKV/pkg/client"
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"github.com/Unfield/AmpKV/pkg/common"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultAsyncQueueSize = 1024
+
+	watchRetryBackoff    = 100 * time.Millisecond
+	watchMaxRetryBackoff = 5 * time.Second
+)
+
+type WriteMode uint8
+
+const (
+	// WriteSync writes to the server first and only updates the local copy
+	// once the server accepted the write.
+	WriteSync WriteMode = iota
+	// WriteAsync updates the local copy immediately and propagates the write
+	// to the server in the background, in the order the writes were made.
+	WriteAsync
+)
+
+func (m WriteMode) ToString() string {
+	switch m {
+	case WriteSync:
+		return "Sync"
+	case WriteAsync:
+		return "Async"
+	default:
+		return fmt.Sprintf("Unknown WriteMode: %d", m)
+	}
+}
+
+type ReplicaOptions struct {
+	WriteMode WriteMode
+	// AsyncQueueSize bounds the number of writes waiting to be propagated in
+	// WriteAsync mode. Writes block while the queue is full.
+	AsyncQueueSize int
+	// CacheTTL is applied to values copied from the server on a local miss.
+	// Zero keeps them until they are invalidated or evicted.
+	CacheTTL time.Duration
+	// OnError is called with errors that cannot be returned to a caller, such
+	// as failed asynchronous writes and change stream interruptions.
+	OnError func(err error)
+}
+
+// Replica serves reads from a local embedded.AmpKV and falls back to a remote
+// AmpKV server on a miss. Writes go to both; changes made on the server by
+// other clients invalidate the local copy through the server's change stream.
+//
+// Invalidation is asynchronous, so reads may briefly return a value that was
+// already overwritten on the server. Versions of values served from the local
+// copy are local revisions and can not be used for conditional writes against
+// the server.
+type Replica struct {
+	local   *embedded.AmpKV
+	remote  *client.Client
+	options ReplicaOptions
+
+	// fills tracks in-flight copies from the server so an invalidation that
+	// arrives while a copy is being fetched keeps it from being stored.
+	// writes tracks the Replica's own writes so that their events, and older
+	// ones, do not invalidate the newer local copy.
+	mu     sync.Mutex
+	fills  map[string]*fill
+	writes map[string]*ownWrite
+
+	// resetRevision marks every local value with a Version up to it as stale.
+	// It is raised when changes on the server may have been missed.
+	resetRevision atomic.Uint64
+
+	queueMu sync.RWMutex
+	queue   chan asyncWrite
+	closed  bool
+
+	ready     chan struct{}
+	readyOnce sync.Once
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+type fill struct {
+	refs  int
+	stale bool
+}
+
+// ownWrite tracks the writes of a key made through the Replica.
+type ownWrite struct {
+	// pending counts writes sent to the server, or queued for it, whose
+	// revision is not known yet.
+	pending int
+	// revision is the highest revision of the completed writes.
+	revision uint64
+	// seen is the highest revision of the events received for the key.
+	seen uint64
+}
+
+type asyncWrite struct {
+	key    string
+	value  any
+	cost   int64
+	ttl    time.Duration
+	delete bool
+	done   chan struct{}
+}
+
+// NewReplica composes local and remote into a Replica. The Replica takes
+// ownership of both and closes them on Close.
+func NewReplica(local *embedded.AmpKV, remote *client.Client, options ReplicaOptions) (*Replica, error) {
+	if local == nil || remote == nil {
+		return nil, fmt.Errorf("Failed to create replica: local and remote must not be nil")
+	}
+	if options.WriteMode != WriteSync && options.WriteMode != WriteAsync {
+		return nil, fmt.Errorf("Failed to create replica: unknown write mode: %s", options.WriteMode.ToString())
+	}
+	if options.AsyncQueueSize <= 0 {
+		options.AsyncQueueSize = DefaultAsyncQueueSize
+	}
+	if options.CacheTTL < 0 {
+		options.CacheTTL = 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Replica{
+		local:   local,
+		remote:  remote,
+		options: options,
+		fills:   make(map[string]*fill),
+		writes:  make(map[string]*ownWrite),
+		ready:   make(chan struct{}),
+		cancel:  cancel,
+	}
+
+	r.wg.Add(1)
+	go r.runInvalidation(ctx)
+
+	if options.WriteMode == WriteAsync {
+		r.queue = make(chan asyncWrite, options.AsyncQueueSize)
+		r.wg.Add(1)
+		go r.runAsyncWrites()
+	}
+
+	return r, nil
+}
+
+// WaitForReady blocks until the change stream from the server is established,
+// after which every change made on the server invalidates the local copy.
+func (r *Replica) WaitForReady(ctx context.Context) error {
+	select {
+	case <-r.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Replica) Get(key string) (*common.AmpKVValue, error) {
+	val, err := r.local.Get(key)
+	if err == nil {
+		if val.Version > r.resetRevision.Load() {
+			return val, nil
+		}
+		r.local.CompareAndDelete(key, val.Version)
+	} else if !errors.Is(err, embedded.ErrNotFound) {
+		return nil, err
+	}
+
+	r.beginFill(key)
+	val, err = r.remote.Get(key)
+	r.endFill(key, val, err)
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+func (r *Replica) Set(key string, value any, cost int64) error {
+	return r.SetWithTTL(key, value, cost, 0)
+}
+
+func (r *Replica) SetWithTTL(key string, value any, cost int64, ttl time.Duration) error {
+	r.beginWrite(key)
+
+	if r.options.WriteMode == WriteAsync {
+		if err := r.local.SetWithTTL(key, value, cost, ttl); err != nil {
+			r.endWrite(key, 0, nil)
+			return err
+		}
+		return r.enqueueWrite(asyncWrite{key: key, value: value, cost: cost, ttl: ttl})
+	}
+
+	revision, err := r.remoteSet(key, value, cost, ttl)
+	if err != nil {
+		r.endWrite(key, 0, nil)
+		return err
+	}
+	r.endWrite(key, revision, func() error {
+		return r.local.SetWithTTL(key, value, cost, ttl)
+	})
+	return nil
+}
+
+func (r *Replica) Delete(key string) error {
+	if r.options.WriteMode == WriteAsync {
+		r.beginWrite(key)
+		if err := r.local.Delete(key); err != nil {
+			r.endWrite(key, 0, nil)
+			return err
+		}
+		return r.enqueueWrite(asyncWrite{key: key, delete: true})
+	}
+
+	if err := r.remote.Delete(key); err != nil {
+		return err
+	}
+	return r.local.Delete(key)
+}
+
+// Flush blocks until every asynchronous write made before the call has been
+// propagated to the server. It returns immediately in WriteSync mode.
+func (r *Replica) Flush(ctx context.Context) error {
+	if r.options.WriteMode != WriteAsync {
+		return nil
+	}
+
+	done := make(chan struct{})
+	if err := r.enqueue(asyncWrite{done: done}); err != nil {
+		return err
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close propagates pending asynchronous writes, stops the change stream and
+// closes the local store and the remote client.
+func (r *Replica) Close() error {
+	r.closeOnce.Do(func() {
+		r.queueMu.Lock()
+		r.closed = true
+		if r.queue != nil {
+			close(r.queue)
+		}
+		r.queueMu.Unlock()
+
+		r.cancel()
+		r.wg.Wait()
+	})
+
+	localErr := r.local.Close()
+	remoteErr := r.remote.Close()
+	if localErr != nil {
+		return fmt.Errorf("Failed to close local AmpKV: %w", localErr)
+	}
+	if remoteErr != nil {
+		return fmt.Errorf("Failed to close remote client: %w", remoteErr)
+	}
+	return nil
+}
+
+// remoteSet writes key to the server and returns the revision of the write.
+func (r *Replica) remoteSet(key string, value any, cost int64, ttl time.Duration) (uint64, error) {
+	versions, err := r.remote.SetMany([]embedded.BatchItem{{Key: key, Value: value, Cost: cost, TTL: ttl}})
+	if err != nil {
+		return 0, err
+	}
+	if len(versions) != 1 {
+		return 0, fmt.Errorf("Failed to set key '%s': expected 1 version, got %d", key, len(versions))
+	}
+	return versions[0], nil
+}
+
+// beginWrite registers a write of key before it changes the local copy or
+// reaches the server. Copies of key that are being fetched are older and are
+// not stored.
+func (r *Replica) beginWrite(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, ok := r.writes[key]
+	if !ok {
+		w = &ownWrite{}
+		r.writes[key] = w
+	}
+	w.pending++
+	if f, ok := r.fills[key]; ok {
+		f.stale = true
+	}
+}
+
+// endWrite completes a write registered with beginWrite. revision is zero if
+// the write failed or its revision is unknown. apply stores a WriteSync write
+// locally; it is skipped, and key invalidated, if the server already has a
+// newer value.
+func (r *Replica) endWrite(key string, revision uint64, apply func() error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w := r.writes[key]
+	w.pending--
+	newest := revision != 0 && revision >= w.revision && revision >= w.seen
+	w.revision = max(w.revision, revision)
+
+	switch {
+	case apply != nil:
+		if !newest || apply() != nil {
+			r.invalidateLocked(key)
+		}
+	case w.pending == 0 && w.seen > w.revision:
+		// Someone else wrote key after our last queued write reached the
+		// server.
+		r.invalidateLocked(key)
+	}
+
+	if w.pending == 0 && w.seen >= w.revision {
+		delete(r.writes, key)
+	}
+}
+
+func (r *Replica) beginFill(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, ok := r.fills[key]
+	if !ok {
+		f = &fill{}
+		r.fills[key] = f
+	}
+	f.refs++
+}
+
+// endFill stores val locally unless key was invalidated since beginFill.
+func (r *Replica) endFill(key string, val *common.AmpKVValue, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := r.fills[key]
+	if err == nil && !f.stale {
+		if r.options.CacheTTL > 0 {
+			r.local.SetWithTTL(key, val, 1, r.options.CacheTTL)
+		} else {
+			r.local.Set(key, val, 1)
+		}
+	}
+
+	f.refs--
+	if f.refs == 0 {
+		delete(r.fills, key)
+	}
+}
+
+// invalidate handles a change of key on the server at revision. Changes made
+// through the Replica, and older ones, are skipped while its writes of key
+// are pending or once they are newer.
+func (r *Replica) invalidate(key string, revision uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if w, ok := r.writes[key]; ok {
+		w.seen = max(w.seen, revision)
+		if w.pending > 0 || revision < w.revision {
+			return
+		}
+		delete(r.writes, key)
+		if revision == w.revision {
+			return
+		}
+	}
+	r.invalidateLocked(key)
+}
+
+func (r *Replica) invalidateLocked(key string) {
+	if f, ok := r.fills[key]; ok {
+		f.stale = true
+	}
+	r.local.Delete(key)
+}
+
+// discardWrite drops the local copy of a write the server never saw so reads
+// go back to the server, unless a later write of key is still queued.
+func (r *Replica) discardWrite(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if w, ok := r.writes[key]; ok && w.pending > 0 {
+		return
+	}
+	r.invalidateLocked(key)
+}
+
+// invalidateAll marks every local value as stale and aborts in-flight fills.
+func (r *Replica) invalidateAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, f := range r.fills {
+		f.stale = true
+	}
+	// Changes that were missed may be newer than pending writes; the others
+	// no longer have events to wait for.
+	for key, w := range r.writes {
+		if w.pending > 0 {
+			w.seen = math.MaxUint64
+		} else {
+			delete(r.writes, key)
+		}
+	}
+	r.resetRevision.Store(r.local.Revision())
+}
+
+// enqueueWrite queues a write registered with beginWrite.
+func (r *Replica) enqueueWrite(write asyncWrite) error {
+	if err := r.enqueue(write); err != nil {
+		r.endWrite(write.key, 0, nil)
+		return err
+	}
+	return nil
+}
+
+func (r *Replica) enqueue(write asyncWrite) error {
+	r.queueMu.RLock()
+	defer r.queueMu.RUnlock()
+
+	if r.closed {
+		return embedded.ErrClosed
+	}
+	r.queue <- write
+	return nil
+}
+
+func (r *Replica) runAsyncWrites() {
+	defer r.wg.Done()
+
+	for write := range r.queue {
+		if write.done != nil {
+			close(write.done)
+			continue
+		}
+
+		var (
+			revision uint64
+			err      error
+		)
+		if write.delete {
+			err = r.remote.Delete(write.key)
+		} else {
+			revision, err = r.remoteSet(write.key, write.value, write.cost, write.ttl)
+		}
+		r.endWrite(write.key, revision, nil)
+		if err != nil {
+			r.discardWrite(write.key)
+			r.reportError(fmt.Errorf("Failed to propagate write of key '%s': %w", write.key, err))
+		}
+	}
+}
+
+func (r *Replica) runInvalidation(ctx context.Context) {
+	defer r.wg.Done()
+
+	var (
+		nextRevision uint64
+		attempted    bool
+		backoff      = watchRetryBackoff
+	)
+	for {
+		// Without a revision to resume from, anything that changed while the
+		// stream was down is unknown.
+		if attempted && nextRevision == 0 {
+			r.invalidateAll()
+		}
+
+		received, err := r.watch(ctx, &nextRevision)
+		if ctx.Err() != nil {
+			return
+		}
+		attempted = true
+		if received {
+			backoff = watchRetryBackoff
+		}
+		if status.Code(err) == codes.OutOfRange {
+			nextRevision = 0
+		}
+		r.reportError(fmt.Errorf("Change stream interrupted: %w", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, watchMaxRetryBackoff)
+	}
+}
+
+// watch invalidates local keys until the change stream ends. It reports
+// whether the stream was established.
+func (r *Replica) watch(ctx context.Context, nextRevision *uint64) (bool, error) {
+	stream, err := r.remote.RPC().Watch(ctx, &pb.WatchRequest{Prefix: true, StartRevision: *nextRevision})
+	if err != nil {
+		return false, err
+	}
+	// The server sends headers once the watch is registered. Without them the
+	// stream failed and Recv returns why.
+	if header, err := stream.Header(); err != nil || header == nil {
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		return false, err
+	}
+	r.readyOnce.Do(func() { close(r.ready) })
+
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			return true, err
+		}
+		if res.Kv != nil {
+			r.invalidate(res.Kv.Key, res.Revision)
+		}
+		*nextRevision = res.Revision + 1
+	}
+}
+
+func (r *Replica) reportError(err error) {
+	if r.options.OnError != nil {
+		r.options.OnError(err)
+	}
+}
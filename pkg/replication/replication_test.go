@@ -0,0 +1,307 @@
+package replication_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Unfield/AmpKV/drivers/cache/ristretto"
+	"github.com/Unfield/AmpKV/internal/server"
+	"github.com/Unfield/AmpKV/pkg/client"
+	pb "github.com/Unfield/AmpKV/pkg/client/rpc"
+	"github.com/Unfield/AmpKV/pkg/embedded"
+	"github.com/Unfield/AmpKV/pkg/replication"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newCacheOnlyAmpKV(t *testing.T) *embedded.AmpKV {
+	cache, err := ristretto.NewRistrettoCache(1e4, 1<<20, 64)
+	if err != nil {
+		t.Fatalf("Failed to initialize Cache: %v", err)
+	}
+	ampkv, err := embedded.NewAmpKV(cache, nil, embedded.AmpKVOptions{Mode: embedded.AmpKVStorageModeCacheOnly})
+	if err != nil {
+		t.Fatalf("Failed to initialize AmpKV: %v", err)
+	}
+	return ampkv
+}
+
+// setupReplica starts an in-process server and returns its AmpKV together
+// with a ready Replica connected to it.
+func setupReplica(t *testing.T, options replication.ReplicaOptions, serverOptions ...grpc.ServerOption) (*embedded.AmpKV, *replication.Replica) {
+	remote := newCacheOnlyAmpKV(t)
+	t.Cleanup(func() { remote.Close() })
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(serverOptions...)
+	pb.RegisterAmpKVServiceServer(s, server.NewAmpKVGrpcServer(remote))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	c, err := client.NewClient("passthrough:///bufnet", client.ClientOptions{
+		DialOptions: []grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	replica, err := replication.NewReplica(newCacheOnlyAmpKV(t), c, options)
+	if err != nil {
+		t.Fatalf("Failed to create replica: %v", err)
+	}
+	t.Cleanup(func() { replica.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := replica.WaitForReady(ctx); err != nil {
+		t.Fatalf("Replica did not become ready: %v", err)
+	}
+	return remote, replica
+}
+
+func stringValue(t *testing.T, replica *replication.Replica, key string) string {
+	t.Helper()
+	val, err := replica.Get(key)
+	if err != nil {
+		t.Fatalf("Get '%s' failed: %v", key, err)
+	}
+	s, err := val.AsString()
+	if err != nil {
+		t.Fatalf("Failed to decode '%s': %v", key, err)
+	}
+	return s
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// syncChangeStream waits until the Replica processed every change made on
+// the server so far.
+func syncChangeStream(t *testing.T, remote *embedded.AmpKV, replica *replication.Replica) {
+	t.Helper()
+	marker := "marker-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := remote.Set(marker, "old", 1); err != nil {
+		t.Fatalf("Remote Set failed: %v", err)
+	}
+	stringValue(t, replica, marker)
+	if err := remote.Set(marker, "new", 1); err != nil {
+		t.Fatalf("Remote Set failed: %v", err)
+	}
+	waitFor(t, func() bool { return stringValue(t, replica, marker) == "new" })
+}
+
+// interceptMethods calls fn before every call of the unary methods names.
+func interceptMethods(fn func(), names ...string) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if slices.Contains(names, info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]) {
+			fn()
+		}
+		return handler(ctx, req)
+	})
+}
+
+// gatedStream calls gate before sending each watch event.
+type gatedStream struct {
+	grpc.ServerStream
+	gate func(res *pb.WatchResponse)
+}
+
+func (s gatedStream) SendMsg(m any) error {
+	if res, ok := m.(*pb.WatchResponse); ok {
+		s.gate(res)
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+func gateWatchEvents(gate func(res *pb.WatchResponse)) grpc.ServerOption {
+	return grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, gatedStream{ServerStream: ss, gate: gate})
+	})
+}
+
+func TestReplicaSync(t *testing.T) {
+	remote, replica := setupReplica(t, replication.ReplicaOptions{})
+
+	t.Run("Falls back to the server on a local miss", func(t *testing.T) {
+		if err := remote.Set("remote-key", "from server", 1); err != nil {
+			t.Fatalf("Remote Set failed: %v", err)
+		}
+		if got := stringValue(t, replica, "remote-key"); got != "from server" {
+			t.Errorf("Expected 'from server', got '%s'", got)
+		}
+		if _, err := replica.Get("missing"); !errors.Is(err, embedded.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Writes reach the server before returning", func(t *testing.T) {
+		if err := replica.Set("written", "by replica", 1); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		val, err := remote.Get("written")
+		if err != nil {
+			t.Fatalf("Remote Get failed: %v", err)
+		}
+		if s, _ := val.AsString(); s != "by replica" {
+			t.Errorf("Expected 'by replica' on the server, got '%s'", s)
+		}
+
+		if err := replica.Delete("written"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if _, err := remote.Get("written"); !errors.Is(err, embedded.ErrNotFound) {
+			t.Errorf("Expected key to be deleted on the server, got %v", err)
+		}
+	})
+
+	t.Run("Server changes invalidate the local copy", func(t *testing.T) {
+		if err := remote.Set("shared", "v1", 1); err != nil {
+			t.Fatalf("Remote Set failed: %v", err)
+		}
+		if got := stringValue(t, replica, "shared"); got != "v1" {
+			t.Fatalf("Expected 'v1', got '%s'", got)
+		}
+
+		if err := remote.Set("shared", "v2", 1); err != nil {
+			t.Fatalf("Remote Set failed: %v", err)
+		}
+		waitFor(t, func() bool { return stringValue(t, replica, "shared") == "v2" })
+
+		if err := remote.Delete("shared"); err != nil {
+			t.Fatalf("Remote Delete failed: %v", err)
+		}
+		waitFor(t, func() bool {
+			_, err := replica.Get("shared")
+			return errors.Is(err, embedded.ErrNotFound)
+		})
+	})
+}
+
+func TestReplicaOwnWrites(t *testing.T) {
+	var remoteGets atomic.Int32
+	// Hold back the events of our writes until both returned, so they
+	// arrive after the local copy was updated.
+	release := make(chan struct{})
+	remote, replica := setupReplica(t, replication.ReplicaOptions{},
+		interceptMethods(func() { remoteGets.Add(1) }, "Get"),
+		gateWatchEvents(func(res *pb.WatchResponse) {
+			if res.Kv.GetKey() == "own" {
+				<-release
+			}
+		}),
+	)
+
+	if err := replica.Set("own", "v1", 1); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := replica.Set("own", "v2", 1); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	close(release)
+	syncChangeStream(t, remote, replica)
+
+	gets := remoteGets.Load()
+	if got := stringValue(t, replica, "own"); got != "v2" {
+		t.Errorf("Expected 'v2', got '%s'", got)
+	}
+	if remoteGets.Load() != gets {
+		t.Error("Expected the events of our own writes to keep the local copy")
+	}
+
+	if err := remote.Set("own", "v3", 1); err != nil {
+		t.Fatalf("Remote Set failed: %v", err)
+	}
+	waitFor(t, func() bool { return stringValue(t, replica, "own") == "v3" })
+}
+
+func TestReplicaAsyncOwnWrites(t *testing.T) {
+	release := make(chan struct{})
+	var blocked sync.Once
+	remote, replica := setupReplica(t, replication.ReplicaOptions{WriteMode: replication.WriteAsync}, interceptMethods(func() {
+		blocked.Do(func() { <-release })
+	}, "Set", "MultiSet"))
+
+	if err := replica.Set("queued", "local", 1); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	// An older value written on the server while ours is still queued must
+	// not replace it locally.
+	if err := remote.Set("queued", "server", 1); err != nil {
+		t.Fatalf("Remote Set failed: %v", err)
+	}
+	syncChangeStream(t, remote, replica)
+	if got := stringValue(t, replica, "queued"); got != "local" {
+		t.Errorf("Expected the queued value 'local', got '%s'", got)
+	}
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := replica.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	syncChangeStream(t, remote, replica)
+
+	val, err := remote.Get("queued")
+	if err != nil {
+		t.Fatalf("Remote Get failed: %v", err)
+	}
+	if s, _ := val.AsString(); s != "local" {
+		t.Errorf("Expected 'local' on the server, got '%s'", s)
+	}
+	if got := stringValue(t, replica, "queued"); got != "local" {
+		t.Errorf("Expected 'local', got '%s'", got)
+	}
+}
+
+func TestReplicaAsync(t *testing.T) {
+	remote, replica := setupReplica(t, replication.ReplicaOptions{WriteMode: replication.WriteAsync})
+
+	for _, key := range []string{"a", "b", "c"} {
+		if err := replica.Set(key, "value-"+key, 1); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	if err := replica.Delete("b"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := replica.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	for key, want := range map[string]string{"a": "value-a", "c": "value-c"} {
+		val, err := remote.Get(key)
+		if err != nil {
+			t.Fatalf("Remote Get '%s' failed: %v", key, err)
+		}
+		if s, _ := val.AsString(); s != want {
+			t.Errorf("Expected '%s' on the server, got '%s'", want, s)
+		}
+	}
+	if _, err := remote.Get("b"); !errors.Is(err, embedded.ErrNotFound) {
+		t.Errorf("Expected 'b' to be deleted on the server, got %v", err)
+	}
+}